@@ -19,12 +19,15 @@ import "github.com/alecthomas/kingpin/v2"
 var (
 	Cmd = kingpin.Command("backup", "")
 
-	_ = Cmd.Command("incremental", "Make an incremental backup.")
-	_ = Cmd.Command("snapshot", "Make a snapshot backup.")
-	_ = Cmd.Command("run", "Make incremental and snapshot backups on a schedule. (Foreground Daemon)")
+	_      = Cmd.Command("incremental", "Make an incremental backup.")
+	_      = Cmd.Command("snapshot", "Make a snapshot backup.")
+	RunCmd = Cmd.Command("run", "Make incremental and snapshot backups on a schedule. (Foreground Daemon)")
 
 	overrideCluster    = Cmd.Flag("cluster", "Override cluster name when storing backups.").String()
 	overrideHostname   = Cmd.Flag("hostname", "Override hostname when storing backups.").String()
 	noCleanIncremental = Cmd.Flag("no-clean-incremental", "Do not clean up incremental backup files.").Bool()
 	verboseClean       = Cmd.Flag("verbose-clean", "Log incremental backup files that are or would be removed.").Bool()
+	uploadConcurrency  = Cmd.Flag("upload-concurrency", "Number of files to upload at once.").Default("2").Int()
+	hashConcurrency    = Cmd.Flag("hash-concurrency", "Number of files to compute digests of at once, for files not in the digest cache.").Default("2").Int()
+	schemaBackup       = Cmd.Flag("schema", "Back up the CQL schema with each snapshot.").Default("true").Bool()
 )
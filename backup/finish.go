@@ -20,6 +20,7 @@ import (
 	"github.com/retailnext/cassandrabackup/bucket"
 	"github.com/retailnext/cassandrabackup/digest"
 	"github.com/retailnext/cassandrabackup/manifests"
+	"github.com/retailnext/cassandrabackup/unixtime"
 	"go.uber.org/zap"
 )
 
@@ -32,6 +33,7 @@ func (p *processor) finish() error {
 	}()
 
 	p.manifest.DataFiles = make(map[string]digest.ForRestore)
+	p.manifest.FileInfo = make(map[string]manifests.FileInfo)
 	var hadFailures bool
 	var prospectError, uploadError error
 	for {
@@ -66,6 +68,13 @@ func (p *processor) finish() error {
 			lgr.Panicw("duplicate_manifest_path", "record", record)
 		}
 		p.manifest.DataFiles[record.ManifestPath] = record.Digests.ForRestore()
+		sstable, _ := manifests.ParseSSTableName(record.ManifestPath)
+		p.manifest.FileInfo[record.ManifestPath] = manifests.FileInfo{
+			Size:     record.File.Len(),
+			BlobSize: record.Digests.PartDigests().TotalLength(),
+			MTime:    unixtime.Seconds(record.File.ModTime().Unix()),
+			SSTable:  sstable,
+		}
 		p.cleanupHandler.MarkUploadSuccess(record.File)
 	}
 
@@ -80,7 +89,8 @@ func (p *processor) finish() error {
 			p.cleanupHandler.MarkManifestUploadFailure()
 			return err
 		} else {
-			lgr.Infow("put_manifest", "type", p.manifest.ManifestType, "files", len(p.manifest.DataFiles))
+			files, bytes, _ := p.manifest.Totals()
+			lgr.Infow("put_manifest", "type", p.manifest.ManifestType, "files", files, "bytes", bytes)
 			p.cleanupHandler.MarkManifestUploadSuccess()
 		}
 	} else {
@@ -18,6 +18,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/retailnext/cassandrabackup/paranoid"
 	"go.uber.org/zap"
@@ -36,16 +37,48 @@ func (p *processor) prospect() {
 		return
 	}
 
+	// Digests of files missing from the cache are computed by a pool of workers, so that reading
+	// many new files isn't done one at a time ahead of the uploads.
+	toHash := make(chan fileRecord)
+	var wg sync.WaitGroup
+	for range max(*hashConcurrency, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.hashFiles(toHash)
+		}()
+	}
+
 	doneCh := p.ctx.Done()
+schedule:
 	for _, record := range records {
+		select {
+		case <-doneCh:
+			break schedule
+		case toHash <- record:
+		}
+	}
+	close(toHash)
+	wg.Wait()
+
+	if p.ctx.Err() != nil {
+		p.prospectedFiles <- fileRecord{
+			ProspectError: p.ctx.Err(),
+		}
+	}
+}
+
+func (p *processor) hashFiles(records <-chan fileRecord) {
+	doneCh := p.ctx.Done()
+	for record := range records {
+		if p.ctx.Err() != nil {
+			// Drain the remaining records; prospect reports the cancellation once.
+			continue
+		}
 		record.Digests, record.ProspectError = p.digestCache.Get(p.ctx, record.File)
 
 		select {
 		case <-doneCh:
-			p.prospectedFiles <- fileRecord{
-				ProspectError: p.ctx.Err(),
-			}
-			return
 		case p.prospectedFiles <- record:
 		}
 	}
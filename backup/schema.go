@@ -0,0 +1,78 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"os"
+
+	"github.com/retailnext/cassandrabackup/bucket"
+	"github.com/retailnext/cassandrabackup/digest"
+	"github.com/retailnext/cassandrabackup/manifests"
+	"github.com/retailnext/cassandrabackup/paranoid"
+	"github.com/retailnext/cassandrabackup/systemlocal"
+	"go.uber.org/zap"
+)
+
+// backupSchema uploads the node's CQL schema as a blob and references it from the manifest. A
+// snapshot is still worth having without its schema, so failures are logged rather than returned.
+func backupSchema(ctx context.Context, client bucket.Client, manifest *manifests.Manifest) {
+	lgr := zap.S()
+	schema, err := systemlocal.GetSchema(manifest.Address)
+	if err != nil {
+		lgr.Errorw("get_schema_error", "addr", manifest.Address, "err", err)
+		return
+	}
+	digests, err := putSchema(ctx, client, schema)
+	if err != nil {
+		lgr.Errorw("put_schema_error", "err", err)
+		return
+	}
+	forRestore := digests.ForRestore()
+	manifest.Schema = &forRestore
+	lgr.Infow("put_schema", "bytes", len(schema), "blob", forRestore.URLSafe())
+}
+
+func putSchema(ctx context.Context, client bucket.Client, schema string) (digest.ForUpload, error) {
+	tmp, err := os.CreateTemp("", "cassandrabackup-schema-*")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if removeErr := os.Remove(tmp.Name()); removeErr != nil {
+			zap.S().Errorw("schema_temp_remove_error", "name", tmp.Name(), "err", removeErr)
+		}
+	}()
+	_, err = tmp.WriteString(schema)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := paranoid.NewFile(tmp.Name())
+	if err != nil {
+		return nil, err
+	}
+	digests, err := digest.GetUncached(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.PutBlob(ctx, file, digests); err != nil && err != bucket.UploadSkipped {
+		return nil, err
+	}
+	return digests, nil
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/retailnext/cassandrabackup/manifests"
 	"github.com/retailnext/cassandrabackup/nodeidentity"
 	"github.com/retailnext/cassandrabackup/nodetool"
+	"go.uber.org/zap"
 )
 
 func DoSnapshotBackup(ctx context.Context) error {
@@ -39,10 +40,21 @@ func DoSnapshotBackup(ctx context.Context) error {
 
 	manifest.ManifestType = manifests.ManifestTypeSnapshot
 
+	// Only snapshots record the ring, since it is large on big clusters and needed only to restore
+	// from a snapshot.
+	if manifest.Peers, err = nodeidentity.GetPeers(manifest.Address); err != nil {
+		zap.S().Errorw("get_peers_error", "addr", manifest.Address, "err", err)
+	}
+
+	bucketClient := bucket.OpenShared()
+	if *schemaBackup {
+		backupSchema(ctx, bucketClient, &manifest)
+	}
+
 	pr := &processor{
 		ctx: ctx,
 
-		bucketClient: bucket.OpenShared(),
+		bucketClient: bucketClient,
 		digestCache:  digest.OpenShared(),
 
 		prospectedFiles: make(chan fileRecord),
@@ -16,6 +16,7 @@ package backup
 
 import (
 	"context"
+	"errors"
 	"sync"
 
 	"github.com/retailnext/cassandrabackup/bucket"
@@ -27,7 +28,7 @@ func (p *processor) uploadFiles() {
 	defer close(p.uploadedFiles)
 
 	var wg sync.WaitGroup
-	limiter := make(chan struct{}, 2)
+	limiter := make(chan struct{}, max(*uploadConcurrency, 1))
 	for {
 		record, ok := <-p.prospectedFiles
 		if !ok {
@@ -54,6 +55,17 @@ func (p *processor) uploadFile(record fileRecord, wg *sync.WaitGroup, limiter <-
 	}
 
 	record.UploadError = p.bucketClient.PutBlob(p.ctx, record.File, record.Digests)
+	if errors.Is(record.UploadError, bucket.ErrCompressedBlobChanged) {
+		// The cached digests describe a compressed blob the file no longer compresses to.
+		lgr.Warnw("compressed_blob_changed", "path", record.File.Name(), "err", record.UploadError)
+		if err := p.digestCache.Forget(record.File); err != nil {
+			lgr.Errorw("forget_digests_error", "path", record.File.Name(), "err", err)
+		} else if record.Digests, err = p.digestCache.Get(p.ctx, record.File); err != nil {
+			record.UploadError = err
+		} else {
+			record.UploadError = p.bucketClient.PutBlob(p.ctx, record.File, record.Digests)
+		}
+	}
 	switch record.UploadError {
 	case nil:
 		lgr.Debugw("upload_done", "path", record.File.Name(), "size", record.File.Len())
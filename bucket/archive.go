@@ -0,0 +1,440 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bucket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/retailnext/cassandrabackup/bucket/safeuploader"
+	"github.com/retailnext/cassandrabackup/digest"
+	"go.uber.org/zap"
+)
+
+// isArchiveClass reports whether objects in the storage class must be restored before they can be
+// read. Glacier Instant Retrieval objects are read like any other.
+func isArchiveClass(storageClass string) bool {
+	return storageClass == s3.StorageClassGlacier || storageClass == s3.StorageClassDeepArchive
+}
+
+// isArchived reports whether S3 refused a read because the object is archived and not restored.
+func isArchived(err error) bool {
+	var awsErr awserr.Error
+	return errors.As(err, &awsErr) && awsErr.Code() == s3.ErrCodeInvalidObjectState
+}
+
+func archivedError(key string, err error) error {
+	return fmt.Errorf("%s is archived and must be restored before it can be read: %w", key, err)
+}
+
+type TierResult struct {
+	Blobs        int
+	Small        int
+	Missing      int
+	AlreadyMoved int
+	Moved        int
+	// Reusable counts the blobs left because backups may still reuse them without looking at them.
+	Reusable int
+	Failed   int
+	// Bytes is the size of the blobs moved.
+	Bytes int64
+}
+
+// Tier moves blobs to storageClass by copying each onto itself, since the storage class uploads
+// set can't otherwise be changed for objects already stored. Blobs smaller than minSize are left,
+// since archive classes bill a minimum size and per-object overhead. In a versioned bucket the copy
+// leaves the blob's previous version behind, to be expired by a lifecycle rule.
+//
+// Backups reuse a blob without looking at it again while their exists cache says it is locked for
+// long enough, so blobs locked for longer than a backup now requires aren't moved to an archive
+// class, which backups couldn't read them from.
+func Tier(ctx context.Context, c Client, blobs []digest.ForRestore, storageClass string, minSize int64, concurrency int, dryRun bool) (TierResult, error) {
+	var result TierResult
+	client, ok := c.(*awsClient)
+	if !ok {
+		return result, errors.New("only the s3 storage backend has storage classes to tier blobs to")
+	}
+	uploader := *client.uploader
+	uploader.StorageClass = aws.String(storageClass)
+
+	var lock sync.Mutex
+	err := forEach(ctx, blobs, concurrency, func(digests digest.ForRestore) {
+		key := client.keyStore.AbsoluteKeyForBlob(digests)
+		lgr := zap.S().With("key", key)
+		status, err := client.headArchive(ctx, key)
+		moved := false
+		reusable := err == nil && isArchiveClass(storageClass) && mayBeCachedAsExisting(status, client.requiredRetainUntil(time.Now()))
+		if err == nil && !reusable && status.size >= minSize && status.storageClass != storageClass && !isArchiveClass(status.storageClass) {
+			if dryRun {
+				lgr.Infow("would_tier_blob", "storage_class", status.storageClass, "size", status.size)
+			} else {
+				err = uploader.Copy(ctx, key, safeuploader.Source{
+					Bucket:     client.keyStore.bucket,
+					Key:        key,
+					Size:       status.size,
+					Encryption: client.encryption,
+				})
+			}
+			moved = err == nil
+		}
+		if err != nil && !IsNoSuchKey(err) && ctx.Err() == nil {
+			lgr.Errorw("tier_blob_error", "err", err)
+		}
+
+		lock.Lock()
+		defer lock.Unlock()
+		result.Blobs++
+		switch {
+		case IsNoSuchKey(err):
+			result.Missing++
+		case err != nil:
+			result.Failed++
+		case moved:
+			result.Moved++
+			result.Bytes += status.size
+		case reusable:
+			result.Reusable++
+		case status.size < minSize:
+			result.Small++
+		default:
+			result.AlreadyMoved++
+		}
+	})
+	if err != nil {
+		return result, err
+	}
+	if result.Failed > 0 {
+		return result, fmt.Errorf("%d blobs failed to move to %s", result.Failed, storageClass)
+	}
+	return result, nil
+}
+
+// ArchivedBlob is a blob in an archive storage class, which must be restored before downloading.
+type ArchivedBlob struct {
+	Key          string
+	StorageClass string
+	Size         int64
+	// Restoring is set once a restore has been requested, and Restored once the restored copy can
+	// be read.
+	Restoring bool
+	Restored  bool
+}
+
+// FindArchived returns the blobs that are in an archive storage class. Only S3 has them.
+func FindArchived(ctx context.Context, c Client, blobs []digest.ForRestore) ([]ArchivedBlob, error) {
+	client, ok := c.(*awsClient)
+	if !ok {
+		return nil, nil
+	}
+	var lock sync.Mutex
+	var archived []ArchivedBlob
+	var firstErr error
+	err := forEach(ctx, blobs, statBlobsConcurrency, func(digests digest.ForRestore) {
+		key := client.keyStore.AbsoluteKeyForBlob(digests)
+		status, err := client.headArchive(ctx, key)
+		lock.Lock()
+		defer lock.Unlock()
+		switch {
+		case IsNoSuchKey(err):
+		case err != nil:
+			if firstErr == nil {
+				firstErr = err
+			}
+		case isArchiveClass(status.storageClass):
+			archived = append(archived, ArchivedBlob{
+				Key:          key,
+				StorageClass: status.storageClass,
+				Size:         status.size,
+				Restoring:    status.restoring || status.restored,
+				Restored:     status.restored,
+			})
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(archived, func(i, j int) bool {
+		return archived[i].Key < archived[j].Key
+	})
+	return archived, firstErr
+}
+
+// RequestRestores asks S3 to restore the archived blobs that aren't restored or being restored, for
+// days days at the given retrieval tier, and marks them as restoring.
+func RequestRestores(ctx context.Context, c Client, archived []ArchivedBlob, tier string, days int64) (int, error) {
+	client, ok := c.(*awsClient)
+	if !ok {
+		return 0, nil
+	}
+	for _, blob := range archived {
+		if blob.StorageClass == s3.StorageClassDeepArchive && tier == s3.TierExpedited {
+			return 0, fmt.Errorf("%s retrieval is not available for %s, where %s is", tier, s3.StorageClassDeepArchive, blob.Key)
+		}
+	}
+	var lock sync.Mutex
+	var firstErr error
+	requested := 0
+	indexes := make([]int, 0, len(archived))
+	for i, blob := range archived {
+		if !blob.Restoring {
+			indexes = append(indexes, i)
+		}
+	}
+	err := forEach(ctx, indexes, statBlobsConcurrency, func(i int) {
+		key := archived[i].Key
+		input := &s3.RestoreObjectInput{
+			Bucket: &client.keyStore.bucket,
+			Key:    &key,
+			RestoreRequest: &s3.RestoreRequest{
+				Days:                 aws.Int64(days),
+				GlacierJobParameters: &s3.GlacierJobParameters{Tier: aws.String(tier)},
+			},
+		}
+		_, err := client.s3Svc.RestoreObjectWithContext(ctx, input)
+		var awsErr awserr.Error
+		if errors.As(err, &awsErr) && awsErr.Code() == "RestoreAlreadyInProgress" {
+			err = nil
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("restore %s: %w", key, err)
+			}
+			return
+		}
+		archived[i].Restoring = true
+		requested++
+	})
+	if err != nil {
+		return requested, err
+	}
+	return requested, firstErr
+}
+
+// WaitRestored checks the blobs being restored every interval until all of them can be read.
+func WaitRestored(ctx context.Context, c Client, archived []ArchivedBlob, interval time.Duration) error {
+	client, ok := c.(*awsClient)
+	if !ok {
+		return nil
+	}
+	lgr := zap.S()
+	for {
+		var pending []int
+		for i, blob := range archived {
+			if !blob.Restored {
+				pending = append(pending, i)
+			}
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+		var lock sync.Mutex
+		var firstErr error
+		err := forEach(ctx, pending, statBlobsConcurrency, func(i int) {
+			status, err := client.headArchive(ctx, archived[i].Key)
+			lock.Lock()
+			defer lock.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			if !status.restoring && !status.restored && isArchiveClass(status.storageClass) {
+				firstErr = fmt.Errorf("%s is archived and no restore of it is in progress", archived[i].Key)
+				return
+			}
+			archived[i].Restored = status.restored || !isArchiveClass(status.storageClass)
+		})
+		if err != nil {
+			return err
+		}
+		if firstErr != nil {
+			return firstErr
+		}
+
+		remaining := 0
+		for _, blob := range archived {
+			if !blob.Restored {
+				remaining++
+			}
+		}
+		if remaining == 0 {
+			return nil
+		}
+		lgr.Infow("archive_restore_waiting", "remaining", remaining, "restored", len(archived)-remaining, "next_check", interval)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// retrievalPrice is what S3 charges to restore from an archive class at a retrieval tier, and how
+// long it takes.
+type retrievalPrice struct {
+	perGB       float64
+	perThousand float64
+	duration    string
+}
+
+// retrievalPrices are approximate us-east-1 list prices, only to give an idea of the cost before
+// restoring. The prices for the bucket's region may differ.
+var retrievalPrices = map[string]map[string]retrievalPrice{
+	s3.StorageClassGlacier: {
+		s3.TierExpedited: {perGB: 0.03, perThousand: 10, duration: "1-5 minutes"},
+		s3.TierStandard:  {perGB: 0.01, perThousand: 0.05, duration: "3-5 hours"},
+		s3.TierBulk:      {perGB: 0, perThousand: 0, duration: "5-12 hours"},
+	},
+	s3.StorageClassDeepArchive: {
+		s3.TierStandard: {perGB: 0.02, perThousand: 0.10, duration: "within 12 hours"},
+		s3.TierBulk:     {perGB: 0.0025, perThousand: 0.025, duration: "within 48 hours"},
+	},
+}
+
+// restoredCopyPerGBMonth is the approximate price of keeping the restored copies, which are billed
+// as S3 Standard for as long as they are kept.
+const restoredCopyPerGBMonth = 0.023
+
+type RetrievalEstimate struct {
+	StorageClass string
+	Tier         string
+	Objects      int
+	Bytes        int64
+	// Cost is in US dollars, for the retrieval and for keeping the restored copies for the days
+	// asked for. It is -1 when the tier isn't available for the storage class.
+	Cost     float64
+	Duration string
+}
+
+// EstimateRetrieval estimates the cost and time of restoring the archived blobs that aren't
+// restored or being restored yet, for each storage class.
+func EstimateRetrieval(archived []ArchivedBlob, tier string, days int64) []RetrievalEstimate {
+	byClass := make(map[string]*RetrievalEstimate)
+	var classes []string
+	for _, blob := range archived {
+		if blob.Restoring {
+			continue
+		}
+		estimate, ok := byClass[blob.StorageClass]
+		if !ok {
+			estimate = &RetrievalEstimate{StorageClass: blob.StorageClass, Tier: tier}
+			byClass[blob.StorageClass] = estimate
+			classes = append(classes, blob.StorageClass)
+		}
+		estimate.Objects++
+		estimate.Bytes += blob.Size
+	}
+	sort.Strings(classes)
+	estimates := make([]RetrievalEstimate, 0, len(classes))
+	for _, class := range classes {
+		estimate := byClass[class]
+		price, ok := retrievalPrices[class][tier]
+		if !ok {
+			estimate.Cost = -1
+			estimate.Duration = "not available"
+		} else {
+			gb := float64(estimate.Bytes) / (1 << 30)
+			estimate.Cost = gb*price.perGB + float64(estimate.Objects)/1000*price.perThousand + gb*restoredCopyPerGBMonth*float64(days)/30
+			estimate.Duration = price.duration
+		}
+		estimates = append(estimates, *estimate)
+	}
+	return estimates
+}
+
+type archiveStatus struct {
+	storageClass string
+	size         int64
+	retainUntil  time.Time
+	restoring    bool
+	restored     bool
+}
+
+// mayBeCachedAsExisting reports whether an exists cache could still let a backup reuse the blob
+// without looking at it. Cache entries hold the retention a blob had when it was seen, which is
+// never longer than it has now, and are only trusted while that is after required, which only
+// grows.
+func mayBeCachedAsExisting(status archiveStatus, required time.Time) bool {
+	return status.retainUntil.After(required)
+}
+
+func (c *awsClient) headArchive(ctx context.Context, key string) (archiveStatus, error) {
+	input := &s3.HeadObjectInput{
+		Bucket: &c.keyStore.bucket,
+		Key:    &key,
+	}
+	c.encryption.ApplyHeadObject(input)
+	output, err := c.s3Svc.HeadObjectWithContext(ctx, input)
+	if err != nil {
+		return archiveStatus{}, err
+	}
+	status := archiveStatus{
+		storageClass: aws.StringValue(output.StorageClass),
+		size:         aws.Int64Value(output.ContentLength),
+		retainUntil:  aws.TimeValue(output.ObjectLockRetainUntilDate),
+	}
+	status.restoring, status.restored = parseRestoreHeader(aws.StringValue(output.Restore))
+	return status, nil
+}
+
+// parseRestoreHeader reads the x-amz-restore header of an archived object, which is
+// `ongoing-request="true"` while a restore is in progress and `ongoing-request="false",
+// expiry-date="..."` once the restored copy can be read.
+func parseRestoreHeader(header string) (restoring, restored bool) {
+	switch {
+	case strings.Contains(header, `ongoing-request="true"`):
+		return true, false
+	case strings.Contains(header, `ongoing-request="false"`):
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+// forEach calls f on each item, concurrency at a time, and returns once they have all returned.
+func forEach[T any](ctx context.Context, items []T, concurrency int, f func(T)) error {
+	var wg sync.WaitGroup
+	limiter := make(chan struct{}, max(concurrency, 1))
+	doneCh := ctx.Done()
+schedule:
+	for _, item := range items {
+		select {
+		case <-doneCh:
+			break schedule
+		case limiter <- struct{}{}:
+		}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-limiter
+				wg.Done()
+			}()
+			f(item)
+		}()
+	}
+	wg.Wait()
+	return ctx.Err()
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bucket
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/go-test/deep"
+)
+
+func TestParseRestoreHeader(t *testing.T) {
+	for _, c := range []struct {
+		header              string
+		restoring, restored bool
+	}{
+		{"", false, false},
+		{`ongoing-request="true"`, true, false},
+		{`ongoing-request="false", expiry-date="Fri, 21 Dec 2012 00:00:00 GMT"`, false, true},
+	} {
+		restoring, restored := parseRestoreHeader(c.header)
+		if restoring != c.restoring || restored != c.restored {
+			t.Errorf("%q: expected %v %v got %v %v", c.header, c.restoring, c.restored, restoring, restored)
+		}
+	}
+}
+
+func TestIsArchived(t *testing.T) {
+	err := archivedError("key", awserr.New(s3.ErrCodeInvalidObjectState, "", nil))
+	if !isArchived(err) {
+		t.Error("expected a wrapped InvalidObjectState to be archived")
+	}
+	if isArchived(awserr.New(s3.ErrCodeNoSuchKey, "", nil)) {
+		t.Error("expected NoSuchKey not to be archived")
+	}
+}
+
+func TestEstimateRetrieval(t *testing.T) {
+	const gb = 1 << 30
+	archived := []ArchivedBlob{
+		{Key: "a", StorageClass: s3.StorageClassGlacier, Size: 10 * gb},
+		{Key: "b", StorageClass: s3.StorageClassDeepArchive, Size: 20 * gb},
+		{Key: "c", StorageClass: s3.StorageClassDeepArchive, Size: 30 * gb},
+		{Key: "d", StorageClass: s3.StorageClassDeepArchive, Size: 40 * gb, Restoring: true},
+	}
+	expected := []RetrievalEstimate{
+		{StorageClass: s3.StorageClassDeepArchive, Tier: s3.TierBulk, Objects: 2, Bytes: 50 * gb, Cost: 50*0.0025 + 2*0.025/1000 + 50*0.023, Duration: "within 48 hours"},
+		{StorageClass: s3.StorageClassGlacier, Tier: s3.TierBulk, Objects: 1, Bytes: 10 * gb, Cost: 10 * 0.023, Duration: "5-12 hours"},
+	}
+	if diff := deep.Equal(EstimateRetrieval(archived, s3.TierBulk, 30), expected); diff != nil {
+		t.Error(diff)
+	}
+
+	estimates := EstimateRetrieval(archived, s3.TierExpedited, 7)
+	if estimates[0].StorageClass != s3.StorageClassDeepArchive || estimates[0].Cost >= 0 {
+		t.Errorf("expected expedited retrieval to be unavailable for deep archive, got %+v", estimates[0])
+	}
+}
@@ -15,12 +15,25 @@
 package bucket
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"go.uber.org/zap"
 )
 
 func IsNoSuchKey(err error) bool {
+	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, storage.ErrObjectNotExist) {
+		return true
+	}
+	if bloberror.HasCode(err, bloberror.BlobNotFound) {
+		return true
+	}
 	if err != nil {
 		if awsErr, ok := err.(awserr.Error); ok {
 			switch awsErr.Code() {
@@ -36,3 +49,53 @@ func IsNoSuchKey(err error) bool {
 	}
 	return false
 }
+
+// isNotImplemented reports whether an S3-compatible store rejected a request it doesn't support.
+func isNotImplemented(err error) bool {
+	var awsErr awserr.Error
+	if !errors.As(err, &awsErr) {
+		return false
+	}
+	switch awsErr.Code() {
+	case "NotImplemented", "XNotImplemented", "MethodNotAllowed":
+		return true
+	}
+	return false
+}
+
+// DecryptError is a read that S3 refused because it couldn't decrypt the object: the caller lacks
+// kms:Decrypt on the object's KMS key, the key is disabled or gone, or the SSE-C key is wrong.
+type DecryptError struct {
+	Key string
+	Err error
+}
+
+func (e DecryptError) Error() string {
+	return fmt.Sprintf("S3 could not decrypt %s; check kms:Decrypt permission on its KMS key and the key's state, or the SSE-C key: %v", e.Key, e.Err)
+}
+
+func (e DecryptError) Unwrap() error {
+	return e.Err
+}
+
+func isDecryptError(err error) bool {
+	var decryptErr DecryptError
+	return errors.As(err, &decryptErr)
+}
+
+// asDecryptError returns err as a DecryptError if it is one, or err unchanged.
+func asDecryptError(key string, err error) error {
+	var awsErr awserr.Error
+	if !errors.As(err, &awsErr) {
+		return err
+	}
+	code, message := awsErr.Code(), strings.ToLower(awsErr.Message())
+	switch {
+	case strings.HasPrefix(code, "KMS."):
+	case code == "AccessDenied" && strings.Contains(message, "kms"):
+	case (code == "InvalidRequest" || code == "InvalidArgument") && (strings.Contains(message, "encrypt") || strings.Contains(message, "key")):
+	default:
+		return err
+	}
+	return DecryptError{Key: key, Err: err}
+}
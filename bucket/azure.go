@@ -0,0 +1,472 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bucket
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/retailnext/cassandrabackup/digest"
+	"github.com/retailnext/cassandrabackup/digest/parts"
+	"github.com/retailnext/cassandrabackup/envelope"
+	"github.com/retailnext/cassandrabackup/manifests"
+	"github.com/retailnext/cassandrabackup/paranoid"
+	"github.com/retailnext/cassandrabackup/throttle"
+	"github.com/retailnext/cassandrabackup/unixtime"
+	"go.uber.org/zap"
+)
+
+// A connection string is the simplest way to point this backend at Azurite.
+// Otherwise the container is addressed by account URL using the default Azure credential chain.
+var (
+	azureContainerName    = kingpin.Flag("azure-container", "Azure Blob Storage container name.").String()
+	azureAccountURL       = kingpin.Flag("azure-account-url", "Azure Blob Storage account URL (https://<account>.blob.core.windows.net).").String()
+	azureConnectionString = kingpin.Flag("azure-connection-string", "Azure Blob Storage connection string.").Envar("AZURE_STORAGE_CONNECTION_STRING").String()
+	azureKeyPrefix        = kingpin.Flag("azure-key-prefix", "Set the prefix for files in the Azure container").Default("/").String()
+	azureBlobAccessTier   = kingpin.Flag("azure-access-tier", "Set the access tier for files in Azure (default: account default)").String()
+	azureEncryptionScope  = kingpin.Flag("azure-encryption-scope", "Encrypt uploads with this encryption scope.").String()
+)
+
+type azureClient struct {
+	container       *container.Client
+	accessTier      *blob.AccessTier
+	encryptionScope *blob.CPKScopeInfo
+
+	keyStore KeyStore
+	keys     *envelope.Keyring
+}
+
+func newAzureClient() *azureClient {
+	lgr := zap.S()
+	if *azureContainerName == "" {
+		lgr.Fatalw("azure_container_required")
+	}
+	var containerClient *container.Client
+	var err error
+	if *azureConnectionString != "" {
+		containerClient, err = container.NewClientFromConnectionString(*azureConnectionString, *azureContainerName, nil)
+	} else {
+		if *azureAccountURL == "" {
+			lgr.Fatalw("azure_account_url_or_connection_string_required")
+		}
+		var cred *azidentity.DefaultAzureCredential
+		cred, err = azidentity.NewDefaultAzureCredential(nil)
+		if err == nil {
+			containerURL := strings.TrimSuffix(*azureAccountURL, "/") + "/" + *azureContainerName
+			containerClient, err = container.NewClient(containerURL, cred, nil)
+		}
+	}
+	if err != nil {
+		lgr.Fatalw("azure_new_client_error", "err", err)
+	}
+
+	c := &azureClient{
+		container: containerClient,
+		keyStore:  newKeyStore(*azureContainerName, strings.Trim(*azureKeyPrefix, "/")),
+		keys:      openKeyring(),
+	}
+	if *azureBlobAccessTier != "" {
+		tier := blob.AccessTier(*azureBlobAccessTier)
+		c.accessTier = &tier
+	}
+	if *azureEncryptionScope != "" {
+		c.encryptionScope = &blob.CPKScopeInfo{
+			EncryptionScope: azureEncryptionScope,
+		}
+	}
+	c.validateEncryptionConfiguration()
+	return c
+}
+
+// validateEncryptionConfiguration is the Azure counterpart of the S3 default SSE check.
+// Azure always encrypts at rest; this confirms the container is reachable and that a requested
+// encryption scope will not be refused because the container pins a different default scope.
+func (c *azureClient) validateEncryptionConfiguration() {
+	props, err := c.container.GetProperties(context.Background(), nil)
+	if err != nil {
+		zap.S().Fatalw("failed_to_validate_container_encryption", "err", err)
+	}
+	if c.encryptionScope == nil {
+		return
+	}
+	defaultScope := ""
+	if props.DefaultEncryptionScope != nil {
+		defaultScope = *props.DefaultEncryptionScope
+	}
+	if props.DenyEncryptionScopeOverride != nil && *props.DenyEncryptionScopeOverride && defaultScope != *c.encryptionScope.EncryptionScope {
+		zap.S().Fatalw("container_denies_encryption_scope_override", "container", c.keyStore.bucket, "default", defaultScope, "requested", *c.encryptionScope.EncryptionScope)
+	}
+}
+
+func (c *azureClient) KeyStore() *KeyStore {
+	return &c.keyStore
+}
+
+func (c *azureClient) keyring() *envelope.Keyring {
+	return c.keys
+}
+
+func (c *azureClient) PutBlob(ctx context.Context, file paranoid.File, digests digest.ForUpload) error {
+	return putBlob(ctx, c, file, digests)
+}
+
+func (c *azureClient) blobExists(ctx context.Context, digests digest.ForRestore, expectedLength int64) (bool, error) {
+	key := c.keyStore.AbsoluteKeyForBlob(digests)
+	props, err := c.container.NewBlobClient(key).GetProperties(ctx, nil)
+	if err != nil {
+		if IsNoSuchKey(err) {
+			return false, nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return false, ctxErr
+		}
+		return false, err
+	}
+	if props.ContentLength == nil || *props.ContentLength != expectedLength {
+		zap.S().Infow("blob_exists_saw_wrong_length", "key", key, "expected", expectedLength, "actual", props.ContentLength)
+		return false, nil
+	}
+	return true, nil
+}
+
+// uploadBlob stages one block per digest part, each validated by Azure against the part's MD5,
+// then commits the block list. Single part blobs are sent in one validated request.
+func (c *azureClient) uploadBlob(ctx context.Context, key string, body io.ReaderAt, pd *parts.PartDigests) error {
+	blockBlob := c.container.NewBlockBlobClient(key)
+	body = throttle.ReaderAt(ctx, body, throttle.Upload)
+	if pd.Parts() == 1 {
+		md5Sum, err := base64.StdEncoding.DecodeString(pd.PartContentMD5(1))
+		if err != nil {
+			panic(err)
+		}
+		resp, err := blockBlob.Upload(ctx, streaming.NopCloser(io.NewSectionReader(body, 0, pd.PartLength(1))), &blockblob.UploadOptions{
+			Tier:                    c.accessTier,
+			CPKScopeInfo:            c.encryptionScope,
+			TransactionalValidation: blob.TransferValidationTypeMD5(md5Sum),
+		})
+		if err != nil {
+			return err
+		}
+		return checkServerEncrypted(resp.IsServerEncrypted)
+	}
+
+	blockIDs := make([]string, pd.Parts())
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	var firstErr error
+	limiter := make(chan struct{}, max(*partUploadConcurrency, 1))
+	stageCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var partNumber int64
+	for partNumber = 1; partNumber <= pd.Parts(); partNumber++ {
+		blockIDs[partNumber-1] = base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%010d", partNumber)))
+		limiter <- struct{}{}
+		wg.Add(1)
+		go func(partNumber int64, blockID string) {
+			defer func() {
+				<-limiter
+				wg.Done()
+			}()
+			md5Sum, err := base64.StdEncoding.DecodeString(pd.PartContentMD5(partNumber))
+			if err != nil {
+				panic(err)
+			}
+			section := io.NewSectionReader(body, pd.PartOffset(partNumber), pd.PartLength(partNumber))
+			_, err = blockBlob.StageBlock(stageCtx, blockID, streaming.NopCloser(section), &blockblob.StageBlockOptions{
+				CPKScopeInfo:            c.encryptionScope,
+				TransactionalValidation: blob.TransferValidationTypeMD5(md5Sum),
+			})
+			if err != nil {
+				lock.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				lock.Unlock()
+				cancel()
+			}
+		}(partNumber, blockIDs[partNumber-1])
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+
+	resp, err := blockBlob.CommitBlockList(ctx, blockIDs, &blockblob.CommitBlockListOptions{
+		Tier:         c.accessTier,
+		CPKScopeInfo: c.encryptionScope,
+	})
+	if err != nil {
+		return err
+	}
+	return checkServerEncrypted(resp.IsServerEncrypted)
+}
+
+func checkServerEncrypted(encrypted *bool) error {
+	if encrypted == nil || !*encrypted {
+		return fmt.Errorf("azure did not report the blob as server encrypted")
+	}
+	return nil
+}
+
+func (c *azureClient) DownloadBlob(ctx context.Context, digests digest.ForRestore, file *os.File) error {
+	return downloadBlob(ctx, c, digests, file)
+}
+
+func (c *azureClient) ResumeDownloadBlob(ctx context.Context, digests digest.ForRestore, file *os.File) error {
+	return resumeDownloadBlob(ctx, c, digests, file)
+}
+
+func (c *azureClient) StatBlob(ctx context.Context, digests digest.ForRestore) (int64, error) {
+	return statBlob(ctx, c, digests)
+}
+
+func (c *azureClient) downloadBlob(ctx context.Context, key string, file *os.File) error {
+	attempts := 0
+	for {
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			zap.S().Panicw("get_blob_seek_error", "err", err)
+		}
+		if err := file.Truncate(0); err != nil {
+			zap.S().Panicw("get_blob_truncate_error", "err", err)
+		}
+		err := c.downloadBlobFrom(ctx, key, throttle.Writer(ctx, file, throttle.Download), 0)
+		if err != nil {
+			attempts++
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			if IsNoSuchKey(err) || attempts > getBlobRetriesLimit {
+				return err
+			}
+			zap.S().Errorw("get_blob_azure_error", "err", err, "attempts", attempts)
+		} else {
+			return nil
+		}
+	}
+}
+
+func (c *azureClient) downloadBlobFrom(ctx context.Context, key string, w io.Writer, offset int64) error {
+	var options *blob.DownloadStreamOptions
+	if offset > 0 {
+		options = &blob.DownloadStreamOptions{Range: blob.HTTPRange{Offset: offset}}
+	}
+	resp, err := c.container.NewBlobClient(key).DownloadStream(ctx, options)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+func (c *azureClient) PutManifest(ctx context.Context, identity manifests.NodeIdentity, manifest manifests.Manifest) error {
+	return putManifest(ctx, c, identity, manifest)
+}
+
+func (c *azureClient) GetManifests(ctx context.Context, identity manifests.NodeIdentity, keys manifests.ManifestKeys) ([]manifests.Manifest, error) {
+	return getManifests(ctx, c, identity, keys)
+}
+
+func (c *azureClient) putObject(ctx context.Context, absoluteKey string, data []byte, contentType, contentEncoding string) error {
+	md5Sum := md5.Sum(data)
+	headers := &blob.HTTPHeaders{
+		BlobContentType: stringPtr(contentType),
+	}
+	if contentEncoding != "" {
+		headers.BlobContentEncoding = stringPtr(contentEncoding)
+	}
+	attempts := 0
+	for {
+		resp, err := c.container.NewBlockBlobClient(absoluteKey).Upload(ctx, streaming.NopCloser(bytes.NewReader(data)), &blockblob.UploadOptions{
+			HTTPHeaders:             headers,
+			CPKScopeInfo:            c.encryptionScope,
+			TransactionalValidation: blob.TransferValidationTypeMD5(md5Sum[:]),
+		})
+		if err == nil {
+			err = checkServerEncrypted(resp.IsServerEncrypted)
+		}
+		if err != nil {
+			attempts++
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			if attempts > putJsonRetriesLimit {
+				return err
+			}
+			zap.S().Warnw("azure_put_blob_error", "err", err, "attempts", attempts)
+			time.Sleep(time.Duration(attempts) * retrySleepPerAttempt)
+		} else {
+			return nil
+		}
+	}
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func (c *azureClient) getObject(ctx context.Context, absoluteKey string) ([]byte, error) {
+	attempts := 0
+	for {
+		resp, err := c.container.NewBlobClient(absoluteKey).DownloadStream(ctx, nil)
+		var data []byte
+		if err == nil {
+			data, err = io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			if err == nil {
+				return data, nil
+			}
+		}
+		if IsNoSuchKey(err) {
+			return nil, err
+		}
+		attempts++
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		if attempts > getJsonRetriesLimit {
+			return nil, err
+		}
+		zap.S().Warnw("azure_get_blob_error", "err", err, "attempts", attempts)
+		time.Sleep(time.Duration(attempts) * retrySleepPerAttempt)
+	}
+}
+
+func (c *azureClient) listObjects(ctx context.Context, prefix string) ([]objectInfo, error) {
+	var objects []objectInfo
+	pager := c.container.NewListBlobsFlatPager(&container.ListBlobsFlatOptions{
+		Prefix: &prefix,
+	})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range page.Segment.BlobItems {
+			info := objectInfo{Key: *item.Name}
+			if props := item.Properties; props != nil {
+				info.Size = valueOf(props.ContentLength)
+				info.LastModified = valueOf(props.LastModified)
+			}
+			objects = append(objects, info)
+		}
+	}
+	return objects, nil
+}
+
+func (c *azureClient) statObject(ctx context.Context, absoluteKey string) (objectInfo, error) {
+	props, err := c.container.NewBlobClient(absoluteKey).GetProperties(ctx, nil)
+	if err != nil {
+		return objectInfo{}, err
+	}
+	return objectInfo{
+		Key:          absoluteKey,
+		Size:         valueOf(props.ContentLength),
+		LastModified: valueOf(props.LastModified),
+		RetainUntil:  valueOf(props.ImmutabilityPolicyExpiresOn),
+		LegalHold:    valueOf(props.LegalHold),
+	}, nil
+}
+
+func (c *azureClient) deleteObject(ctx context.Context, absoluteKey string) error {
+	_, err := c.container.NewBlobClient(absoluteKey).Delete(ctx, nil)
+	return err
+}
+
+func valueOf[T any](p *T) T {
+	var v T
+	if p != nil {
+		v = *p
+	}
+	return v
+}
+
+func (c *azureClient) ListManifests(ctx context.Context, identity manifests.NodeIdentity, startAfter, notAfter unixtime.Seconds) (manifests.ManifestKeys, error) {
+	names, _, err := c.list(ctx, c.keyStore.absoluteKeyPrefixForManifests(identity))
+	if err != nil {
+		return nil, err
+	}
+	return c.keyStore.manifestKeysInRange(identity, startAfter, notAfter, names), nil
+}
+
+func (c *azureClient) ListHostNames(ctx context.Context, cluster string) ([]manifests.NodeIdentity, error) {
+	lgr := zap.S()
+	names, prefixes, err := c.list(ctx, c.keyStore.absoluteKeyPrefixForClusterHosts(cluster))
+	if err != nil {
+		return nil, err
+	}
+	result, bonus := c.keyStore.decodeClusterHostPrefixes(prefixes)
+	if len(bonus) > 0 {
+		lgr.Warnw("unexpected_objects_in_bucket", "keys", bonus)
+	}
+	if len(names) > 0 {
+		lgr.Warnw("unexpected_objects_in_bucket", "keys", names)
+	}
+	return result, nil
+}
+
+func (c *azureClient) ListClusters(ctx context.Context) ([]string, error) {
+	_, prefixes, err := c.list(ctx, c.keyStore.absoluteKeyPrefixForClusters())
+	if err != nil {
+		return nil, err
+	}
+	var result []string
+	for _, prefix := range prefixes {
+		cluster, err := c.keyStore.decodeCluster(prefix)
+		if err != nil {
+			zap.S().Errorw("decode_cluster_error", "err", err)
+		} else {
+			result = append(result, cluster)
+		}
+	}
+	return result, nil
+}
+
+// list returns the blob names and virtual directory prefixes directly under prefix, in lexical order.
+func (c *azureClient) list(ctx context.Context, prefix string) ([]string, []string, error) {
+	var names, prefixes []string
+	pager := c.container.NewListBlobsHierarchyPager("/", &container.ListBlobsHierarchyOptions{
+		Prefix: &prefix,
+	})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, item := range page.Segment.BlobPrefixes {
+			prefixes = append(prefixes, *item.Name)
+		}
+		for _, item := range page.Segment.BlobItems {
+			names = append(names, *item.Name)
+		}
+	}
+	return names, prefixes, nil
+}
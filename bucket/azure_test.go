@@ -0,0 +1,52 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bucket
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+)
+
+// Run against Azurite with AZURITE_CONNECTION_STRING set to its development storage connection string.
+func TestAzureClient(t *testing.T) {
+	connectionString := os.Getenv("AZURITE_CONNECTION_STRING")
+	if connectionString == "" {
+		t.Skip("AZURITE_CONNECTION_STRING not set")
+	}
+	const containerName = "cassandrabackup-test"
+	containerClient, err := container.NewClientFromConnectionString(connectionString, containerName, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := containerClient.Create(context.Background(), nil); err != nil && !bloberror.HasCode(err, bloberror.ContainerAlreadyExists) {
+		t.Fatal(err)
+	}
+
+	c := &azureClient{
+		container: containerClient,
+		keyStore:  newKeyStore(containerName, "test"),
+	}
+	c.validateEncryptionConfiguration()
+	t.Run("Blobs", func(t *testing.T) {
+		testClientBlobs(t, c)
+	})
+	t.Run("Manifests", func(t *testing.T) {
+		testClientManifests(t, c)
+	})
+}
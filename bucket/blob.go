@@ -17,25 +17,76 @@ package bucket
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"os"
+	"sync"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/retailnext/cassandrabackup/compression"
 	"github.com/retailnext/cassandrabackup/digest"
+	"github.com/retailnext/cassandrabackup/digest/parts"
+	"github.com/retailnext/cassandrabackup/envelope"
 	"github.com/retailnext/cassandrabackup/paranoid"
+	"github.com/retailnext/cassandrabackup/throttle"
 	"go.uber.org/zap"
 )
 
+const statBlobsConcurrency = 8
+
 var UploadSkipped = errors.New("upload skipped")
 
+// ErrCompressedBlobChanged is returned by PutBlob when compressing a file no longer gives the blob
+// its digests describe, such as after an upgrade of the compression library. The digests need to be
+// computed again before the file is uploaded.
+var ErrCompressedBlobChanged = errors.New("compressed blob differs from its digests")
+
+// blobStore is what each backend provides for blobs. PutBlob and DownloadBlob are built on it so that
+// the skip-if-present check, client-side encryption and metrics are the same for every backend.
+type blobStore interface {
+	objectStore
+	KeyStore() *KeyStore
+	keyring() *envelope.Keyring
+	// blobExists reports whether the blob is stored with the given length.
+	blobExists(ctx context.Context, digests digest.ForRestore, length int64) (bool, error)
+	uploadBlob(ctx context.Context, key string, body io.ReaderAt, partDigests *parts.PartDigests) error
+	// downloadBlob replaces the contents of file with the stored object, retrying on failure.
+	downloadBlob(ctx context.Context, key string, file *os.File) error
+	// downloadBlobFrom writes the stored object from offset onwards to w, without retrying.
+	downloadBlobFrom(ctx context.Context, key string, w io.Writer, offset int64) error
+}
+
 func (c *awsClient) KeyStore() *KeyStore {
 	return &c.keyStore
 }
 
+func (c *awsClient) keyring() *envelope.Keyring {
+	return c.keys
+}
+
 func (c *awsClient) PutBlob(ctx context.Context, file paranoid.File, digests digest.ForUpload) error {
-	key := c.keyStore.AbsoluteKeyForBlob(digests.ForRestore())
-	if exists, err := c.blobExists(ctx, digests); err != nil {
+	return putBlob(ctx, c, file, digests)
+}
+
+func (c *awsClient) uploadBlob(ctx context.Context, key string, body io.ReaderAt, partDigests *parts.PartDigests) error {
+	return c.uploader.Upload(ctx, key, body, partDigests)
+}
+
+func putBlob(ctx context.Context, store blobStore, file paranoid.File, digests digest.ForUpload) error {
+	expectedLength := digests.PartDigests().TotalLength()
+	if store.keyring() != nil {
+		expectedLength = envelope.SealedSize(expectedLength)
+	}
+	// Blobs that a prune is about to delete are uploaded again rather than reused.
+	if pending, err := isPendingDelete(ctx, store, digests.ForRestore()); err != nil {
+		uploadErrors.Inc()
+		return err
+	} else if pending {
+		reuploadedFiles.Inc()
+	} else if exists, err := store.blobExists(ctx, digests.ForRestore(), expectedLength); err != nil {
 		uploadErrors.Inc()
 		return err
 	} else if exists {
@@ -44,7 +95,7 @@ func (c *awsClient) PutBlob(ctx context.Context, file paranoid.File, digests dig
 		return UploadSkipped
 	}
 
-	if err := c.uploader.UploadFile(ctx, key, file, digests); err != nil {
+	if err := uploadFile(ctx, store, file, digests); err != nil {
 		uploadErrors.Inc()
 		if ctxErr := ctx.Err(); ctxErr != nil {
 			return ctxErr
@@ -53,15 +104,247 @@ func (c *awsClient) PutBlob(ctx context.Context, file paranoid.File, digests dig
 	}
 	uploadedFiles.Inc()
 	uploadedBytes.Add(float64(file.Len()))
+	if digests.Codec() != compression.None {
+		recordCompression(file.Len(), digests.PartDigests().TotalLength())
+	}
 	return nil
 }
 
+// uploadFile uploads the file as is or compressed, and then either as is or sealed with a new data
+// key whose wrapped form is stored first.
+func uploadFile(ctx context.Context, store blobStore, file paranoid.File, digests digest.ForUpload) error {
+	keyStore := store.KeyStore()
+	osFile, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := osFile.Close(); closeErr != nil {
+			panic(closeErr)
+		}
+	}()
+
+	var body io.ReaderAt = throttle.ReaderAt(ctx, osFile, throttle.DiskRead)
+	partDigests := digests.PartDigests()
+	if codec := digests.Codec(); codec != compression.None {
+		compressed, err := compressFile(ctx, throttle.Reader(ctx, osFile, throttle.DiskRead), codec, partDigests.PartSize())
+		if err != nil {
+			return err
+		}
+		defer compressed.Close()
+		if !samePartDigests(compressed.partDigests, partDigests) {
+			// Uploading it anyway would leave a blob whose length never matches what later backups
+			// and manifests expect of it.
+			return fmt.Errorf("%s compressed to %d bytes instead of %d: %w", file.Name(), compressed.partDigests.TotalLength(), partDigests.TotalLength(), ErrCompressedBlobChanged)
+		}
+		body, partDigests = compressed, compressed.partDigests
+	}
+	if keyring := store.keyring(); keyring != nil {
+		dataKey, wrapped, err := keyring.NewDataKey(ctx)
+		if err != nil {
+			return err
+		}
+		sealed := envelope.NewSealedReader(dataKey, body, partDigests.TotalLength())
+		if partDigests, err = sealedPartDigests(ctx, sealed, partDigests.PartSize()); err != nil {
+			return err
+		}
+		if err := putDataKey(ctx, store, keyStore, digests.ForRestore(), wrapped); err != nil {
+			return err
+		}
+		body = sealed
+	}
+
+	if err := store.uploadBlob(ctx, keyStore.AbsoluteKeyForBlob(digests.ForRestore()), body, partDigests); err != nil {
+		return err
+	}
+	return file.CheckFile(osFile)
+}
+
+// sealedPartDigests computes the part digests of a sealed blob, which unlike those of the
+// plaintext cannot be cached because every upload uses a new data key.
+func sealedPartDigests(ctx context.Context, sealed *envelope.SealedReader, partSize int64) (*parts.PartDigests, error) {
+	var maker parts.PartDigestsMaker
+	maker.Reset(uint64(partSize))
+	if _, err := io.Copy(&maker, contextReader{ctx: ctx, r: io.NewSectionReader(sealed, 0, sealed.Size())}); err != nil {
+		return nil, err
+	}
+	pd := maker.Finish()
+	return &pd, nil
+}
+
 func (c *awsClient) DownloadBlob(ctx context.Context, digests digest.ForRestore, file *os.File) error {
-	key := c.keyStore.AbsoluteKeyForBlob(digests)
+	return downloadBlob(ctx, c, digests, file)
+}
+
+func downloadBlob(ctx context.Context, store blobStore, digests digest.ForRestore, file *os.File) error {
+	key := store.KeyStore().AbsoluteKeyForBlob(digests)
+	if err := store.downloadBlob(ctx, key, file); err != nil {
+		return err
+	}
+	return finishDownload(ctx, store, key, digests, file)
+}
+
+func (c *awsClient) ResumeDownloadBlob(ctx context.Context, digests digest.ForRestore, file *os.File) error {
+	return resumeDownloadBlob(ctx, c, digests, file)
+}
+
+// resumeDownloadBlob downloads the rest of a blob into file, which holds the start of the stored
+// object from an earlier attempt, then decrypts, decompresses and verifies it like DownloadBlob.
+// A failed or short download keeps what it got, but if the finished download doesn't verify, file
+// is emptied so that the next attempt starts over.
+func resumeDownloadBlob(ctx context.Context, store blobStore, digests digest.ForRestore, file *os.File) error {
+	lgr := zap.S()
+	key := store.KeyStore().AbsoluteKeyForBlob(digests)
+	info, err := store.statObject(ctx, key)
+	if err != nil {
+		return err
+	}
+	size, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	if size > info.Size {
+		lgr.Infow("resume_download_too_long", "key", key, "size", size, "expected", info.Size)
+		if err := file.Truncate(0); err != nil {
+			return err
+		}
+		if size, err = file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+	}
+	if size > 0 {
+		resumedDownloads.Inc()
+		resumedDownloadBytes.Add(float64(size))
+	}
+
+	attempts := 0
+	for size < info.Size {
+		dlErr := store.downloadBlobFrom(ctx, key, throttle.Writer(ctx, file, throttle.Download), size)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if size, err = file.Seek(0, io.SeekEnd); err != nil {
+			return err
+		}
+		if dlErr == nil {
+			break
+		}
+		attempts++
+		if IsNoSuchKey(dlErr) || isArchived(dlErr) || attempts > getBlobRetriesLimit {
+			// What was downloaded is kept for the next attempt.
+			return dlErr
+		}
+		lgr.Errorw("resume_download_error", "key", key, "err", dlErr, "attempts", attempts, "offset", size)
+	}
+	if size != info.Size {
+		return fmt.Errorf("downloaded %d bytes of %s, expected %d", size, key, info.Size)
+	}
+	err = finishDownload(ctx, store, key, digests, file)
+	if err != nil && ctx.Err() == nil {
+		if truncateErr := file.Truncate(0); truncateErr != nil {
+			lgr.Errorw("resume_download_truncate_error", "key", key, "err", truncateErr)
+		}
+	}
+	return err
+}
+
+// finishDownload turns a downloaded object into the original file and checks it.
+func finishDownload(ctx context.Context, store blobStore, key string, digests digest.ForRestore, file *os.File) error {
+	if err := openSealedBlob(ctx, store, key, digests, file); err != nil {
+		return err
+	}
+	if err := decompressBlob(ctx, file); err != nil {
+		return fmt.Errorf("decompress %s: %w", key, err)
+	}
+	return digests.Verify(ctx, file)
+}
+
+// openSealedBlob decrypts a downloaded blob in place if it was sealed.
+// Blobs uploaded before client-side encryption was enabled are left as they are.
+func openSealedBlob(ctx context.Context, store blobStore, key string, digests digest.ForRestore, file *os.File) error {
+	header := make([]byte, envelope.HeaderSize)
+	if n, _ := file.ReadAt(header, 0); !envelope.IsSealed(header[:n]) {
+		return nil
+	}
+	keyring := store.keyring()
+	if keyring == nil {
+		return errNoKeyring(key)
+	}
+	id, err := envelope.StreamKeyID(header)
+	if err != nil {
+		return err
+	}
+	wrapped, err := getDataKey(ctx, store, store.KeyStore(), digests, id)
+	if err != nil {
+		return fmt.Errorf("get data key %s for %s: %w", id, key, err)
+	}
+	dataKey, err := keyring.Unwrap(ctx, wrapped)
+	if err != nil {
+		return fmt.Errorf("unwrap data key %s for %s: %w", id, key, err)
+	}
+	return envelope.OpenFile(dataKey, file)
+}
+
+func (c *awsClient) StatBlob(ctx context.Context, digests digest.ForRestore) (int64, error) {
+	return statBlob(ctx, c, digests)
+}
+
+// statBlob returns the stored size of a blob, which includes any compression and encryption overhead.
+// Unlike blobExists it bypasses the exists cache, so that it sees blobs that have since been deleted.
+func statBlob(ctx context.Context, store blobStore, digests digest.ForRestore) (int64, error) {
+	info, err := store.statObject(ctx, store.KeyStore().AbsoluteKeyForBlob(digests))
+	if err != nil {
+		return 0, err
+	}
+	return info.Size, nil
+}
+
+// StatBlobs looks up the stored sizes of blobs, a few at a time. Missing blobs are left out of the result.
+func StatBlobs(ctx context.Context, c Client, blobs []digest.ForRestore) (map[digest.ForRestore]int64, error) {
+	result := make(map[digest.ForRestore]int64, len(blobs))
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	var firstErr error
+	limiter := make(chan struct{}, statBlobsConcurrency)
+	doneCh := ctx.Done()
+schedule:
+	for _, digests := range blobs {
+		select {
+		case <-doneCh:
+			break schedule
+		case limiter <- struct{}{}:
+		}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-limiter
+				wg.Done()
+			}()
+			size, err := c.StatBlob(ctx, digests)
+			lock.Lock()
+			defer lock.Unlock()
+			switch {
+			case err == nil:
+				result[digests] = size
+			case IsNoSuchKey(err):
+			case firstErr == nil:
+				firstErr = err
+			}
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return result, firstErr
+}
+
+func (c *awsClient) downloadBlob(ctx context.Context, key string, file *os.File) error {
 	getObjectInput := &s3.GetObjectInput{
 		Bucket: &c.keyStore.bucket,
 		Key:    &key,
 	}
+	c.encryption.ApplyGetObject(getObjectInput)
 	attempts := 0
 	for {
 		if _, err := file.Seek(0, io.SeekStart); err != nil {
@@ -70,25 +353,53 @@ func (c *awsClient) DownloadBlob(ctx context.Context, digests digest.ForRestore,
 		if err := file.Truncate(0); err != nil {
 			zap.S().Panicw("get_blob_truncate_error", "err", err)
 		}
-		_, err := c.downloader.DownloadWithContext(ctx, file, getObjectInput)
+		_, err := c.downloader.DownloadWithContext(ctx, throttle.WriterAt(ctx, file, throttle.Download), getObjectInput)
 		if err != nil {
 			attempts++
 			if ctxErr := ctx.Err(); ctxErr != nil {
 				return ctxErr
 			}
-			if IsNoSuchKey(err) || attempts > getBlobRetriesLimit {
+			if isArchived(err) {
+				return archivedError(key, err)
+			}
+			err = asDecryptError(key, err)
+			if IsNoSuchKey(err) || isDecryptError(err) || attempts > getBlobRetriesLimit {
 				return err
 			}
 			zap.S().Errorw("get_blob_s3_error", "err", err, "attempts", attempts)
 		} else {
-			return digests.Verify(ctx, file)
+			return nil
+		}
+	}
+}
+
+func (c *awsClient) downloadBlobFrom(ctx context.Context, key string, w io.Writer, offset int64) error {
+	getObjectInput := &s3.GetObjectInput{
+		Bucket: &c.keyStore.bucket,
+		Key:    &key,
+	}
+	c.encryption.ApplyGetObject(getObjectInput)
+	if offset > 0 {
+		getObjectInput.Range = aws.String(fmt.Sprintf("bytes=%d-", offset))
+	}
+	getObjectOutput, err := c.s3Svc.GetObjectWithContext(ctx, getObjectInput)
+	if err != nil {
+		if isArchived(err) {
+			return archivedError(key, err)
 		}
+		return asDecryptError(key, err)
 	}
+	defer func() {
+		_ = getObjectOutput.Body.Close()
+	}()
+	_, err = io.Copy(w, contextReader{ctx: ctx, r: getObjectOutput.Body})
+	return err
 }
 
-func (c *awsClient) blobExists(ctx context.Context, digests digest.ForUpload) (bool, error) {
-	key := c.keyStore.AbsoluteKeyForBlob(digests.ForRestore())
-	if c.existsCache.Get(digests.ForRestore()) {
+func (c *awsClient) blobExists(ctx context.Context, digests digest.ForRestore, expectedLength int64) (bool, error) {
+	key := c.keyStore.AbsoluteKeyForBlob(digests)
+	now := time.Now()
+	if c.existsCache.Get(digests, c.requiredRetainUntil(now)) {
 		return true, nil
 	}
 
@@ -96,6 +407,7 @@ func (c *awsClient) blobExists(ctx context.Context, digests digest.ForUpload) (b
 		Bucket: &c.keyStore.bucket,
 		Key:    &key,
 	}
+	c.encryption.ApplyHeadObject(headObjectInput)
 	headObjectOutput, err := c.s3Svc.HeadObjectWithContext(ctx, headObjectInput)
 	if err != nil {
 		if IsNoSuchKey(err) {
@@ -110,15 +422,32 @@ func (c *awsClient) blobExists(ctx context.Context, digests digest.ForUpload) (b
 		zap.S().Infow("blob_exists_saw_delete_marker", "key", key)
 		return false, nil
 	}
-	expectedLength := digests.PartDigests().TotalLength()
 	actualLength := *headObjectOutput.ContentLength
 	if actualLength != expectedLength {
 		zap.S().Infow("blob_exists_saw_wrong_length", "key", key, "expected", expectedLength, "actual", actualLength)
 		return false, nil
 	}
+	if storageClass := aws.StringValue(headObjectOutput.StorageClass); isArchiveClass(storageClass) {
+		// A blob that can't be read without restoring it first isn't fit for a new backup; upload it
+		// again, in the storage class uploads use.
+		zap.S().Infow("blob_exists_saw_archived", "key", key, "storage_class", storageClass)
+		return false, nil
+	}
 
-	if headObjectOutput.ObjectLockRetainUntilDate != nil {
-		c.existsCache.Put(digests.ForRestore(), *headObjectOutput.ObjectLockRetainUntilDate)
+	info := headObjectInfo(key, headObjectOutput)
+	if c.objectLock.Enabled() && info.RetainUntil.Before(c.requiredRetainUntil(now)) {
+		// The blob is reused by a manifest that is to be retained for longer than it is.
+		until := c.objectLock.RetainUntil(now)
+		if _, _, err := c.retainBlob(ctx, digests, info, retention{mode: c.objectLock.Mode, until: until}); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return false, ctxErr
+			}
+			return false, err
+		}
+		info.RetainUntil = until
+	}
+	if !info.RetainUntil.IsZero() {
+		c.existsCache.Put(digests, info.RetainUntil)
 	}
 
 	return true, nil
@@ -149,6 +478,24 @@ var (
 		Name:      "upload_files_total",
 		Help:      "Number of files uploaded to the bucket.",
 	})
+	reuploadedFiles = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "cassandrabackup",
+		Subsystem: "bucket",
+		Name:      "pending_delete_reupload_files_total",
+		Help:      "Number of files uploaded again because a prune had marked them for deletion.",
+	})
+	resumedDownloads = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "cassandrabackup",
+		Subsystem: "bucket",
+		Name:      "resumed_downloads_total",
+		Help:      "Number of blob downloads continued from a partial download.",
+	})
+	resumedDownloadBytes = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "cassandrabackup",
+		Subsystem: "bucket",
+		Name:      "resumed_download_bytes_total",
+		Help:      "Bytes of partial downloads that did not need to be downloaded again.",
+	})
 	uploadErrors = prometheus.NewCounter(prometheus.CounterOpts{
 		Namespace: "cassandrabackup",
 		Subsystem: "bucket",
@@ -162,5 +509,8 @@ func init() {
 	prometheus.MustRegister(skippedFiles)
 	prometheus.MustRegister(uploadedBytes)
 	prometheus.MustRegister(uploadedFiles)
+	prometheus.MustRegister(reuploadedFiles)
 	prometheus.MustRegister(uploadErrors)
+	prometheus.MustRegister(resumedDownloads)
+	prometheus.MustRegister(resumedDownloadBytes)
 }
@@ -17,13 +17,12 @@ package bucket
 import (
 	"context"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/alecthomas/kingpin/v2"
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
@@ -31,6 +30,7 @@ import (
 	"github.com/retailnext/cassandrabackup/bucket/safeuploader"
 	"github.com/retailnext/cassandrabackup/cache"
 	"github.com/retailnext/cassandrabackup/digest"
+	"github.com/retailnext/cassandrabackup/envelope"
 	"github.com/retailnext/cassandrabackup/manifests"
 	"github.com/retailnext/cassandrabackup/paranoid"
 	"github.com/retailnext/cassandrabackup/unixtime"
@@ -52,6 +52,9 @@ type Client interface {
 	ListHostNames(ctx context.Context, cluster string) ([]manifests.NodeIdentity, error)
 	ListClusters(ctx context.Context) ([]string, error)
 	DownloadBlob(ctx context.Context, digests digest.ForRestore, file *os.File) error
+	// ResumeDownloadBlob is DownloadBlob for a file that may already hold the start of the blob as stored.
+	ResumeDownloadBlob(ctx context.Context, digests digest.ForRestore, file *os.File) error
+	StatBlob(ctx context.Context, digests digest.ForRestore) (int64, error)
 	PutBlob(ctx context.Context, file paranoid.File, digests digest.ForUpload) error
 	KeyStore() *KeyStore
 }
@@ -62,13 +65,20 @@ type awsClient struct {
 	downloader  s3manageriface.DownloaderAPI
 	existsCache *ExistsCache
 
-	keyStore             KeyStore
-	serverSideEncryption *string
+	keyStore   KeyStore
+	encryption safeuploader.Encryption
+	objectLock safeuploader.ObjectLock
+	keys       *envelope.Keyring
 }
 
 var (
-	bucketName             = kingpin.Flag("s3-bucket", "S3 bucket name.").Required().String()
-	bucketRegion           = kingpin.Flag("s3-region", "S3 bucket region.").Envar("AWS_REGION").Required().String()
+	storageBackend = kingpin.Flag("storage", "Storage backend for backups.").Default("s3").Enum(backendNames()...)
+
+	partUploadConcurrency   = kingpin.Flag("part-upload-concurrency", "Number of parts of each blob to upload at once to S3 or Azure.").Default("4").Int()
+	partDownloadConcurrency = kingpin.Flag("part-download-concurrency", "Number of parts of each blob to download at once from S3.").Default("5").Int()
+
+	bucketName             = kingpin.Flag("s3-bucket", "S3 bucket name.").String()
+	bucketRegion           = kingpin.Flag("s3-region", "S3 bucket region.").Envar("AWS_REGION").String()
 	bucketKeyPrefix        = kingpin.Flag("s3-key-prefix", "Set the prefix for files in the S3 bucket").Default("/").String()
 	bucketBlobStorageClass = kingpin.Flag("s3-storage-class", "Set the storage class for files in S3").Default(s3.StorageClassStandardIa).String()
 )
@@ -82,18 +92,52 @@ func GetBucketFlags() (*string, *string) {
 	return bucketName, bucketRegion
 }
 
+// backends maps the values accepted by --storage to the constructor for that backend.
+var backends = map[string]func() Client{
+	"s3":    func() Client { return newAWSClient() },
+	"gcs":   func() Client { return newGCSClient() },
+	"azure": func() Client { return newAzureClient() },
+	"file":  func() Client { return newFileClient() },
+}
+
+func backendNames() []string {
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func OpenShared() Client {
 	once.Do(func() {
-		Shared = newAWSClient()
+		Shared = backends[*storageBackend]()
 	})
 	return Shared
 }
 
 func newAWSClient() *awsClient {
+	return newAWSClientFor(*bucketName, *bucketRegion, *bucketKeyPrefix, "")
+}
+
+// newAWSClientFor opens a bucket configured by the S3 flags other than its location. The local
+// caches of blobs known to exist and of uploads in progress are keyed by digest and object key, so
+// cacheSuffix keeps those of a second bucket apart from the main one's.
+func newAWSClientFor(name, region, keyPrefix, cacheSuffix string) *awsClient {
+	if name == "" || (region == "" && *s3Endpoint == "") {
+		zap.S().Fatalw("s3_bucket_and_region_required", "bucket", name, "region", region)
+	}
+	encryption, err := s3Encryption(*s3SSE, *s3SSEKMSKeyID, *s3SSEBucketKey, *s3SSECustomerKey)
+	if err != nil {
+		zap.S().Fatalw("s3_sse_config_error", "err", err)
+	}
+	objectLock, err := s3ObjectLock(*s3ObjectLockMode, *s3ObjectLockRetention)
+	if err != nil {
+		zap.S().Fatalw("s3_object_lock_config_error", "err", err)
+	}
 	cache.OpenShared()
 
-	awsConf := aws.NewConfig().WithRegion(*bucketRegion)
-	awsSession, err := session.NewSession(awsConf)
+	awsSession, err := newAWSSession(region)
 	if err != nil {
 		zap.S().Fatalw("aws_new_session_error", "err", err)
 	}
@@ -102,38 +146,59 @@ func newAWSClient() *awsClient {
 	c := &awsClient{
 		s3Svc: s3Svc,
 		uploader: &safeuploader.SafeUploader{
-			S3:                   s3Svc,
-			Bucket:               *bucketName,
-			ServerSideEncryption: aws.String(s3.ServerSideEncryptionAes256),
-			StorageClass:         bucketBlobStorageClass,
+			S3:           s3Svc,
+			Bucket:       name,
+			Encryption:   encryption,
+			ObjectLock:   objectLock,
+			StorageClass: bucketBlobStorageClass,
+			Concurrency:  *partUploadConcurrency,
+			ResumeCache:  cache.Shared.Cache("multipart_uploads" + cacheSuffix),
 		},
 		downloader: s3manager.NewDownloaderWithClient(s3Svc, func(d *s3manager.Downloader) {
 			d.PartSize = 64 * 1024 * 1024 // 64MB per part
+			d.Concurrency = *partDownloadConcurrency
 		}),
 		existsCache: &ExistsCache{
-			cache: cache.Shared.Cache("bucket_exists"),
+			cache: cache.Shared.Cache("bucket_exists" + cacheSuffix),
 		},
-		keyStore:             newKeyStore(*bucketName, strings.Trim(*bucketKeyPrefix, "/")),
-		serverSideEncryption: aws.String(s3.ServerSideEncryptionAes256),
+		keyStore:   newKeyStore(name, strings.Trim(keyPrefix, "/")),
+		encryption: encryption,
+		objectLock: objectLock,
+		keys:       openKeyring(),
 	}
-	c.validateEncryptionConfiguration()
+	c.validateEncryptionConfiguration(*s3SSE)
+	c.validateObjectLockConfiguration()
 	return c
 }
 
-func (c *awsClient) validateEncryptionConfiguration() {
+// validateEncryptionConfiguration checks that the bucket encrypts objects by default, as a backstop
+// for the encryption every upload asks for, and with --s3-sse=aws:kms that the default is the same
+// KMS key, so that objects written without the tool are readable with the same grants. It is
+// skipped when --s3-sse deliberately opts out of that: with none, for stores without encryption at
+// rest, and with SSE-C, where the key is ours and the bucket default doesn't apply. Stores that
+// don't implement the check are trusted to honor the encryption uploads ask for.
+func (c *awsClient) validateEncryptionConfiguration(mode string) {
+	lgr := zap.S()
+	switch mode {
+	case sseNone:
+		lgr.Warnw("s3_sse_disabled", "bucket", c.keyStore.bucket)
+		return
+	case sseCustomer:
+		lgr.Infow("skip_bucket_encryption_check", "sse", mode)
+		return
+	}
 	input := &s3.GetBucketEncryptionInput{
 		Bucket: &c.keyStore.bucket,
 	}
 	output, err := c.s3Svc.GetBucketEncryption(input)
 	if err != nil {
-		zap.S().Fatalw("failed_to_validate_bucket_encryption", "err", err)
-	}
-	for _, rule := range output.ServerSideEncryptionConfiguration.Rules {
-		if rule.ApplyServerSideEncryptionByDefault != nil {
-			if rule.ApplyServerSideEncryptionByDefault.SSEAlgorithm != nil {
-				return
-			}
+		if isNotImplemented(err) {
+			lgr.Warnw("bucket_encryption_check_not_supported", "sse", mode, "err", err)
+			return
 		}
+		lgr.Fatalw("failed_to_validate_bucket_encryption", "err", err)
+	}
+	if err := checkBucketEncryption(output.ServerSideEncryptionConfiguration, c.encryption); err != nil {
+		lgr.Fatalw("bucket_encryption_mismatch", "bucket", c.keyStore.bucket, "err", err)
 	}
-	zap.S().Fatalw("bucket_not_configured_with_sse_algorithm", "bucket", c.keyStore.bucket)
 }
@@ -0,0 +1,327 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bucket
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/retailnext/cassandrabackup/digest"
+	"github.com/retailnext/cassandrabackup/manifests"
+	"github.com/retailnext/cassandrabackup/paranoid"
+)
+
+// testClientBlobs and testClientManifests exercise the behavior every backend must share.
+// Backends that need an emulator run them only when one is configured.
+
+func testClientBlobs(t *testing.T, c Client) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	srcPath := filepath.Join(dir, "src")
+	data := make([]byte, 100*1024)
+	if _, err := rand.Read(data); err != nil {
+		panic(err)
+	}
+	if err := os.WriteFile(srcPath, data, 0o644); err != nil {
+		panic(err)
+	}
+	src, err := paranoid.NewFile(srcPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digests, err := digest.GetUncached(ctx, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.PutBlob(ctx, src, digests); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.PutBlob(ctx, src, digests); err != UploadSkipped {
+		t.Fatalf("expected UploadSkipped got %v", err)
+	}
+
+	dst, err := os.Create(filepath.Join(dir, "dst"))
+	if err != nil {
+		panic(err)
+	}
+	defer func() {
+		_ = dst.Close()
+	}()
+	if err := c.DownloadBlob(ctx, digests.ForRestore(), dst); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := os.ReadFile(dst.Name())
+	if err != nil {
+		panic(err)
+	}
+	if !bytes.Equal(data, restored) {
+		t.Fatal("restored data mismatch")
+	}
+
+	if size, err := c.StatBlob(ctx, digests.ForRestore()); err != nil {
+		t.Fatal(err)
+	} else if size < int64(len(data)) {
+		t.Fatalf("unexpected blob size %d", size)
+	}
+
+	testClientResumeDownload(t, c, digests.ForRestore(), data)
+	testClientResumeDownloadRetries(t, c, digests.ForRestore(), data)
+
+	var missing digest.ForRestore
+	if err := c.DownloadBlob(ctx, missing, dst); !IsNoSuchKey(err) {
+		t.Fatalf("expected no such key got %v", err)
+	}
+	if _, err := c.StatBlob(ctx, missing); !IsNoSuchKey(err) {
+		t.Fatalf("expected no such key got %v", err)
+	}
+}
+
+// testClientResumeDownload continues downloads from partial copies of the stored object.
+func testClientResumeDownload(t *testing.T, c Client, digests digest.ForRestore, data []byte) {
+	ctx := context.Background()
+	store := c.(blobStore)
+	var stored bytes.Buffer
+	if err := store.downloadBlobFrom(ctx, store.KeyStore().AbsoluteKeyForBlob(digests), &stored, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	half := stored.Len() / 2
+	corrupt := append([]byte{}, stored.Bytes()[:half]...)
+	corrupt[0] ^= 0xff
+	cases := map[string][]byte{
+		"empty":    nil,
+		"half":     stored.Bytes()[:half],
+		"complete": stored.Bytes(),
+		"too_long": append(append([]byte{}, stored.Bytes()...), 0),
+	}
+	for name, partial := range cases {
+		t.Run(name, func(t *testing.T) {
+			dst := filepath.Join(t.TempDir(), "partial")
+			if err := os.WriteFile(dst, partial, 0o644); err != nil {
+				panic(err)
+			}
+			file, err := os.OpenFile(dst, os.O_RDWR, 0)
+			if err != nil {
+				panic(err)
+			}
+			defer func() {
+				_ = file.Close()
+			}()
+			if err := c.ResumeDownloadBlob(ctx, digests, file); err != nil {
+				t.Fatal(err)
+			}
+			restored, err := os.ReadFile(dst)
+			if err != nil {
+				panic(err)
+			}
+			if !bytes.Equal(data, restored) {
+				t.Fatal("restored data mismatch")
+			}
+		})
+	}
+
+	t.Run("corrupt", func(t *testing.T) {
+		dst := filepath.Join(t.TempDir(), "partial")
+		if err := os.WriteFile(dst, corrupt, 0o644); err != nil {
+			panic(err)
+		}
+		file, err := os.OpenFile(dst, os.O_RDWR, 0)
+		if err != nil {
+			panic(err)
+		}
+		defer func() {
+			_ = file.Close()
+		}()
+		if err := c.ResumeDownloadBlob(ctx, digests, file); err == nil {
+			t.Fatal("expected an error resuming from a corrupt partial download")
+		}
+		if info, err := file.Stat(); err != nil {
+			panic(err)
+		} else if info.Size() != 0 {
+			t.Fatalf("expected the corrupt partial download to be discarded, got %d bytes", info.Size())
+		}
+		if err := c.ResumeDownloadBlob(ctx, digests, file); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+// flakyStore fails the first failures downloads after writing at most chunk bytes of them.
+type flakyStore struct {
+	blobStore
+	chunk    int64
+	failures int
+	offsets  []int64
+}
+
+var errFlaky = errors.New("connection reset")
+
+func (s *flakyStore) downloadBlobFrom(ctx context.Context, key string, w io.Writer, offset int64) error {
+	s.offsets = append(s.offsets, offset)
+	if len(s.offsets) > s.failures {
+		return s.blobStore.downloadBlobFrom(ctx, key, w, offset)
+	}
+	var rest bytes.Buffer
+	if err := s.blobStore.downloadBlobFrom(ctx, key, &rest, offset); err != nil {
+		return err
+	}
+	if _, err := w.Write(rest.Bytes()[:min(s.chunk, int64(rest.Len()))]); err != nil {
+		return err
+	}
+	return errFlaky
+}
+
+// testClientResumeDownloadRetries checks that downloads failing partway through are continued from
+// where they stopped, and that what they got is kept when they give up.
+func testClientResumeDownloadRetries(t *testing.T, c Client, digests digest.ForRestore, data []byte) {
+	ctx := context.Background()
+	store := c.(blobStore)
+	info, err := store.statObject(ctx, store.KeyStore().AbsoluteKeyForBlob(digests))
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunk := info.Size / 3
+
+	t.Run("retried", func(t *testing.T) {
+		file, err := os.Create(filepath.Join(t.TempDir(), "partial"))
+		if err != nil {
+			panic(err)
+		}
+		defer func() {
+			_ = file.Close()
+		}()
+		flaky := &flakyStore{blobStore: store, chunk: chunk, failures: 2}
+		if err := resumeDownloadBlob(ctx, flaky, digests, file); err != nil {
+			t.Fatal(err)
+		}
+		if diff := deep.Equal(flaky.offsets, []int64{0, chunk, 2 * chunk}); diff != nil {
+			t.Error(diff)
+		}
+		restored, err := os.ReadFile(file.Name())
+		if err != nil {
+			panic(err)
+		}
+		if !bytes.Equal(data, restored) {
+			t.Fatal("restored data mismatch")
+		}
+	})
+
+	t.Run("gave_up", func(t *testing.T) {
+		file, err := os.Create(filepath.Join(t.TempDir(), "partial"))
+		if err != nil {
+			panic(err)
+		}
+		defer func() {
+			_ = file.Close()
+		}()
+		flaky := &flakyStore{blobStore: store, chunk: 1, failures: getBlobRetriesLimit + 1}
+		if err := resumeDownloadBlob(ctx, flaky, digests, file); !errors.Is(err, errFlaky) {
+			t.Fatalf("expected the download error, got %v", err)
+		}
+		if fileInfo, err := file.Stat(); err != nil {
+			panic(err)
+		} else if fileInfo.Size() != int64(getBlobRetriesLimit+1) {
+			t.Fatalf("expected the partial download to be kept, got %d bytes", fileInfo.Size())
+		}
+		if err := resumeDownloadBlob(ctx, store, digests, file); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func testClientManifests(t *testing.T, c Client) {
+	ctx := context.Background()
+
+	identity := manifests.NodeIdentity{
+		Cluster:  t.Name(),
+		Hostname: "cassandra-1",
+	}
+	m1 := manifests.Manifest{
+		Time:         100,
+		ManifestType: manifests.ManifestTypeSnapshot,
+		HostID:       "foobar",
+		Tokens:       []string{"1"},
+		DataFiles:    map[string]digest.ForRestore{},
+	}
+	m2 := m1
+	m2.Time = 200
+	m2.ManifestType = manifests.ManifestTypeIncremental
+	m3 := m1
+	m3.Time = 300
+	for _, m := range []manifests.Manifest{m1, m2, m3} {
+		if err := c.PutManifest(ctx, identity, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	keys, err := c.ListManifests(ctx, identity, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal(keys, manifests.ManifestKeys{m1.Key(), m2.Key(), m3.Key()}); diff != nil {
+		t.Fatal(diff)
+	}
+
+	// Same boundary semantics as S3 StartAfter on the time-prefixed key names.
+	keys, err = c.ListManifests(ctx, identity, 200, 300)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal(keys, manifests.ManifestKeys{m2.Key()}); diff != nil {
+		t.Fatal(diff)
+	}
+
+	got, err := c.GetManifests(ctx, identity, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal(got, []manifests.Manifest{m2}); diff != nil {
+		t.Fatal(diff)
+	}
+
+	hosts, err := c.ListHostNames(ctx, identity.Cluster)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal(hosts, []manifests.NodeIdentity{identity}); diff != nil {
+		t.Fatal(diff)
+	}
+
+	clusters, err := c.ListClusters(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	for _, cluster := range clusters {
+		found = found || cluster == identity.Cluster
+	}
+	if !found {
+		t.Fatalf("cluster %q not in %v", identity.Cluster, clusters)
+	}
+
+	hosts, err = c.ListHostNames(ctx, "missing-cluster")
+	if err != nil || len(hosts) != 0 {
+		t.Fatalf("expected no hosts got %v %v", hosts, err)
+	}
+}
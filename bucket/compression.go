@@ -0,0 +1,164 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bucket
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/retailnext/cassandrabackup/compression"
+	"github.com/retailnext/cassandrabackup/digest/parts"
+	"go.uber.org/zap"
+)
+
+var compressionTempDir = kingpin.Flag("compression-temp-dir", "Directory for the compressed copies of blobs being uploaded or downloaded.").Default(os.TempDir()).ExistingDir()
+
+// compressedFile is a temporary compressed copy of a file being uploaded.
+type compressedFile struct {
+	*os.File
+	partDigests *parts.PartDigests
+}
+
+func (f *compressedFile) Close() {
+	name := f.Name()
+	if err := f.File.Close(); err != nil {
+		zap.S().Errorw("compression_temp_close_error", "name", name, "err", err)
+	}
+	if err := os.Remove(name); err != nil {
+		zap.S().Errorw("compression_temp_remove_error", "name", name, "err", err)
+	}
+}
+
+// compressFile writes a compressed copy of src, from its current offset, to a temporary file. The digest
+// cache already holds part digests for the compressed stream; they are recomputed on the way so that
+// the upload is checked against what is actually sent.
+func compressFile(ctx context.Context, src io.Reader, codec compression.Codec, partSize int64) (*compressedFile, error) {
+	tmp, err := os.CreateTemp(*compressionTempDir, "cassandrabackup-upload-*")
+	if err != nil {
+		return nil, err
+	}
+	result := &compressedFile{File: tmp}
+	var maker parts.PartDigestsMaker
+	maker.Reset(uint64(partSize))
+	w, err := compression.NewWriter(io.MultiWriter(tmp, &maker), codec)
+	if err == nil {
+		_, err = io.Copy(w, contextReader{ctx: ctx, r: src})
+		if closeErr := w.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		result.Close()
+		return nil, err
+	}
+	pd := maker.Finish()
+	result.partDigests = &pd
+	return result, nil
+}
+
+func samePartDigests(a, b *parts.PartDigests) bool {
+	aBinary, aErr := a.MarshalBinary()
+	bBinary, bErr := b.MarshalBinary()
+	return aErr == nil && bErr == nil && bytes.Equal(aBinary, bBinary)
+}
+
+// decompressBlob decompresses a downloaded blob in place if it was compressed.
+func decompressBlob(ctx context.Context, file *os.File) error {
+	header := make([]byte, compression.HeaderSize)
+	if n, _ := file.ReadAt(header, 0); compression.StreamCodec(header[:n]) == compression.None {
+		return nil
+	}
+
+	tmp, err := os.CreateTemp(*compressionTempDir, "cassandrabackup-download-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := tmp.Close(); closeErr != nil {
+			zap.S().Errorw("compression_temp_close_error", "name", tmp.Name(), "err", closeErr)
+		}
+		if removeErr := os.Remove(tmp.Name()); removeErr != nil {
+			zap.S().Errorw("compression_temp_remove_error", "name", tmp.Name(), "err", removeErr)
+		}
+	}()
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := io.Copy(tmp, contextReader{ctx: ctx, r: file}); err != nil {
+		return err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	r, err := compression.NewReader(tmp)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if err := file.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, contextReader{ctx: ctx, r: r}); err != nil {
+		return err
+	}
+	_, err = file.Seek(0, io.SeekStart)
+	return err
+}
+
+func recordCompression(uncompressed, compressed int64) {
+	compressionInputBytes.Add(float64(uncompressed))
+	compressionOutputBytes.Add(float64(compressed))
+	if compressed > 0 {
+		compressionRatio.Observe(float64(uncompressed) / float64(compressed))
+	}
+}
+
+var (
+	compressionInputBytes = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "cassandrabackup",
+		Subsystem: "bucket",
+		Name:      "compression_input_bytes_total",
+		Help:      "Total uncompressed size of compressed blobs uploaded to the bucket.",
+	})
+	compressionOutputBytes = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "cassandrabackup",
+		Subsystem: "bucket",
+		Name:      "compression_output_bytes_total",
+		Help:      "Total compressed size of compressed blobs uploaded to the bucket.",
+	})
+	compressionRatio = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "cassandrabackup",
+		Subsystem: "bucket",
+		Name:      "compression_ratio",
+		Help:      "Ratio of uncompressed to compressed size of each compressed blob uploaded.",
+		Buckets:   []float64{1, 1.25, 1.5, 2, 3, 4, 6, 8, 12, 16},
+	})
+)
+
+func init() {
+	prometheus.MustRegister(compressionInputBytes)
+	prometheus.MustRegister(compressionOutputBytes)
+	prometheus.MustRegister(compressionRatio)
+}
@@ -0,0 +1,121 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bucket
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/retailnext/cassandrabackup/compression"
+	"github.com/retailnext/cassandrabackup/digest"
+	"github.com/retailnext/cassandrabackup/envelope"
+	"github.com/retailnext/cassandrabackup/paranoid"
+)
+
+func TestFileClientCompressed(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "nb-1-big-Index.db")
+	data := bytes.Repeat([]byte("some highly compressible sstable index\n"), 100000)
+	if err := os.WriteFile(name, data, 0o644); err != nil {
+		panic(err)
+	}
+	file, err := paranoid.NewFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	digests, err := digest.GetUncachedWithCodec(ctx, file, compression.Zstd)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, encrypted := range []bool{false, true} {
+		c := &fileClient{keyStore: newKeyStore(t.TempDir(), "")}
+		if encrypted {
+			c.keys = testKeyring(t, testKeyFile(t, "k1"), "")
+		}
+		if err := c.PutBlob(ctx, file, digests); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.PutBlob(ctx, file, digests); err != UploadSkipped {
+			t.Fatalf("expected UploadSkipped got %v", err)
+		}
+
+		stored, err := c.getObject(ctx, c.keyStore.AbsoluteKeyForBlob(digests.ForRestore()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if encrypted != envelope.IsSealed(stored) {
+			t.Fatalf("encrypted=%v but sealed=%v", encrypted, !encrypted)
+		}
+		if !encrypted && compression.StreamCodec(stored) != compression.Zstd {
+			t.Fatal("blob stored uncompressed")
+		}
+		if len(stored) >= len(data)/10 {
+			t.Fatalf("stored %d bytes for %d bytes of data", len(stored), len(data))
+		}
+
+		dst, err := os.Create(filepath.Join(t.TempDir(), "dst"))
+		if err != nil {
+			panic(err)
+		}
+		if err := c.DownloadBlob(ctx, digests.ForRestore(), dst); err != nil {
+			t.Fatal(err)
+		}
+		_ = dst.Close()
+		got, err := os.ReadFile(dst.Name())
+		if err != nil {
+			panic(err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Fatal("round trip mismatch")
+		}
+	}
+}
+
+func TestPutBlobCompressedChanged(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	var files []paranoid.File
+	for i, line := range []string{"some highly compressible sstable index\n", "another sstable index\n"} {
+		name := filepath.Join(dir, fmt.Sprintf("nb-%d-big-Index.db", i))
+		if err := os.WriteFile(name, bytes.Repeat([]byte(line), 10000), 0o644); err != nil {
+			panic(err)
+		}
+		file, err := paranoid.NewFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, file)
+	}
+	// Digests that describe a different compressed stream than the file compresses to, as cached
+	// digests do after the compression library changes.
+	digests, err := digest.GetUncachedWithCodec(ctx, files[0], compression.Zstd)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &fileClient{keyStore: newKeyStore(t.TempDir(), "")}
+	if err := c.PutBlob(ctx, files[1], digests); !errors.Is(err, ErrCompressedBlobChanged) {
+		t.Fatalf("expected ErrCompressedBlobChanged got %v", err)
+	}
+	if _, err := c.StatBlob(ctx, digests.ForRestore()); !IsNoSuchKey(err) {
+		t.Fatalf("expected nothing to be stored, got %v", err)
+	}
+}
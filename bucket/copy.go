@@ -0,0 +1,357 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bucket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/retailnext/cassandrabackup/bucket/safeuploader"
+	"github.com/retailnext/cassandrabackup/digest"
+	"github.com/retailnext/cassandrabackup/digest/parts"
+	"github.com/retailnext/cassandrabackup/manifests"
+	"go.uber.org/zap"
+)
+
+// copyPartSize is the part size of blobs copied through this host, the same as backups upload with.
+const copyPartSize = 64 * 1024 * 1024
+
+// Destination is a second store to copy backups to. It is configured by the same flags as the main
+// store other than its location, and only the s3 and file backends are supported.
+type Destination struct {
+	Storage     string
+	S3Bucket    string
+	S3Region    string
+	S3KeyPrefix string
+	FileRoot    string
+}
+
+func OpenDestination(d Destination) (Client, error) {
+	switch d.Storage {
+	case "s3":
+		region := d.S3Region
+		if region == "" {
+			region = *bucketRegion
+		}
+		return newAWSClientFor(d.S3Bucket, region, d.S3KeyPrefix, ":"+d.S3Bucket+"/"+strings.Trim(d.S3KeyPrefix, "/")), nil
+	case "file":
+		return newFileClientAt(d.FileRoot), nil
+	default:
+		return nil, fmt.Errorf("copying to the %s storage backend is not supported", d.Storage)
+	}
+}
+
+type CopyResult struct {
+	Manifests         int
+	ExistingManifests int
+	FailedManifests   int
+	Blobs             int
+	ExistingBlobs     int
+	FailedBlobs       int
+	// Bytes is the stored size of the blobs copied.
+	Bytes int64
+}
+
+// Copier copies manifests and the blobs they reference from one store to another, as stored: blobs
+// stay compressed and sealed, and their data keys are copied alongside, so the destination needs
+// the same master keys to be restored from. Blobs go server side when both stores are S3, and are
+// otherwise downloaded, verified against their digests and uploaded again.
+type Copier struct {
+	src         copyStore
+	dst         copyStore
+	concurrency int
+	tempDir     string
+	dryRun      bool
+
+	lock   sync.Mutex
+	blobs  map[digest.ForRestore]error
+	Result CopyResult
+}
+
+type copyStore interface {
+	Client
+	blobStore
+}
+
+func NewCopier(src, dst Client, concurrency int, tempDir string, dryRun bool) (*Copier, error) {
+	srcStore, srcOK := src.(copyStore)
+	dstStore, dstOK := dst.(copyStore)
+	if !srcOK || !dstOK {
+		return nil, errors.New("unsupported storage backend")
+	}
+	if *src.KeyStore() == *dst.KeyStore() {
+		return nil, errors.New("the destination is the same as the source")
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &Copier{
+		src:         srcStore,
+		dst:         dstStore,
+		concurrency: concurrency,
+		tempDir:     tempDir,
+		dryRun:      dryRun,
+		blobs:       make(map[digest.ForRestore]error),
+	}, nil
+}
+
+// Copy copies the given manifests of a host in order, each once all the blobs it references are in
+// the destination, so that the destination never has a manifest it can't be restored from. A
+// manifest with a blob that failed to copy is left out, and manifests already in the destination
+// are skipped. Failures are logged and counted in Result rather than returned.
+func (c *Copier) Copy(ctx context.Context, identity manifests.NodeIdentity, keys manifests.ManifestKeys) error {
+	lgr := zap.S().With("identity", identity)
+	existingKeys, err := c.dst.ListManifests(ctx, identity, 0, 0)
+	if err != nil {
+		return err
+	}
+	existing := make(map[manifests.ManifestKey]struct{}, len(existingKeys))
+	for _, key := range existingKeys {
+		existing[key] = struct{}{}
+	}
+	var missing manifests.ManifestKeys
+	for _, key := range keys {
+		if _, ok := existing[key]; ok {
+			c.Result.ExistingManifests++
+		} else {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	got, err := c.src.GetManifests(ctx, identity, missing)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range got {
+		referenced := make([]digest.ForRestore, 0, len(m.DataFiles)+1)
+		for _, file := range m.DataFiles {
+			referenced = append(referenced, file)
+		}
+		if m.Schema != nil {
+			referenced = append(referenced, *m.Schema)
+		}
+		if err := c.copyBlobs(ctx, referenced); err != nil {
+			return err
+		}
+		manifestLgr := lgr.With("manifest", m.Key().FileName())
+		failed := 0
+		for _, digests := range referenced {
+			if c.blobs[digests] != nil {
+				failed++
+			}
+		}
+		if failed > 0 {
+			manifestLgr.Errorw("copy_manifest_skipped", "failed_blobs", failed)
+			c.Result.FailedManifests++
+			continue
+		}
+		if c.dryRun {
+			manifestLgr.Infow("would_copy_manifest")
+		} else if err := c.dst.PutManifest(ctx, identity, m); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			manifestLgr.Errorw("copy_manifest_error", "err", err)
+			c.Result.FailedManifests++
+			continue
+		}
+		c.Result.Manifests++
+	}
+	return nil
+}
+
+// copyBlobs copies the blobs that no earlier manifest referenced, a few at a time.
+func (c *Copier) copyBlobs(ctx context.Context, blobs []digest.ForRestore) error {
+	var wg sync.WaitGroup
+	limiter := make(chan struct{}, c.concurrency)
+	doneCh := ctx.Done()
+schedule:
+	for _, digests := range blobs {
+		c.lock.Lock()
+		_, seen := c.blobs[digests]
+		if !seen {
+			// Blobs that already failed keep their error, so later manifests referencing them are
+			// skipped too.
+			c.blobs[digests] = nil
+		}
+		c.lock.Unlock()
+		if seen {
+			continue
+		}
+		select {
+		case <-doneCh:
+			break schedule
+		case limiter <- struct{}{}:
+		}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-limiter
+				wg.Done()
+			}()
+			err := c.copyBlob(ctx, digests)
+			if err != nil && ctx.Err() == nil {
+				zap.S().Errorw("copy_blob_error", "key", c.src.KeyStore().AbsoluteKeyForBlob(digests), "err", err)
+			}
+			c.lock.Lock()
+			defer c.lock.Unlock()
+			c.blobs[digests] = err
+			if err != nil {
+				c.Result.FailedBlobs++
+			}
+		}()
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+// copyBlob copies a blob unless the destination already has it, its data keys first as uploads do.
+func (c *Copier) copyBlob(ctx context.Context, digests digest.ForRestore) error {
+	srcKey := c.src.KeyStore().AbsoluteKeyForBlob(digests)
+	dstKey := c.dst.KeyStore().AbsoluteKeyForBlob(digests)
+	info, err := c.src.statObject(ctx, srcKey)
+	if err != nil {
+		return err
+	}
+	// Blobs that a prune of the destination is about to delete are copied again, as backups would.
+	if pending, err := isPendingDelete(ctx, c.dst, digests); err != nil {
+		return err
+	} else if !pending {
+		if exists, err := c.dstHasBlob(ctx, digests, info.Size); err != nil {
+			return err
+		} else if exists {
+			c.lock.Lock()
+			c.Result.ExistingBlobs++
+			c.lock.Unlock()
+			return nil
+		}
+	}
+
+	if !c.dryRun {
+		if err := c.copyDataKeys(ctx, digests); err != nil {
+			return err
+		}
+		src, srcS3 := c.src.(*awsClient)
+		dst, dstS3 := c.dst.(*awsClient)
+		if srcS3 && dstS3 {
+			err = dst.uploader.Copy(ctx, dstKey, safeuploader.Source{
+				Bucket:     src.keyStore.bucket,
+				Key:        srcKey,
+				Size:       info.Size,
+				Encryption: src.encryption,
+			})
+		} else {
+			err = c.streamBlob(ctx, digests, srcKey, dstKey)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	c.lock.Lock()
+	c.Result.Blobs++
+	c.Result.Bytes += info.Size
+	c.lock.Unlock()
+	return nil
+}
+
+// dstHasBlob is blobExists, other than for a dry run, which only looks: blobExists may extend the
+// retention of the blob in the destination.
+func (c *Copier) dstHasBlob(ctx context.Context, digests digest.ForRestore, size int64) (bool, error) {
+	if !c.dryRun {
+		return c.dst.blobExists(ctx, digests, size)
+	}
+	info, err := c.dst.statObject(ctx, c.dst.KeyStore().AbsoluteKeyForBlob(digests))
+	if IsNoSuchKey(err) {
+		return false, nil
+	}
+	return err == nil && info.Size == size, err
+}
+
+func (c *Copier) copyDataKeys(ctx context.Context, digests digest.ForRestore) error {
+	srcPrefix := c.src.KeyStore().absoluteKeyPrefixForBlobDataKeys(digests)
+	dstPrefix := c.dst.KeyStore().absoluteKeyPrefixForBlobDataKeys(digests)
+	dataKeys, err := c.src.listObjects(ctx, srcPrefix)
+	if err != nil {
+		return err
+	}
+	for _, dataKey := range dataKeys {
+		data, err := c.src.getObject(ctx, dataKey.Key)
+		if err != nil {
+			return err
+		}
+		if err := c.dst.putObject(ctx, dstPrefix+strings.TrimPrefix(dataKey.Key, srcPrefix), data, "application/octet-stream", ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// streamBlob downloads the blob as stored, checks that it opens to the content its digests
+// describe, and uploads it as stored.
+func (c *Copier) streamBlob(ctx context.Context, digests digest.ForRestore, srcKey, dstKey string) error {
+	stored, err := c.createTemp()
+	if err != nil {
+		return err
+	}
+	defer c.removeTemp(stored)
+	if err := c.src.downloadBlob(ctx, srcKey, stored); err != nil {
+		return err
+	}
+	size, err := stored.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+
+	opened, err := c.createTemp()
+	if err != nil {
+		return err
+	}
+	defer c.removeTemp(opened)
+	if _, err := io.Copy(opened, contextReader{ctx: ctx, r: io.NewSectionReader(stored, 0, size)}); err != nil {
+		return err
+	}
+	if err := finishDownload(ctx, c.src, srcKey, digests, opened); err != nil {
+		return err
+	}
+
+	var maker parts.PartDigestsMaker
+	maker.Reset(copyPartSize)
+	if _, err := io.Copy(&maker, contextReader{ctx: ctx, r: io.NewSectionReader(stored, 0, size)}); err != nil {
+		return err
+	}
+	partDigests := maker.Finish()
+	return c.dst.uploadBlob(ctx, dstKey, stored, &partDigests)
+}
+
+func (c *Copier) createTemp() (*os.File, error) {
+	return os.CreateTemp(c.tempDir, "cassandrabackup-copy-*")
+}
+
+func (c *Copier) removeTemp(file *os.File) {
+	if closeErr := file.Close(); closeErr != nil {
+		zap.S().Errorw("copy_temp_close_error", "name", file.Name(), "err", closeErr)
+	}
+	if removeErr := os.Remove(file.Name()); removeErr != nil {
+		zap.S().Errorw("copy_temp_remove_error", "name", file.Name(), "err", removeErr)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bucket
+
+import (
+	"context"
+	"crypto/rand"
+	"os"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/retailnext/cassandrabackup/digest"
+	"github.com/retailnext/cassandrabackup/manifests"
+)
+
+func TestCopier(t *testing.T) {
+	ctx := context.Background()
+	keyFile := testKeyFile(t, "k1")
+	src := &fileClient{
+		keyStore: newKeyStore(t.TempDir(), ""),
+		keys:     testKeyring(t, keyFile, ""),
+	}
+	dst := &fileClient{
+		keyStore: newKeyStore(t.TempDir(), ""),
+		keys:     testKeyring(t, keyFile, ""),
+	}
+
+	var blobs []digest.ForRestore
+	for i := 0; i < 3; i++ {
+		file, digests := testBlobFile(t, 10*1024)
+		if err := src.PutBlob(ctx, file, digests); err != nil {
+			t.Fatal(err)
+		}
+		blobs = append(blobs, digests.ForRestore())
+	}
+	// The third blob is damaged in the source, so the manifests that reference it can't be copied.
+	corrupt := src.path(src.keyStore.AbsoluteKeyForBlob(blobs[2]))
+	info, err := os.Stat(corrupt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	garbage := make([]byte, info.Size())
+	if _, err := rand.Read(garbage); err != nil {
+		panic(err)
+	}
+	if err := os.WriteFile(corrupt, garbage, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	identity := manifests.NodeIdentity{Cluster: "c", Hostname: "h"}
+	m1 := manifests.Manifest{
+		Time:         100,
+		ManifestType: manifests.ManifestTypeSnapshot,
+		DataFiles:    map[string]digest.ForRestore{"ks/t/a-Data.db": blobs[0]},
+		Schema:       &blobs[1],
+	}
+	m2 := manifests.Manifest{
+		Time:         200,
+		ManifestType: manifests.ManifestTypeIncremental,
+		DataFiles:    map[string]digest.ForRestore{"ks/t/a-Data.db": blobs[0], "ks/t/b-Data.db": blobs[2]},
+	}
+	// A later manifest referencing the failed blob is skipped as well.
+	m3 := manifests.Manifest{
+		Time:         300,
+		ManifestType: manifests.ManifestTypeIncremental,
+		DataFiles:    map[string]digest.ForRestore{"ks/t/b-Data.db": blobs[2]},
+	}
+	for _, m := range []manifests.Manifest{m1, m2, m3} {
+		if err := src.PutManifest(ctx, identity, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	keys := manifests.ManifestKeys{m1.Key(), m2.Key(), m3.Key()}
+
+	copier, err := NewCopier(src, dst, 2, t.TempDir(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := copier.Copy(ctx, identity, keys); err != nil {
+		t.Fatal(err)
+	}
+	bytes := copier.Result.Bytes
+	if diff := deep.Equal(copier.Result, CopyResult{Manifests: 1, FailedManifests: 2, Blobs: 2, FailedBlobs: 1, Bytes: bytes}); diff != nil {
+		t.Error(diff)
+	}
+	got, err := dst.ListManifests(ctx, identity, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal(got, manifests.ManifestKeys{m1.Key()}); diff != nil {
+		t.Error(diff)
+	}
+	for _, digests := range blobs[:2] {
+		file, err := os.CreateTemp(t.TempDir(), "blob")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := dst.DownloadBlob(ctx, digests, file); err != nil {
+			t.Error(err)
+		}
+		_ = file.Close()
+	}
+
+	// Copying again finds the first manifest and its blobs already there.
+	copier, err = NewCopier(src, dst, 2, t.TempDir(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := copier.Copy(ctx, identity, keys); err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal(copier.Result, CopyResult{ExistingManifests: 1, FailedManifests: 2, ExistingBlobs: 1, FailedBlobs: 1}); diff != nil {
+		t.Error(diff)
+	}
+
+	if _, err := NewCopier(src, src, 1, "", false); err == nil {
+		t.Error("expected copying to the source to be refused")
+	}
+}
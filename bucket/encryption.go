@@ -0,0 +1,92 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bucket
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/retailnext/cassandrabackup/digest"
+	"github.com/retailnext/cassandrabackup/envelope"
+	"go.uber.org/zap"
+)
+
+// Client-side encryption is enabled by configuring a master key. When both a key file and a KMS key
+// are given, new data keys are wrapped with KMS and the key file is only used to read older keys.
+var (
+	encryptionKeyFile   = kingpin.Flag("encryption-key-file", "Encrypt blobs and manifests client-side with data keys wrapped by master keys from this file.").String()
+	encryptionKeyID     = kingpin.Flag("encryption-key-id", "Master key in --encryption-key-file to wrap new data keys with (default: the last key in the file).").String()
+	encryptionKMSKeyID  = kingpin.Flag("encryption-kms-key-id", "Encrypt blobs and manifests client-side with data keys wrapped by this AWS KMS key.").String()
+	encryptionKMSRegion = kingpin.Flag("encryption-kms-region", "AWS region of --encryption-kms-key-id.").Envar("AWS_REGION").String()
+)
+
+// openKeyring returns nil when client-side encryption is not configured.
+func openKeyring() *envelope.Keyring {
+	lgr := zap.S()
+	var providers []envelope.KeyProvider
+	if *encryptionKMSKeyID != "" {
+		awsSession, err := session.NewSession(aws.NewConfig().WithRegion(*encryptionKMSRegion))
+		if err != nil {
+			lgr.Fatalw("aws_new_session_error", "err", err)
+		}
+		provider, err := envelope.NewKMS(context.Background(), kms.New(awsSession), *encryptionKMSKeyID)
+		if err != nil {
+			lgr.Fatalw("encryption_kms_key_error", "key_id", *encryptionKMSKeyID, "err", err)
+		}
+		providers = append(providers, provider)
+	}
+	if *encryptionKeyFile != "" {
+		provider, err := envelope.LoadKeyFile(*encryptionKeyFile, *encryptionKeyID)
+		if err != nil {
+			lgr.Fatalw("encryption_key_file_error", "err", err)
+		}
+		providers = append(providers, provider)
+	}
+	if len(providers) == 0 {
+		return nil
+	}
+	keyring := envelope.NewKeyring(providers[0], providers[1:]...)
+	lgr.Infow("client_side_encryption_enabled", "provider", providers[0].Name(), "key_id", providers[0].KeyID())
+	return keyring
+}
+
+// Each blob's wrapped data key is stored beside the blob, named by the data key's id rather than
+// overwriting a single key object, so that two hosts uploading the same blob at once cannot leave a
+// blob sealed with one data key next to the wrapped form of another.
+func putDataKey(ctx context.Context, store objectStore, keyStore *KeyStore, digests digest.ForRestore, wrapped envelope.WrappedKey) error {
+	data, err := wrapped.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return store.putObject(ctx, keyStore.absoluteKeyForDataKey(digests, wrapped.DataKeyID), data, "application/octet-stream", "")
+}
+
+func getDataKey(ctx context.Context, store objectStore, keyStore *KeyStore, digests digest.ForRestore, id envelope.DataKeyID) (envelope.WrappedKey, error) {
+	var wrapped envelope.WrappedKey
+	data, err := store.getObject(ctx, keyStore.absoluteKeyForDataKey(digests, id))
+	if err != nil {
+		return wrapped, err
+	}
+	err = wrapped.UnmarshalBinary(data)
+	return wrapped, err
+}
+
+func errNoKeyring(key string) error {
+	return fmt.Errorf("%s is encrypted but client-side encryption is not configured", key)
+}
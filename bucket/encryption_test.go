@@ -0,0 +1,245 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bucket
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/retailnext/cassandrabackup/digest"
+	"github.com/retailnext/cassandrabackup/envelope"
+	"github.com/retailnext/cassandrabackup/manifests"
+	"github.com/retailnext/cassandrabackup/paranoid"
+)
+
+func testKeyFile(t *testing.T, ids ...string) string {
+	name := filepath.Join(t.TempDir(), "keys")
+	var contents []byte
+	for _, id := range ids {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			panic(err)
+		}
+		contents = fmt.Appendf(contents, "%s %s\n", id, base64.StdEncoding.EncodeToString(key))
+	}
+	if err := os.WriteFile(name, contents, 0o600); err != nil {
+		panic(err)
+	}
+	return name
+}
+
+func testKeyring(t *testing.T, keyFile, activeID string) *envelope.Keyring {
+	kf, err := envelope.LoadKeyFile(keyFile, activeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return envelope.NewKeyring(kf)
+}
+
+func testBlobFile(t *testing.T, size int) (paranoid.File, digest.ForUpload) {
+	name := filepath.Join(t.TempDir(), "blob")
+	data := make([]byte, size)
+	if _, err := rand.Read(data); err != nil {
+		panic(err)
+	}
+	if err := os.WriteFile(name, data, 0o644); err != nil {
+		panic(err)
+	}
+	file, err := paranoid.NewFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digests, err := digest.GetUncached(context.Background(), file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return file, digests
+}
+
+func TestFileClientEncrypted(t *testing.T) {
+	c := &fileClient{
+		keyStore: newKeyStore(t.TempDir(), ""),
+		keys:     testKeyring(t, testKeyFile(t, "k1"), ""),
+	}
+	t.Run("Blobs", func(t *testing.T) {
+		testClientBlobs(t, c)
+	})
+	t.Run("Manifests", func(t *testing.T) {
+		testClientManifests(t, c)
+	})
+
+	ctx := context.Background()
+	blobs, err := c.listObjects(ctx, "files/")
+	if err != nil || len(blobs) == 0 {
+		t.Fatalf("expected blobs got %v %v", blobs, err)
+	}
+	for _, blob := range blobs {
+		data, err := c.getObject(ctx, blob.Key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !envelope.IsSealed(data) {
+			t.Fatalf("blob %s stored unencrypted", blob.Key)
+		}
+	}
+	documents, err := c.listObjects(ctx, c.keyStore.absoluteKeyPrefixForClusters())
+	if err != nil || len(documents) == 0 {
+		t.Fatalf("expected manifests got %v %v", documents, err)
+	}
+	for _, document := range documents {
+		data, err := c.getObject(ctx, document.Key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !envelope.IsSealedDocument(data) {
+			t.Fatalf("manifest %s stored unencrypted", document.Key)
+		}
+	}
+}
+
+func TestFileClientEnablingEncryption(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	plain := &fileClient{keyStore: newKeyStore(root, "")}
+	encrypted := &fileClient{
+		keyStore: newKeyStore(root, ""),
+		keys:     testKeyring(t, testKeyFile(t, "k1"), ""),
+	}
+	file, digests := testBlobFile(t, 3*envelope.ChunkSize)
+	if err := plain.PutBlob(ctx, file, digests); err != nil {
+		t.Fatal(err)
+	}
+
+	// Blobs from before encryption was enabled stay readable.
+	dst, err := os.Create(filepath.Join(t.TempDir(), "dst"))
+	if err != nil {
+		panic(err)
+	}
+	defer func() {
+		_ = dst.Close()
+	}()
+	if err := encrypted.DownloadBlob(ctx, digests.ForRestore(), dst); err != nil {
+		t.Fatal(err)
+	}
+
+	// ...and are replaced by sealed ones the next time they are backed up.
+	if err := encrypted.PutBlob(ctx, file, digests); err != nil {
+		t.Fatalf("expected upload got %v", err)
+	}
+	if err := encrypted.DownloadBlob(ctx, digests.ForRestore(), dst); err != nil {
+		t.Fatal(err)
+	}
+	if err := plain.DownloadBlob(ctx, digests.ForRestore(), dst); err == nil {
+		t.Fatal("expected error reading sealed blob without a key")
+	}
+}
+
+func TestRewrapKeys(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	keyFile := testKeyFile(t, "old", "new")
+	oldClient := &fileClient{
+		keyStore: newKeyStore(root, ""),
+		keys:     testKeyring(t, keyFile, "old"),
+	}
+	newClient := &fileClient{
+		keyStore: newKeyStore(root, ""),
+		keys:     testKeyring(t, keyFile, "new"),
+	}
+
+	file, digests := testBlobFile(t, 1000)
+	if err := oldClient.PutBlob(ctx, file, digests); err != nil {
+		t.Fatal(err)
+	}
+	identity := manifests.NodeIdentity{Cluster: "cluster", Hostname: "host"}
+	manifest := manifests.Manifest{
+		Time:         100,
+		ManifestType: manifests.ManifestTypeSnapshot,
+		DataFiles:    map[string]digest.ForRestore{},
+	}
+	if err := oldClient.PutManifest(ctx, identity, manifest); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := RewrapResult{DataKeys: 1, DataKeysRewrapped: 1, Manifests: 1, ManifestsRewrapped: 1}
+	result, err := RewrapKeys(ctx, newClient, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal(result, expected); diff != nil {
+		t.Fatal(diff)
+	}
+	if result, err = RewrapKeys(ctx, newClient, false); err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal(result, expected); diff != nil {
+		t.Fatal(diff)
+	}
+	if result, err = RewrapKeys(ctx, newClient, true); err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal(result, RewrapResult{DataKeys: 1, Manifests: 1}); diff != nil {
+		t.Fatal(diff)
+	}
+
+	// The old master key is no longer needed.
+	onlyNew := &fileClient{
+		keyStore: newKeyStore(root, ""),
+		keys:     testKeyring(t, keyFileWithout(t, keyFile, "old"), ""),
+	}
+	dst, err := os.Create(filepath.Join(t.TempDir(), "dst"))
+	if err != nil {
+		panic(err)
+	}
+	defer func() {
+		_ = dst.Close()
+	}()
+	if err := onlyNew.DownloadBlob(ctx, digests.ForRestore(), dst); err != nil {
+		t.Fatal(err)
+	}
+	got, err := onlyNew.GetManifests(ctx, identity, manifests.ManifestKeys{manifest.Key()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal(got, []manifests.Manifest{manifest}); diff != nil {
+		t.Fatal(diff)
+	}
+}
+
+// keyFileWithout copies keyFile leaving out the key with the given id.
+func keyFileWithout(t *testing.T, keyFile, id string) string {
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		panic(err)
+	}
+	var kept [][]byte
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if !bytes.HasPrefix(line, []byte(id+" ")) {
+			kept = append(kept, line)
+		}
+	}
+	name := filepath.Join(t.TempDir(), "keys")
+	if err := os.WriteFile(name, bytes.Join(kept, []byte("\n")), 0o600); err != nil {
+		panic(err)
+	}
+	return name
+}
@@ -30,7 +30,9 @@ type ExistsCache struct {
 	cache *cache.Cache
 }
 
-func (e *ExistsCache) Get(restore digest.ForRestore) bool {
+// Get reports whether the blob is known to exist and to be locked until after notBefore, so that it
+// can't have been deleted since it was seen and won't be before a manifest referencing it is.
+func (e *ExistsCache) Get(restore digest.ForRestore, notBefore time.Time) bool {
 	var exists bool
 	key, err := restore.MarshalBinary()
 	if err != nil {
@@ -41,7 +43,7 @@ func (e *ExistsCache) Get(restore digest.ForRestore) bool {
 		if err := lockedUntil.UnmarshalBinary(value); err != nil {
 			return err
 		}
-		if time.Now().Add(objectLockSafetyMargin).Unix() < int64(lockedUntil) {
+		if notBefore.Unix() < int64(lockedUntil) {
 			exists = true
 			return nil
 		} else {
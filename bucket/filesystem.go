@@ -0,0 +1,335 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bucket
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/retailnext/cassandrabackup/digest"
+	"github.com/retailnext/cassandrabackup/digest/parts"
+	"github.com/retailnext/cassandrabackup/envelope"
+	"github.com/retailnext/cassandrabackup/manifests"
+	"github.com/retailnext/cassandrabackup/paranoid"
+	"github.com/retailnext/cassandrabackup/throttle"
+	"github.com/retailnext/cassandrabackup/unixtime"
+	"github.com/retailnext/writefile"
+	"go.uber.org/zap"
+)
+
+var fileRoot = kingpin.Flag("file-root", "Root directory for the file storage backend.").String()
+
+// fileClient stores blobs and manifests in a local (or NFS mounted) directory using the same
+// key layout as the S3 backend, so a directory tree can be synced to or from a bucket as-is.
+type fileClient struct {
+	keyStore KeyStore
+	keys     *envelope.Keyring
+}
+
+func newFileClient() *fileClient {
+	return newFileClientAt(*fileRoot)
+}
+
+func newFileClientAt(fileRoot string) *fileClient {
+	if fileRoot == "" {
+		zap.S().Fatalw("file_root_required")
+	}
+	root, err := filepath.Abs(fileRoot)
+	if err != nil {
+		zap.S().Fatalw("file_root_invalid", "root", fileRoot, "err", err)
+	}
+	return &fileClient{
+		keyStore: newKeyStore(root, ""),
+		keys:     openKeyring(),
+	}
+}
+
+func (c *fileClient) KeyStore() *KeyStore {
+	return &c.keyStore
+}
+
+func (c *fileClient) path(key string) string {
+	return filepath.Join(c.keyStore.bucket, filepath.FromSlash(key))
+}
+
+func (c *fileClient) writeFile(key string, op writefile.WriteOperation) error {
+	target := writefile.Config{
+		Directory:     c.keyStore.bucket,
+		DirectoryMode: 0o755,
+		FileMode:      0o644,
+	}
+	return target.WriteFile(filepath.FromSlash(key), func(file *os.File) error {
+		if err := op(file); err != nil {
+			return err
+		}
+		return file.Sync()
+	})
+}
+
+func (c *fileClient) keyring() *envelope.Keyring {
+	return c.keys
+}
+
+func (c *fileClient) PutBlob(ctx context.Context, file paranoid.File, digests digest.ForUpload) error {
+	return putBlob(ctx, c, file, digests)
+}
+
+// uploadBlob checks the part digests while copying, giving the same protection against the
+// source changing underneath us that the cloud backends get from their checksum headers.
+func (c *fileClient) uploadBlob(ctx context.Context, key string, body io.ReaderAt, partDigests *parts.PartDigests) error {
+	return c.writeFile(key, func(dst *os.File) error {
+		var maker parts.PartDigestsMaker
+		maker.Reset(uint64(partDigests.PartSize()))
+		src := io.NewSectionReader(throttle.ReaderAt(ctx, body, throttle.Upload), 0, partDigests.TotalLength())
+		if _, err := io.Copy(io.MultiWriter(dst, &maker), contextReader{ctx: ctx, r: src}); err != nil {
+			return err
+		}
+		expected, err := partDigests.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		written := maker.Finish()
+		actual, err := written.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(expected, actual) {
+			return fmt.Errorf("digest mismatch writing %s", key)
+		}
+		return nil
+	})
+}
+
+func (c *fileClient) blobExists(ctx context.Context, digests digest.ForRestore, expectedLength int64) (bool, error) {
+	key := c.keyStore.AbsoluteKeyForBlob(digests)
+	info, err := os.Stat(c.path(key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if actualLength := info.Size(); actualLength != expectedLength {
+		zap.S().Infow("blob_exists_saw_wrong_length", "key", key, "expected", expectedLength, "actual", actualLength)
+		return false, nil
+	}
+	return true, nil
+}
+
+func (c *fileClient) DownloadBlob(ctx context.Context, digests digest.ForRestore, file *os.File) error {
+	return downloadBlob(ctx, c, digests, file)
+}
+
+func (c *fileClient) ResumeDownloadBlob(ctx context.Context, digests digest.ForRestore, file *os.File) error {
+	return resumeDownloadBlob(ctx, c, digests, file)
+}
+
+func (c *fileClient) StatBlob(ctx context.Context, digests digest.ForRestore) (int64, error) {
+	return statBlob(ctx, c, digests)
+}
+
+func (c *fileClient) downloadBlobFrom(ctx context.Context, key string, w io.Writer, offset int64) error {
+	src, err := os.Open(c.path(key))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := src.Close(); closeErr != nil {
+			panic(closeErr)
+		}
+	}()
+	if _, err := src.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	_, err = io.Copy(w, contextReader{ctx: ctx, r: src})
+	return err
+}
+
+func (c *fileClient) downloadBlob(ctx context.Context, key string, file *os.File) error {
+	src, err := os.Open(c.path(key))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := src.Close(); closeErr != nil {
+			panic(closeErr)
+		}
+	}()
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		zap.S().Panicw("get_blob_seek_error", "err", err)
+	}
+	if err := file.Truncate(0); err != nil {
+		zap.S().Panicw("get_blob_truncate_error", "err", err)
+	}
+	if _, err := io.Copy(throttle.Writer(ctx, file, throttle.Download), contextReader{ctx: ctx, r: src}); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return err
+	}
+	return nil
+}
+
+func (c *fileClient) PutManifest(ctx context.Context, identity manifests.NodeIdentity, manifest manifests.Manifest) error {
+	return putManifest(ctx, c, identity, manifest)
+}
+
+func (c *fileClient) GetManifests(ctx context.Context, identity manifests.NodeIdentity, keys manifests.ManifestKeys) ([]manifests.Manifest, error) {
+	return getManifests(ctx, c, identity, keys)
+}
+
+func (c *fileClient) putObject(ctx context.Context, absoluteKey string, data []byte, contentType, contentEncoding string) error {
+	return c.writeFile(absoluteKey, func(file *os.File) error {
+		_, err := file.Write(data)
+		return err
+	})
+}
+
+func (c *fileClient) getObject(ctx context.Context, absoluteKey string) ([]byte, error) {
+	return os.ReadFile(c.path(absoluteKey))
+}
+
+// listObjects walks the tree under prefix, which like an object store prefix need not end at a directory boundary.
+func (c *fileClient) listObjects(ctx context.Context, prefix string) ([]objectInfo, error) {
+	dir := prefix[:strings.LastIndex(prefix, "/")+1]
+	var objects []objectInfo
+	err := filepath.WalkDir(c.path(dir), func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if strings.HasPrefix(entry.Name(), ".") || entry.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(c.keyStore.bucket, name)
+		if err != nil {
+			return err
+		}
+		if key := filepath.ToSlash(rel); strings.HasPrefix(key, prefix) {
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			objects = append(objects, objectInfo{Key: key, Size: info.Size(), LastModified: info.ModTime()})
+		}
+		return nil
+	})
+	return objects, err
+}
+
+func (c *fileClient) statObject(ctx context.Context, absoluteKey string) (objectInfo, error) {
+	info, err := os.Stat(c.path(absoluteKey))
+	if err != nil {
+		return objectInfo{}, err
+	}
+	return objectInfo{Key: absoluteKey, Size: info.Size(), LastModified: info.ModTime()}, nil
+}
+
+func (c *fileClient) deleteObject(ctx context.Context, absoluteKey string) error {
+	return os.Remove(c.path(absoluteKey))
+}
+
+func (c *fileClient) ListManifests(ctx context.Context, identity manifests.NodeIdentity, startAfter, notAfter unixtime.Seconds) (manifests.ManifestKeys, error) {
+	prefixKey := c.keyStore.absoluteKeyPrefixForManifests(identity)
+	names, err := c.readDir(prefixKey, false)
+	if err != nil {
+		return nil, err
+	}
+	absoluteKeys := make([]string, 0, len(names))
+	for _, name := range names {
+		absoluteKeys = append(absoluteKeys, prefixKey+name)
+	}
+	return c.keyStore.manifestKeysInRange(identity, startAfter, notAfter, absoluteKeys), nil
+}
+
+func (c *fileClient) ListHostNames(ctx context.Context, cluster string) ([]manifests.NodeIdentity, error) {
+	prefix := c.keyStore.absoluteKeyPrefixForClusterHosts(cluster)
+	names, err := c.readDir(prefix, true)
+	if err != nil {
+		return nil, err
+	}
+	prefixes := make([]string, 0, len(names))
+	for _, name := range names {
+		prefixes = append(prefixes, prefix+name+"/")
+	}
+	result, bonus := c.keyStore.decodeClusterHostPrefixes(prefixes)
+	if len(bonus) > 0 {
+		zap.S().Warnw("unexpected_objects_in_bucket", "keys", bonus)
+	}
+	return result, nil
+}
+
+func (c *fileClient) ListClusters(ctx context.Context) ([]string, error) {
+	prefix := c.keyStore.absoluteKeyPrefixForClusters()
+	names, err := c.readDir(prefix, true)
+	if err != nil {
+		return nil, err
+	}
+	var result []string
+	for _, name := range names {
+		cluster, err := c.keyStore.decodeCluster(prefix + name)
+		if err != nil {
+			zap.S().Errorw("decode_cluster_error", "err", err)
+		} else {
+			result = append(result, cluster)
+		}
+	}
+	return result, nil
+}
+
+// readDir returns the sorted names of the directories (or files) under a key prefix.
+// Temporary files left behind by interrupted writes are skipped, and a missing prefix is treated as empty.
+func (c *fileClient) readDir(prefix string, dirs bool) ([]string, error) {
+	entries, err := os.ReadDir(c.path(prefix))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") || entry.IsDir() != dirs {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	return names, nil
+}
+
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
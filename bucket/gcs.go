@@ -0,0 +1,379 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bucket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/retailnext/cassandrabackup/digest"
+	"github.com/retailnext/cassandrabackup/digest/parts"
+	"github.com/retailnext/cassandrabackup/envelope"
+	"github.com/retailnext/cassandrabackup/manifests"
+	"github.com/retailnext/cassandrabackup/paranoid"
+	"github.com/retailnext/cassandrabackup/throttle"
+	"github.com/retailnext/cassandrabackup/unixtime"
+	"go.uber.org/zap"
+	"google.golang.org/api/iterator"
+)
+
+// The GCS client honors STORAGE_EMULATOR_HOST, which is how this backend is pointed at a local emulator.
+var (
+	gcsBucketName       = kingpin.Flag("gcs-bucket", "GCS bucket name.").String()
+	gcsKeyPrefix        = kingpin.Flag("gcs-key-prefix", "Set the prefix for files in the GCS bucket").Default("/").String()
+	gcsBlobStorageClass = kingpin.Flag("gcs-storage-class", "Set the storage class for files in GCS (default: bucket default)").String()
+	gcsRequireKMS       = kingpin.Flag("gcs-require-kms", "Require the GCS bucket to have a default customer-managed KMS key.").Bool()
+)
+
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+type gcsClient struct {
+	bucket       *storage.BucketHandle
+	storageClass string
+
+	keyStore KeyStore
+	keys     *envelope.Keyring
+}
+
+func newGCSClient() *gcsClient {
+	lgr := zap.S()
+	if *gcsBucketName == "" {
+		lgr.Fatalw("gcs_bucket_required")
+	}
+	gcs, err := storage.NewClient(context.Background())
+	if err != nil {
+		lgr.Fatalw("gcs_new_client_error", "err", err)
+	}
+	c := &gcsClient{
+		bucket:       gcs.Bucket(*gcsBucketName),
+		storageClass: *gcsBlobStorageClass,
+		keyStore:     newKeyStore(*gcsBucketName, strings.Trim(*gcsKeyPrefix, "/")),
+		keys:         openKeyring(),
+	}
+	c.validateEncryptionConfiguration()
+	return c
+}
+
+// validateEncryptionConfiguration is the GCS counterpart of the S3 default SSE check.
+// GCS always encrypts at rest, so this only confirms the bucket is reachable and, when
+// requested, that objects will be encrypted with a customer-managed key by default.
+func (c *gcsClient) validateEncryptionConfiguration() {
+	attrs, err := c.bucket.Attrs(context.Background())
+	if err != nil {
+		zap.S().Fatalw("failed_to_validate_bucket_encryption", "err", err)
+	}
+	if !*gcsRequireKMS {
+		return
+	}
+	if attrs.Encryption == nil || attrs.Encryption.DefaultKMSKeyName == "" {
+		zap.S().Fatalw("bucket_not_configured_with_kms_key", "bucket", c.keyStore.bucket)
+	}
+}
+
+func (c *gcsClient) KeyStore() *KeyStore {
+	return &c.keyStore
+}
+
+func (c *gcsClient) keyring() *envelope.Keyring {
+	return c.keys
+}
+
+func (c *gcsClient) PutBlob(ctx context.Context, file paranoid.File, digests digest.ForUpload) error {
+	return putBlob(ctx, c, file, digests)
+}
+
+func (c *gcsClient) blobExists(ctx context.Context, digests digest.ForRestore, expectedLength int64) (bool, error) {
+	key := c.keyStore.AbsoluteKeyForBlob(digests)
+	attrs, err := c.bucket.Object(key).Attrs(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return false, nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return false, ctxErr
+		}
+		return false, err
+	}
+	if attrs.Size != expectedLength {
+		zap.S().Infow("blob_exists_saw_wrong_length", "key", key, "expected", expectedLength, "actual", attrs.Size)
+		return false, nil
+	}
+	return true, nil
+}
+
+// uploadBlob sends the body with its CRC32C up front so GCS rejects the upload rather than storing corrupt data.
+func (c *gcsClient) uploadBlob(ctx context.Context, key string, body io.ReaderAt, partDigests *parts.PartDigests) error {
+	length := partDigests.TotalLength()
+	h := crc32.New(crc32cTable)
+	if _, err := io.Copy(h, contextReader{ctx: ctx, r: io.NewSectionReader(body, 0, length)}); err != nil {
+		return err
+	}
+
+	writeCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	w := c.bucket.Object(key).NewWriter(writeCtx)
+	w.ContentType = "application/octet-stream"
+	w.StorageClass = c.storageClass
+	w.CRC32C = h.Sum32()
+	w.SendCRC32C = true
+	if _, err := io.Copy(w, io.NewSectionReader(throttle.ReaderAt(ctx, body, throttle.Upload), 0, length)); err != nil {
+		cancel()
+		_ = w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+func (c *gcsClient) DownloadBlob(ctx context.Context, digests digest.ForRestore, file *os.File) error {
+	return downloadBlob(ctx, c, digests, file)
+}
+
+func (c *gcsClient) ResumeDownloadBlob(ctx context.Context, digests digest.ForRestore, file *os.File) error {
+	return resumeDownloadBlob(ctx, c, digests, file)
+}
+
+func (c *gcsClient) StatBlob(ctx context.Context, digests digest.ForRestore) (int64, error) {
+	return statBlob(ctx, c, digests)
+}
+
+func (c *gcsClient) downloadBlob(ctx context.Context, key string, file *os.File) error {
+	attempts := 0
+	for {
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			zap.S().Panicw("get_blob_seek_error", "err", err)
+		}
+		if err := file.Truncate(0); err != nil {
+			zap.S().Panicw("get_blob_truncate_error", "err", err)
+		}
+		err := c.downloadBlobFrom(ctx, key, throttle.Writer(ctx, file, throttle.Download), 0)
+		if err != nil {
+			attempts++
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			if IsNoSuchKey(err) || attempts > getBlobRetriesLimit {
+				return err
+			}
+			zap.S().Errorw("get_blob_gcs_error", "err", err, "attempts", attempts)
+		} else {
+			return nil
+		}
+	}
+}
+
+func (c *gcsClient) downloadBlobFrom(ctx context.Context, key string, w io.Writer, offset int64) error {
+	r, err := c.bucket.Object(key).NewRangeReader(ctx, offset, -1)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+	_, err = io.Copy(w, r)
+	return err
+}
+
+func (c *gcsClient) PutManifest(ctx context.Context, identity manifests.NodeIdentity, manifest manifests.Manifest) error {
+	return putManifest(ctx, c, identity, manifest)
+}
+
+func (c *gcsClient) GetManifests(ctx context.Context, identity manifests.NodeIdentity, keys manifests.ManifestKeys) ([]manifests.Manifest, error) {
+	return getManifests(ctx, c, identity, keys)
+}
+
+func (c *gcsClient) putObject(ctx context.Context, absoluteKey string, data []byte, contentType, contentEncoding string) error {
+	attempts := 0
+	for {
+		w := c.bucket.Object(absoluteKey).NewWriter(ctx)
+		w.ContentType = contentType
+		w.ContentEncoding = contentEncoding
+		w.CRC32C = crc32.Checksum(data, crc32cTable)
+		w.SendCRC32C = true
+		_, err := w.Write(data)
+		if closeErr := w.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			attempts++
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			if attempts > putJsonRetriesLimit {
+				return err
+			}
+			zap.S().Warnw("gcs_put_object_error", "err", err, "attempts", attempts)
+			time.Sleep(time.Duration(attempts) * retrySleepPerAttempt)
+		} else {
+			return nil
+		}
+	}
+}
+
+// getObject reads the object as stored; gzip encoded objects are not transparently decompressed.
+func (c *gcsClient) getObject(ctx context.Context, absoluteKey string) ([]byte, error) {
+	attempts := 0
+	for {
+		r, err := c.bucket.Object(absoluteKey).ReadCompressed(true).NewReader(ctx)
+		var data []byte
+		if err == nil {
+			data, err = io.ReadAll(r)
+			_ = r.Close()
+			if err == nil {
+				return data, nil
+			}
+		}
+		if IsNoSuchKey(err) {
+			return nil, err
+		}
+		attempts++
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		if attempts > getJsonRetriesLimit {
+			return nil, err
+		}
+		zap.S().Warnw("gcs_get_object_error", "err", err, "attempts", attempts)
+		time.Sleep(time.Duration(attempts) * retrySleepPerAttempt)
+	}
+}
+
+func (c *gcsClient) listObjects(ctx context.Context, prefix string) ([]objectInfo, error) {
+	query := &storage.Query{Prefix: prefix}
+	if err := query.SetAttrSelection([]string{"Name", "Size", "Updated"}); err != nil {
+		panic(err)
+	}
+	var objects []objectInfo
+	it := c.bucket.Objects(ctx, query)
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			return objects, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("list %q: %w", prefix, err)
+		}
+		objects = append(objects, objectInfo{Key: attrs.Name, Size: attrs.Size, LastModified: attrs.Updated})
+	}
+}
+
+// statObject treats holds like an S3 legal hold, and the later of the object's own retention and
+// the bucket retention policy like an S3 retain-until date.
+func (c *gcsClient) statObject(ctx context.Context, absoluteKey string) (objectInfo, error) {
+	attrs, err := c.bucket.Object(absoluteKey).Attrs(ctx)
+	if err != nil {
+		return objectInfo{}, err
+	}
+	info := objectInfo{
+		Key:          absoluteKey,
+		Size:         attrs.Size,
+		LastModified: attrs.Updated,
+		RetainUntil:  attrs.RetentionExpirationTime,
+		LegalHold:    attrs.TemporaryHold || attrs.EventBasedHold,
+	}
+	if attrs.Retention != nil && attrs.Retention.RetainUntil.After(info.RetainUntil) {
+		info.RetainUntil = attrs.Retention.RetainUntil
+	}
+	return info, nil
+}
+
+func (c *gcsClient) deleteObject(ctx context.Context, absoluteKey string) error {
+	return c.bucket.Object(absoluteKey).Delete(ctx)
+}
+
+func (c *gcsClient) ListManifests(ctx context.Context, identity manifests.NodeIdentity, startAfter, notAfter unixtime.Seconds) (manifests.ManifestKeys, error) {
+	prefixKey := c.keyStore.absoluteKeyPrefixForManifests(identity)
+	query := &storage.Query{
+		Prefix:      prefixKey,
+		Delimiter:   "/",
+		StartOffset: c.keyStore.absoluteKeyForManifestTimeRange(identity, startAfter),
+	}
+	names, _, err := c.list(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return c.keyStore.manifestKeysInRange(identity, startAfter, notAfter, names), nil
+}
+
+func (c *gcsClient) ListHostNames(ctx context.Context, cluster string) ([]manifests.NodeIdentity, error) {
+	lgr := zap.S()
+	query := &storage.Query{
+		Prefix:    c.keyStore.absoluteKeyPrefixForClusterHosts(cluster),
+		Delimiter: "/",
+	}
+	names, prefixes, err := c.list(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	result, bonus := c.keyStore.decodeClusterHostPrefixes(prefixes)
+	if len(bonus) > 0 {
+		lgr.Warnw("unexpected_objects_in_bucket", "keys", bonus)
+	}
+	if len(names) > 0 {
+		lgr.Warnw("unexpected_objects_in_bucket", "keys", names)
+	}
+	return result, nil
+}
+
+func (c *gcsClient) ListClusters(ctx context.Context) ([]string, error) {
+	query := &storage.Query{
+		Prefix:    c.keyStore.absoluteKeyPrefixForClusters(),
+		Delimiter: "/",
+	}
+	_, prefixes, err := c.list(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	var result []string
+	for _, prefix := range prefixes {
+		cluster, err := c.keyStore.decodeCluster(prefix)
+		if err != nil {
+			zap.S().Errorw("decode_cluster_error", "err", err)
+		} else {
+			result = append(result, cluster)
+		}
+	}
+	return result, nil
+}
+
+// list returns the object names and common prefixes matching query, in lexical order.
+func (c *gcsClient) list(ctx context.Context, query *storage.Query) ([]string, []string, error) {
+	var names, prefixes []string
+	if err := query.SetAttrSelection([]string{"Name"}); err != nil {
+		panic(err)
+	}
+	it := c.bucket.Objects(ctx, query)
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			return names, prefixes, nil
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("list %q: %w", query.Prefix, err)
+		}
+		if attrs.Prefix != "" {
+			prefixes = append(prefixes, attrs.Prefix)
+		} else {
+			names = append(names, attrs.Name)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bucket
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"cloud.google.com/go/storage"
+)
+
+// Run against fake-gcs-server with e.g. STORAGE_EMULATOR_HOST=localhost:4443.
+func TestGCSClient(t *testing.T) {
+	if os.Getenv("STORAGE_EMULATOR_HOST") == "" {
+		t.Skip("STORAGE_EMULATOR_HOST not set")
+	}
+	ctx := context.Background()
+	gcs, err := storage.NewClient(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const bucketName = "cassandrabackup-test"
+	bkt := gcs.Bucket(bucketName)
+	if _, err := bkt.Attrs(ctx); errors.Is(err, storage.ErrBucketNotExist) {
+		if err := bkt.Create(ctx, "test", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := &gcsClient{
+		bucket:   bkt,
+		keyStore: newKeyStore(bucketName, "test"),
+	}
+	c.validateEncryptionConfiguration()
+	t.Run("Blobs", func(t *testing.T) {
+		testClientBlobs(t, c)
+	})
+	t.Run("Manifests", func(t *testing.T) {
+		testClientManifests(t, c)
+	})
+}
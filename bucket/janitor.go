@@ -0,0 +1,91 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bucket
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/s3"
+	"go.uber.org/zap"
+)
+
+type AbortUploadsResult struct {
+	Uploads int
+	Stale   int
+	Aborted int
+	Failed  int
+}
+
+// AbortStaleUploads aborts multipart uploads of blobs that were started before olderThan ago. Uploads
+// that fail are kept so that a later backup can resume them, which leaves the parts of blobs that are
+// never backed up again taking up space until this cleans them up.
+func AbortStaleUploads(ctx context.Context, c Client, olderThan time.Duration, dryRun bool) (AbortUploadsResult, error) {
+	var result AbortUploadsResult
+	client, ok := c.(*awsClient)
+	if !ok {
+		return result, fmt.Errorf("only the s3 storage backend leaves multipart uploads behind")
+	}
+	cutoff := time.Now().Add(-olderThan)
+	prefix := client.keyStore.keyWithPrefix("files/")
+
+	var stale []*s3.MultipartUpload
+	input := &s3.ListMultipartUploadsInput{
+		Bucket: &client.keyStore.bucket,
+		Prefix: &prefix,
+	}
+	err := client.s3Svc.ListMultipartUploadsPagesWithContext(ctx, input, func(output *s3.ListMultipartUploadsOutput, lastPage bool) bool {
+		for _, upload := range output.Uploads {
+			result.Uploads++
+			if upload.Initiated != nil && upload.Initiated.Before(cutoff) {
+				stale = append(stale, upload)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return result, err
+	}
+	result.Stale = len(stale)
+
+	lgr := zap.S()
+	for _, upload := range stale {
+		uploadLgr := lgr.With("key", *upload.Key, "upload_id", *upload.UploadId, "initiated", *upload.Initiated)
+		if dryRun {
+			uploadLgr.Infow("would_abort_multipart_upload")
+			continue
+		}
+		_, err := client.s3Svc.AbortMultipartUploadWithContext(ctx, &s3.AbortMultipartUploadInput{
+			Bucket:   &client.keyStore.bucket,
+			Key:      upload.Key,
+			UploadId: upload.UploadId,
+		})
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return result, ctxErr
+			}
+			uploadLgr.Errorw("abort_multipart_upload_error", "err", err)
+			result.Failed++
+			continue
+		}
+		uploadLgr.Infow("abort_multipart_upload_ok")
+		result.Aborted++
+	}
+	if result.Failed > 0 {
+		return result, fmt.Errorf("%d stale uploads failed to abort", result.Failed)
+	}
+	return result, nil
+}
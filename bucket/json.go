@@ -15,18 +15,19 @@
 package bucket
 
 import (
+	"bufio"
 	"bytes"
 	"compress/gzip"
 	"context"
-	"time"
+	"fmt"
+	"io"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/mailru/easyjson"
-	"go.uber.org/zap"
+	"github.com/retailnext/cassandrabackup/envelope"
 )
 
-func (c *awsClient) putDocument(ctx context.Context, absoluteKey string, v easyjson.Marshaler) error {
+// encodeDocument returns the gzipped JSON form that documents are stored in by every backend.
+func encodeDocument(v easyjson.Marshaler) []byte {
 	var encodeBuffer bytes.Buffer
 	gzipWriter := gzip.NewWriter(&encodeBuffer)
 	if _, err := easyjson.MarshalToWriter(v, gzipWriter); err != nil {
@@ -35,59 +36,52 @@ func (c *awsClient) putDocument(ctx context.Context, absoluteKey string, v easyj
 	if err := gzipWriter.Close(); err != nil {
 		panic(err)
 	}
+	return encodeBuffer.Bytes()
+}
 
-	putObjectInput := &s3.PutObjectInput{
-		Bucket:               &c.keyStore.bucket,
-		Key:                  &absoluteKey,
-		ContentType:          aws.String("application/json"),
-		ContentEncoding:      aws.String("gzip"),
-		ServerSideEncryption: c.serverSideEncryption,
-		Body:                 bytes.NewReader(encodeBuffer.Bytes()),
-	}
-	attempts := 0
-	for {
-		_, err := c.s3Svc.PutObjectWithContext(ctx, putObjectInput)
+// decodeDocument reads a document written by encodeDocument. Some stores transparently decompress
+// objects with a gzip Content-Encoding and some do not, so both forms are accepted.
+func decodeDocument(r io.Reader, v easyjson.Unmarshaler) error {
+	buffered := bufio.NewReader(r)
+	magic, err := buffered.Peek(2)
+	if err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gzipReader, err := gzip.NewReader(buffered)
 		if err != nil {
-			attempts++
-			if ctxErr := ctx.Err(); ctxErr != nil {
-				return ctxErr
-			}
-			if attempts > putJsonRetriesLimit {
-				return err
-			}
-			zap.S().Warnw("s3_put_object_error", "err", err, "attempts", attempts)
-			time.Sleep(time.Duration(attempts) * retrySleepPerAttempt)
-		} else {
-			return nil
+			return err
 		}
+		return easyjson.UnmarshalFromReader(gzipReader, v)
 	}
+	return easyjson.UnmarshalFromReader(buffered, v)
 }
 
-func (c *awsClient) getDocument(ctx context.Context, absoluteKey string, v easyjson.Unmarshaler) error {
-	getObjectInput := &s3.GetObjectInput{
-		Bucket: &c.keyStore.bucket,
-		Key:    &absoluteKey,
+// putDocument stores a document, sealed if client-side encryption is enabled. Sealed documents are
+// not marked as gzip encoded since the stores would otherwise try to decompress the ciphertext.
+func putDocument(ctx context.Context, store blobStore, absoluteKey string, v easyjson.Marshaler) error {
+	encoded := encodeDocument(v)
+	keyring := store.keyring()
+	if keyring == nil {
+		return store.putObject(ctx, absoluteKey, encoded, "application/json", "gzip")
 	}
-	attempts := 0
-	for {
-		getObjectOutput, err := c.s3Svc.GetObjectWithContext(ctx, getObjectInput)
-		if err != nil {
-			if IsNoSuchKey(err) {
-				return err
-			}
-			attempts++
-			if ctxErr := ctx.Err(); ctxErr != nil {
-				return ctxErr
-			}
-			if attempts > getJsonRetriesLimit {
-				return err
-			}
-			zap.S().Warnw("s3_get_object_error", "err", err, "attempts", attempts)
-			time.Sleep(time.Duration(attempts) * retrySleepPerAttempt)
-		} else {
-			err = easyjson.UnmarshalFromReader(getObjectOutput.Body, v)
-			_ = getObjectOutput.Body.Close()
-			return err
+	sealed, err := keyring.SealDocument(ctx, encoded)
+	if err != nil {
+		return err
+	}
+	return store.putObject(ctx, absoluteKey, sealed, "application/octet-stream", "")
+}
+
+func getDocument(ctx context.Context, store blobStore, absoluteKey string, v easyjson.Unmarshaler) error {
+	data, err := store.getObject(ctx, absoluteKey)
+	if err != nil {
+		return err
+	}
+	if envelope.IsSealedDocument(data) {
+		keyring := store.keyring()
+		if keyring == nil {
+			return errNoKeyring(absoluteKey)
+		}
+		if data, err = keyring.OpenDocument(ctx, data); err != nil {
+			return fmt.Errorf("open %s: %w", absoluteKey, err)
 		}
 	}
+	return decodeDocument(bytes.NewReader(data), v)
 }
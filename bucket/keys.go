@@ -18,12 +18,15 @@ import (
 	"bytes"
 	"encoding/base64"
 	"fmt"
+	"path"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/retailnext/cassandrabackup/digest"
+	"github.com/retailnext/cassandrabackup/envelope"
 	"github.com/retailnext/cassandrabackup/manifests"
 	"github.com/retailnext/cassandrabackup/unixtime"
+	"go.uber.org/zap"
 )
 
 type KeyStore struct {
@@ -47,22 +50,53 @@ func (c *KeyStore) keyWithPrefix(key string) string {
 }
 
 func (c *KeyStore) AbsoluteKeyForBlob(digests digest.ForRestore) string {
+	return c.keyWithPrefix(blobPath("files/blake2b/", digests))
+}
+
+func (c *KeyStore) absoluteKeyPrefixForDataKeys() string {
+	return c.keyWithPrefix("keys/")
+}
+
+func (c *KeyStore) absoluteKeyForDataKey(digests digest.ForRestore, id envelope.DataKeyID) string {
+	return c.keyWithPrefix(blobPath("keys/blake2b/", digests) + "/" + id.String())
+}
+
+func (c *KeyStore) absoluteKeyPrefixForBlobDataKeys(digests digest.ForRestore) string {
+	return c.keyWithPrefix(blobPath("keys/blake2b/", digests) + "/")
+}
+
+func blobPath(prefix string, digests digest.ForRestore) string {
 	var buffer bytes.Buffer
 	encoded := digests.URLSafe()
-	buffer.WriteString("files/blake2b/")
+	buffer.WriteString(prefix)
 	buffer.WriteString(encoded[0:1])
 	buffer.WriteString("/")
 	buffer.WriteString(encoded[1:2])
 	buffer.WriteString("/")
 	buffer.WriteString(encoded[2:])
-	return c.keyWithPrefix(buffer.String())
+	return buffer.String()
 }
 
 func (c *KeyStore) DecodeBlobKey(key string) (digest.ForRestore, error) {
+	return decodeBlobPath(strings.TrimPrefix(key, c.absoluteKeyPrefixForBlobs()))
+}
+
+func (c *KeyStore) absoluteKeyPrefixForBlobs() string {
+	return c.keyWithPrefix("files/blake2b/")
+}
+
+// decodeDataKeyKey returns the digest of the blob that a data key object belongs to.
+func (c *KeyStore) decodeDataKeyKey(key string) (digest.ForRestore, error) {
+	encoded := strings.TrimPrefix(key, c.keyWithPrefix("keys/blake2b/"))
+	return decodeBlobPath(encoded[:max(strings.LastIndexByte(encoded, '/'), 0)])
+}
+
+func decodeBlobPath(encoded string) (digest.ForRestore, error) {
 	var digests digest.ForRestore
 	var buffer bytes.Buffer
-	blobPrefix := c.keyWithPrefix("files/blake2b/")
-	encoded := strings.TrimPrefix(key, blobPrefix)
+	if len(encoded) < 5 || encoded[1] != '/' || encoded[3] != '/' {
+		return digests, fmt.Errorf("invalid blob path %q", encoded)
+	}
 	buffer.WriteString(encoded[0:1])
 	buffer.WriteString(encoded[2:3])
 	buffer.WriteString(encoded[4:])
@@ -74,6 +108,10 @@ func (c *KeyStore) DecodeBlobKey(key string) (digest.ForRestore, error) {
 	return digests, err
 }
 
+func (c *KeyStore) absoluteKeyForPendingDeletes() string {
+	return c.keyWithPrefix("prune/pending.json")
+}
+
 func (c *KeyStore) absoluteKeyPrefixForClusters() string {
 	return c.keyWithPrefix("manifests/")
 }
@@ -95,11 +133,18 @@ func (c *KeyStore) decodeCluster(key string) (string, error) {
 }
 
 func (c *KeyStore) decodeClusterHosts(prefixes []*s3.CommonPrefix) ([]manifests.NodeIdentity, []string) {
+	raw := make([]string, 0, len(prefixes))
+	for _, obj := range prefixes {
+		raw = append(raw, *obj.Prefix)
+	}
+	return c.decodeClusterHostPrefixes(raw)
+}
+
+func (c *KeyStore) decodeClusterHostPrefixes(prefixes []string) ([]manifests.NodeIdentity, []string) {
 	result := make([]manifests.NodeIdentity, 0, len(prefixes))
 	var bonus []string
 	skip := len(c.absoluteKeyPrefixForClusters())
-	for _, obj := range prefixes {
-		raw := *obj.Prefix
+	for _, raw := range prefixes {
 		trimmed := raw[skip:]
 		parts := strings.Split(trimmed, "/")
 		if len(parts) != 3 {
@@ -141,3 +186,31 @@ func (c *KeyStore) absoluteKeyForManifestTimeRange(identity manifests.NodeIdenti
 func (c *KeyStore) AbsoluteKeyForManifest(identity manifests.NodeIdentity, manifestKey manifests.ManifestKey) string {
 	return c.absoluteKeyPrefixForManifests(identity) + manifestKey.FileName()
 }
+
+// manifestKeysInRange parses sorted absolute manifest keys for identity, keeping the ones that
+// ListManifests would return from S3 for the same startAfter and notAfter.
+func (c *KeyStore) manifestKeysInRange(identity manifests.NodeIdentity, startAfter, notAfter unixtime.Seconds, absoluteKeys []string) manifests.ManifestKeys {
+	lgr := zap.S()
+	startAfterKey := c.absoluteKeyForManifestTimeRange(identity, startAfter)
+	notAfterKey := ""
+	if notAfter > 0 {
+		notAfterKey = c.absoluteKeyForManifestTimeRange(identity, notAfter)
+	}
+	var keys manifests.ManifestKeys
+	for _, key := range absoluteKeys {
+		if key <= startAfterKey {
+			continue
+		}
+		if notAfterKey != "" && key > notAfterKey {
+			break
+		}
+		name := path.Base(key)
+		var manifestKey manifests.ManifestKey
+		if err := manifestKey.PopulateFromFileName(name); err != nil {
+			lgr.Warnw("list_manifests_ignoring_bad_filename", "name", name, "err", err)
+		} else {
+			keys = append(keys, manifestKey)
+		}
+	}
+	return keys
+}
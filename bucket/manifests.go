@@ -80,14 +80,22 @@ func (c *awsClient) ListManifests(ctx context.Context, identity manifests.NodeId
 }
 
 func (c *awsClient) PutManifest(ctx context.Context, identity manifests.NodeIdentity, manifest manifests.Manifest) error {
+	return putManifest(ctx, c, identity, manifest)
+}
+
+func (c *awsClient) GetManifests(ctx context.Context, identity manifests.NodeIdentity, keys manifests.ManifestKeys) ([]manifests.Manifest, error) {
+	return getManifests(ctx, c, identity, keys)
+}
+
+func putManifest(ctx context.Context, store blobStore, identity manifests.NodeIdentity, manifest manifests.Manifest) error {
 	if manifest.ManifestType == manifests.ManifestTypeInvalid {
 		panic("invalid manifest type")
 	}
-	absoluteKey := c.keyStore.AbsoluteKeyForManifest(identity, manifest.Key())
-	return c.putDocument(ctx, absoluteKey, manifest)
+	absoluteKey := store.KeyStore().AbsoluteKeyForManifest(identity, manifest.Key())
+	return putDocument(ctx, store, absoluteKey, manifest)
 }
 
-func (c *awsClient) GetManifests(ctx context.Context, identity manifests.NodeIdentity, keys manifests.ManifestKeys) ([]manifests.Manifest, error) {
+func getManifests(ctx context.Context, store blobStore, identity manifests.NodeIdentity, keys manifests.ManifestKeys) ([]manifests.Manifest, error) {
 	var results []manifests.Manifest
 	doneCh := ctx.Done()
 	for _, manifestKey := range keys {
@@ -96,9 +104,9 @@ func (c *awsClient) GetManifests(ctx context.Context, identity manifests.NodeIde
 			return nil, nil
 		default:
 		}
-		absoluteKey := c.keyStore.AbsoluteKeyForManifest(identity, manifestKey)
+		absoluteKey := store.KeyStore().AbsoluteKeyForManifest(identity, manifestKey)
 		var m manifests.Manifest
-		if err := c.getDocument(ctx, absoluteKey, &m); err != nil {
+		if err := getDocument(ctx, store, absoluteKey, &m); err != nil {
 			zap.S().Errorw("get_manifest_error", "key", absoluteKey, "err", err)
 			return nil, err
 		}
@@ -0,0 +1,260 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bucket
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/retailnext/cassandrabackup/digest"
+	"github.com/retailnext/cassandrabackup/manifests"
+	"go.uber.org/zap"
+)
+
+// validateObjectLockConfiguration checks that Object Lock is enabled on the bucket when uploads are
+// to set a retention, which S3 would otherwise reject. Stores that don't implement the check are
+// trusted to honor it.
+func (c *awsClient) validateObjectLockConfiguration() {
+	if !c.objectLock.Enabled() {
+		return
+	}
+	lgr := zap.S()
+	output, err := c.s3Svc.GetObjectLockConfiguration(&s3.GetObjectLockConfigurationInput{
+		Bucket: &c.keyStore.bucket,
+	})
+	if err != nil {
+		if isNotImplemented(err) {
+			lgr.Warnw("bucket_object_lock_check_not_supported", "err", err)
+			return
+		}
+		lgr.Fatalw("failed_to_validate_bucket_object_lock", "bucket", c.keyStore.bucket, "err", err)
+	}
+	if output.ObjectLockConfiguration == nil || aws.StringValue(output.ObjectLockConfiguration.ObjectLockEnabled) != s3.ObjectLockEnabledEnabled {
+		lgr.Fatalw("bucket_object_lock_not_enabled", "bucket", c.keyStore.bucket)
+	}
+}
+
+// requiredRetainUntil is how long a blob that a manifest written at now references must be locked
+// for to be reused without checking it again.
+func (c *awsClient) requiredRetainUntil(now time.Time) time.Time {
+	if c.objectLock.Enabled() {
+		return now.Add(c.objectLock.Retention)
+	}
+	return now.Add(objectLockSafetyMargin)
+}
+
+// retention is what to extend the retention of objects to. Objects already locked keep their mode,
+// since S3 doesn't allow relaxing COMPLIANCE to GOVERNANCE.
+type retention struct {
+	mode   string
+	until  time.Time
+	dryRun bool
+}
+
+// retainObject extends the retention of the object to r.until, and reports whether it had to. It
+// never shortens a retention.
+func (c *awsClient) retainObject(ctx context.Context, info objectInfo, r retention) (bool, error) {
+	if !info.RetainUntil.Before(r.until) {
+		return false, nil
+	}
+	mode := r.mode
+	if info.RetainMode != "" {
+		mode = info.RetainMode
+	}
+	if r.dryRun {
+		return true, nil
+	}
+	_, err := c.s3Svc.PutObjectRetentionWithContext(ctx, &s3.PutObjectRetentionInput{
+		Bucket: &c.keyStore.bucket,
+		Key:    &info.Key,
+		Retention: &s3.ObjectLockRetention{
+			Mode:            &mode,
+			RetainUntilDate: aws.Time(r.until),
+		},
+	})
+	return err == nil, err
+}
+
+// retainBlob extends the retention of the blob and of its data keys, without which a sealed blob
+// can't be read. It returns the number of objects it looked at and the number it extended.
+func (c *awsClient) retainBlob(ctx context.Context, digests digest.ForRestore, blob objectInfo, r retention) (int, int, error) {
+	objects, extended := 1, 0
+	if ok, err := c.retainObject(ctx, blob, r); err != nil {
+		return objects, extended, err
+	} else if ok {
+		extended++
+	}
+	dataKeys, err := c.listObjects(ctx, c.keyStore.absoluteKeyPrefixForBlobDataKeys(digests))
+	if err != nil {
+		return objects, extended, err
+	}
+	for _, listed := range dataKeys {
+		info, err := c.statObject(ctx, listed.Key)
+		if err != nil {
+			return objects, extended, err
+		}
+		objects++
+		if ok, err := c.retainObject(ctx, info, r); err != nil {
+			return objects, extended, err
+		} else if ok {
+			extended++
+		}
+	}
+	return objects, extended, nil
+}
+
+type RetentionResult struct {
+	Manifests int
+	Blobs     int
+	// Objects counts the blobs, data keys and manifests looked at, of which Extended were retained
+	// for less than required.
+	Objects  int
+	Extended int
+	Missing  int
+	Failed   int
+}
+
+// Retainer extends the S3 Object Lock retention of manifests, of the blobs they reference and of the
+// blobs' data keys, such as to keep the backups from before an incident for longer than usual.
+// Blobs referenced by manifests of several hosts are only looked at once.
+type Retainer struct {
+	client      *awsClient
+	retention   retention
+	concurrency int
+	seen        map[digest.ForRestore]struct{}
+
+	lock   sync.Mutex
+	Result RetentionResult
+}
+
+// NewRetainer returns a Retainer that extends retention to until, locking objects that aren't
+// already locked in mode.
+func NewRetainer(c Client, mode string, until time.Time, concurrency int, dryRun bool) (*Retainer, error) {
+	client, ok := c.(*awsClient)
+	if !ok {
+		return nil, errors.New("only the s3 storage backend supports Object Lock retention")
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &Retainer{
+		client:      client,
+		retention:   retention{mode: mode, until: until, dryRun: dryRun},
+		concurrency: concurrency,
+		seen:        make(map[digest.ForRestore]struct{}),
+	}, nil
+}
+
+// Retain extends the retention of the given manifests of a host and of everything they reference.
+// Blobs are extended before the manifests, so that a manifest is never retained for longer than
+// what it needs. Failures are logged and counted in Result rather than returned.
+func (r *Retainer) Retain(ctx context.Context, identity manifests.NodeIdentity, keys manifests.ManifestKeys) error {
+	got, err := r.client.GetManifests(ctx, identity, keys)
+	if err != nil {
+		return err
+	}
+	var blobs []digest.ForRestore
+	add := func(digests digest.ForRestore) {
+		if _, ok := r.seen[digests]; !ok {
+			r.seen[digests] = struct{}{}
+			blobs = append(blobs, digests)
+		}
+	}
+	for _, m := range got {
+		for _, file := range m.DataFiles {
+			add(file)
+		}
+		if m.Schema != nil {
+			add(*m.Schema)
+		}
+	}
+
+	var wg sync.WaitGroup
+	limiter := make(chan struct{}, r.concurrency)
+	doneCh := ctx.Done()
+schedule:
+	for _, digests := range blobs {
+		select {
+		case <-doneCh:
+			break schedule
+		case limiter <- struct{}{}:
+		}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-limiter
+				wg.Done()
+			}()
+			r.retainBlob(ctx, digests)
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		absoluteKey := r.client.keyStore.AbsoluteKeyForManifest(identity, key)
+		info, err := r.client.statObject(ctx, absoluteKey)
+		if err == nil {
+			var extended bool
+			if extended, err = r.client.retainObject(ctx, info, r.retention); extended {
+				r.Result.Extended++
+			}
+		}
+		r.Result.Manifests++
+		r.Result.Objects++
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			zap.S().Errorw("retain_manifest_error", "key", absoluteKey, "err", err)
+			r.Result.Failed++
+		}
+	}
+	return nil
+}
+
+func (r *Retainer) retainBlob(ctx context.Context, digests digest.ForRestore) {
+	key := r.client.keyStore.AbsoluteKeyForBlob(digests)
+	info, err := r.client.statObject(ctx, key)
+	if IsNoSuchKey(err) {
+		zap.S().Warnw("retain_blob_missing", "key", key)
+		r.lock.Lock()
+		r.Result.Blobs++
+		r.Result.Missing++
+		r.lock.Unlock()
+		return
+	}
+	objects, extended := 0, 0
+	if err == nil {
+		objects, extended, err = r.client.retainBlob(ctx, digests, info, r.retention)
+	}
+	if err != nil && ctx.Err() == nil {
+		zap.S().Errorw("retain_blob_error", "key", key, "err", err)
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.Result.Blobs++
+	r.Result.Objects += objects
+	r.Result.Extended += extended
+	if err != nil {
+		r.Result.Failed++
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bucket
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"go.uber.org/zap"
+)
+
+// objectStore is implemented by every backend for whole small objects such as manifests and
+// wrapped data keys. Reads and writes are retried by the backend.
+type objectStore interface {
+	getObject(ctx context.Context, key string) ([]byte, error)
+	putObject(ctx context.Context, key string, data []byte, contentType, contentEncoding string) error
+	// listObjects returns all objects under prefix, in lexical order. Lock fields are not filled in.
+	listObjects(ctx context.Context, prefix string) ([]objectInfo, error)
+	// statObject returns an error satisfying IsNoSuchKey if the object does not exist.
+	statObject(ctx context.Context, key string) (objectInfo, error)
+	deleteObject(ctx context.Context, key string) error
+}
+
+type objectInfo struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+	RetainUntil  time.Time
+	// RetainMode is the Object Lock mode of RetainUntil, only filled in by S3.
+	RetainMode string
+	LegalHold  bool
+}
+
+// locked reports whether the store would refuse to delete the object, or would only hide it.
+func (o objectInfo) locked(now time.Time) bool {
+	return o.LegalHold || o.RetainUntil.After(now)
+}
+
+func (c *awsClient) putObject(ctx context.Context, absoluteKey string, data []byte, contentType, contentEncoding string) error {
+	putObjectInput := &s3.PutObjectInput{
+		Bucket:      &c.keyStore.bucket,
+		Key:         &absoluteKey,
+		ContentType: aws.String(contentType),
+		Body:        bytes.NewReader(data),
+		// S3 requires the MD5 of objects written with Object Lock retention, by us or by default.
+		ContentMD5: aws.String(contentMD5(data)),
+	}
+	c.encryption.ApplyPutObject(putObjectInput)
+	// The pending deletes are rewritten by every prune, and keeping old versions of them is pointless.
+	if absoluteKey != c.keyStore.absoluteKeyForPendingDeletes() {
+		c.objectLock.ApplyPutObject(putObjectInput, time.Now())
+	}
+	if contentEncoding != "" {
+		putObjectInput.ContentEncoding = aws.String(contentEncoding)
+	}
+	attempts := 0
+	for {
+		_, err := c.s3Svc.PutObjectWithContext(ctx, putObjectInput)
+		if err != nil {
+			attempts++
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			if attempts > putJsonRetriesLimit {
+				return err
+			}
+			zap.S().Warnw("s3_put_object_error", "err", err, "attempts", attempts)
+			time.Sleep(time.Duration(attempts) * retrySleepPerAttempt)
+		} else {
+			return nil
+		}
+	}
+}
+
+func (c *awsClient) getObject(ctx context.Context, absoluteKey string) ([]byte, error) {
+	getObjectInput := &s3.GetObjectInput{
+		Bucket: &c.keyStore.bucket,
+		Key:    &absoluteKey,
+	}
+	c.encryption.ApplyGetObject(getObjectInput)
+	attempts := 0
+	for {
+		getObjectOutput, err := c.s3Svc.GetObjectWithContext(ctx, getObjectInput)
+		var data []byte
+		if err == nil {
+			data, err = io.ReadAll(getObjectOutput.Body)
+			_ = getObjectOutput.Body.Close()
+			if err == nil {
+				return data, nil
+			}
+		}
+		if IsNoSuchKey(err) {
+			return nil, err
+		}
+		if err = asDecryptError(absoluteKey, err); isDecryptError(err) {
+			return nil, err
+		}
+		attempts++
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		if attempts > getJsonRetriesLimit {
+			return nil, err
+		}
+		zap.S().Warnw("s3_get_object_error", "err", err, "attempts", attempts)
+		time.Sleep(time.Duration(attempts) * retrySleepPerAttempt)
+	}
+}
+
+func (c *awsClient) listObjects(ctx context.Context, prefix string) ([]objectInfo, error) {
+	input := &s3.ListObjectsV2Input{
+		Bucket: &c.keyStore.bucket,
+		Prefix: &prefix,
+	}
+	var objects []objectInfo
+	err := c.s3Svc.ListObjectsV2PagesWithContext(ctx, input, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, obj := range page.Contents {
+			objects = append(objects, objectInfo{
+				Key:          *obj.Key,
+				Size:         aws.Int64Value(obj.Size),
+				LastModified: aws.TimeValue(obj.LastModified),
+			})
+		}
+		return true
+	})
+	return objects, err
+}
+
+func (c *awsClient) statObject(ctx context.Context, absoluteKey string) (objectInfo, error) {
+	input := &s3.HeadObjectInput{
+		Bucket: &c.keyStore.bucket,
+		Key:    &absoluteKey,
+	}
+	c.encryption.ApplyHeadObject(input)
+	output, err := c.s3Svc.HeadObjectWithContext(ctx, input)
+	if err != nil {
+		return objectInfo{}, err
+	}
+	return headObjectInfo(absoluteKey, output), nil
+}
+
+func headObjectInfo(absoluteKey string, output *s3.HeadObjectOutput) objectInfo {
+	return objectInfo{
+		Key:          absoluteKey,
+		Size:         aws.Int64Value(output.ContentLength),
+		LastModified: aws.TimeValue(output.LastModified),
+		RetainUntil:  aws.TimeValue(output.ObjectLockRetainUntilDate),
+		RetainMode:   aws.StringValue(output.ObjectLockMode),
+		LegalHold:    aws.StringValue(output.ObjectLockLegalHoldStatus) == s3.ObjectLockLegalHoldStatusOn,
+	}
+}
+
+func contentMD5(data []byte) string {
+	sum := md5.Sum(data)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func (c *awsClient) deleteObject(ctx context.Context, absoluteKey string) error {
+	_, err := c.s3Svc.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: &c.keyStore.bucket,
+		Key:    &absoluteKey,
+	})
+	return err
+}
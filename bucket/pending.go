@@ -0,0 +1,77 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:generate go run github.com/mailru/easyjson/easyjson -disallow_unknown_fields $GOFILE
+
+package bucket
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/retailnext/cassandrabackup/digest"
+	"github.com/retailnext/cassandrabackup/unixtime"
+)
+
+// pendingDeletes records the blobs that the last prune found unreferenced. They are only deleted
+// by a later prune, once every backup that could have decided to reuse them has finished. Backups
+// that see the list treat these blobs as missing and upload them again if they need them.
+//
+//easyjson:json
+type pendingDeletes struct {
+	MarkedAt unixtime.Seconds    `json:"marked_at"`
+	Blobs    []digest.ForRestore `json:"blobs"`
+}
+
+// PendingDeletesRefresh is how long a backup may go on using a copy of the pending deletes.
+const PendingDeletesRefresh = time.Hour
+
+type pendingDeletesCache struct {
+	lock     sync.Mutex
+	loadedAt time.Time
+	blobs    map[digest.ForRestore]struct{}
+}
+
+// pendingDeletesCaches holds a *pendingDeletesCache per KeyStore.
+var pendingDeletesCaches sync.Map
+
+func getPendingDeletes(ctx context.Context, store blobStore) (pendingDeletes, error) {
+	var pending pendingDeletes
+	err := getDocument(ctx, store, store.KeyStore().absoluteKeyForPendingDeletes(), &pending)
+	if IsNoSuchKey(err) {
+		return pendingDeletes{}, nil
+	}
+	return pending, err
+}
+
+func isPendingDelete(ctx context.Context, store blobStore, digests digest.ForRestore) (bool, error) {
+	value, _ := pendingDeletesCaches.LoadOrStore(*store.KeyStore(), &pendingDeletesCache{})
+	cache := value.(*pendingDeletesCache)
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	if cache.blobs == nil || time.Since(cache.loadedAt) > PendingDeletesRefresh {
+		pending, err := getPendingDeletes(ctx, store)
+		if err != nil {
+			return false, err
+		}
+		cache.blobs = make(map[digest.ForRestore]struct{}, len(pending.Blobs))
+		for _, blob := range pending.Blobs {
+			cache.blobs[blob] = struct{}{}
+		}
+		cache.loadedAt = time.Now()
+	}
+	_, pending := cache.blobs[digests]
+	return pending, nil
+}
@@ -0,0 +1,132 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package bucket
+
+import (
+	json "encoding/json"
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+	digest "github.com/retailnext/cassandrabackup/digest"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjson6e0e5e1fDecodeGithubComRetailnextCassandrabackupBucket(in *jlexer.Lexer, out *pendingDeletes) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "marked_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				(out.MarkedAt).UnmarshalEasyJSON(in)
+			}
+		case "blobs":
+			if in.IsNull() {
+				in.Skip()
+				out.Blobs = nil
+			} else {
+				in.Delim('[')
+				if out.Blobs == nil {
+					if !in.IsDelim(']') {
+						out.Blobs = make([]digest.ForRestore, 0, 1)
+					} else {
+						out.Blobs = []digest.ForRestore{}
+					}
+				} else {
+					out.Blobs = (out.Blobs)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 digest.ForRestore
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v1).UnmarshalEasyJSON(in)
+					}
+					out.Blobs = append(out.Blobs, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson6e0e5e1fEncodeGithubComRetailnextCassandrabackupBucket(out *jwriter.Writer, in pendingDeletes) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"marked_at\":"
+		out.RawString(prefix[1:])
+		(in.MarkedAt).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"blobs\":"
+		out.RawString(prefix)
+		if in.Blobs == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v2, v3 := range in.Blobs {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				(v3).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v pendingDeletes) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson6e0e5e1fEncodeGithubComRetailnextCassandrabackupBucket(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v pendingDeletes) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson6e0e5e1fEncodeGithubComRetailnextCassandrabackupBucket(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *pendingDeletes) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson6e0e5e1fDecodeGithubComRetailnextCassandrabackupBucket(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *pendingDeletes) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson6e0e5e1fDecodeGithubComRetailnextCassandrabackupBucket(l, v)
+}
@@ -0,0 +1,315 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bucket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/retailnext/cassandrabackup/digest"
+	"github.com/retailnext/cassandrabackup/manifests"
+	"github.com/retailnext/cassandrabackup/unixtime"
+	"go.uber.org/zap"
+)
+
+const sweepConcurrency = 8
+
+var ErrObjectLocked = errors.New("object is locked")
+
+// DeleteManifest deletes a manifest unless an object lock retention date or legal hold protects it,
+// in which case ErrObjectLocked is returned.
+func DeleteManifest(ctx context.Context, c Client, identity manifests.NodeIdentity, key manifests.ManifestKey, dryRun bool) error {
+	store, ok := c.(objectStore)
+	if !ok {
+		return fmt.Errorf("storage backend does not support deletion")
+	}
+	absoluteKey := c.KeyStore().AbsoluteKeyForManifest(identity, key)
+	info, err := store.statObject(ctx, absoluteKey)
+	if err != nil {
+		return err
+	}
+	if info.locked(time.Now()) {
+		return ErrObjectLocked
+	}
+	if dryRun {
+		return nil
+	}
+	return store.deleteObject(ctx, absoluteKey)
+}
+
+type GarbageReport struct {
+	Blobs             int       `json:"blobs"`
+	BlobBytes         int64     `json:"blob_bytes"`
+	Referenced        int       `json:"referenced_blobs"`
+	Missing           int       `json:"missing_blobs"`
+	Unreferenced      int       `json:"unreferenced_blobs"`
+	UnreferencedBytes int64     `json:"unreferenced_bytes"`
+	Deleted           int       `json:"deleted_blobs"`
+	DeletedBytes      int64     `json:"deleted_bytes"`
+	Locked            int       `json:"locked_blobs"`
+	Reuploaded        int       `json:"reuploaded_blobs"`
+	Failed            int       `json:"failed_blobs"`
+	Marked            int       `json:"marked_blobs"`
+	MarkedAt          time.Time `json:"marked_at,omitzero"`
+	SweepAfter        time.Time `json:"sweep_after,omitzero"`
+}
+
+// blobObjects are the objects stored for one blob: the blob itself, if present, and its wrapped data keys.
+type blobObjects struct {
+	blob   *objectInfo
+	keys   []objectInfo
+	newest time.Time
+}
+
+func (o *blobObjects) add(info objectInfo) {
+	if info.LastModified.After(o.newest) {
+		o.newest = info.LastModified
+	}
+}
+
+func (o *blobObjects) size() int64 {
+	if o.blob == nil {
+		return 0
+	}
+	return o.blob.Size
+}
+
+// CollectGarbage deletes blobs that no manifest references, in two phases so that it is safe to run
+// while hosts are backing up. Unreferenced blobs are first marked by recording them in the bucket;
+// backups stop reusing marked blobs within PendingDeletesRefresh. A later run, at least grace after
+// the marking, deletes the marked blobs that are still unreferenced and have not been uploaded again
+// within grace.
+// grace must therefore exceed PendingDeletesRefresh plus the longest a backup can take, and referenced
+// must be built from manifests listed after listedAt. Blobs protected by object lock are kept and
+// marked again. Objects uploaded within grace of listedAt are never marked, since the backup that
+// uploaded them may not have written its manifest yet.
+func CollectGarbage(ctx context.Context, c Client, referenced map[digest.ForRestore]struct{}, listedAt time.Time, grace time.Duration, dryRun bool) (GarbageReport, error) {
+	lgr := zap.S()
+	report := GarbageReport{Referenced: len(referenced)}
+	store, ok := c.(blobStore)
+	if !ok {
+		return report, fmt.Errorf("storage backend does not support deletion")
+	}
+	keyStore := store.KeyStore()
+
+	previous, err := getPendingDeletes(ctx, store)
+	if err != nil {
+		return report, err
+	}
+	stored, err := listBlobObjects(ctx, store)
+	if err != nil {
+		return report, err
+	}
+
+	unreferenced := make(map[digest.ForRestore]*blobObjects)
+	cutoff := listedAt.Add(-grace)
+	for digests, objects := range stored {
+		if objects.blob != nil {
+			report.Blobs++
+			report.BlobBytes += objects.blob.Size
+		}
+		if _, ok := referenced[digests]; ok || objects.newest.After(cutoff) {
+			continue
+		}
+		unreferenced[digests] = objects
+		report.Unreferenced++
+		report.UnreferencedBytes += objects.size()
+	}
+	for digests := range referenced {
+		if objects, ok := stored[digests]; !ok || objects.blob == nil {
+			report.Missing++
+		}
+	}
+
+	markedAt := previous.MarkedAt.Time()
+	sweep := previous.MarkedAt != 0 && listedAt.Sub(markedAt) >= grace
+	deleted := make(map[digest.ForRestore]struct{})
+	if sweep {
+		var lock sync.Mutex
+		var wg sync.WaitGroup
+		limiter := make(chan struct{}, sweepConcurrency)
+		doneCh := ctx.Done()
+	schedule:
+		for _, digests := range previous.Blobs {
+			objects, ok := unreferenced[digests]
+			if !ok {
+				continue
+			}
+			select {
+			case <-doneCh:
+				break schedule
+			case limiter <- struct{}{}:
+			}
+			wg.Add(1)
+			go func() {
+				defer func() {
+					<-limiter
+					wg.Done()
+				}()
+				outcome, err := sweepBlob(ctx, store, objects, cutoff, dryRun)
+				lock.Lock()
+				defer lock.Unlock()
+				switch outcome {
+				case sweepDeleted:
+					deleted[digests] = struct{}{}
+					report.Deleted++
+					report.DeletedBytes += objects.size()
+				case sweepLocked:
+					report.Locked++
+				case sweepReuploaded:
+					report.Reuploaded++
+				case sweepFailed:
+					report.Failed++
+					if ctx.Err() == nil {
+						lgr.Errorw("prune_delete_blob_error", "blob", digests, "err", err)
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+	}
+
+	if previous.MarkedAt != 0 && !sweep {
+		report.Marked = len(previous.Blobs)
+		report.MarkedAt = markedAt
+		report.SweepAfter = markedAt.Add(grace)
+	} else {
+		var next pendingDeletes
+		for digests := range unreferenced {
+			if _, ok := deleted[digests]; !ok {
+				next.Blobs = append(next.Blobs, digests)
+			}
+		}
+		sort.Slice(next.Blobs, func(i, j int) bool {
+			return next.Blobs[i].URLSafe() < next.Blobs[j].URLSafe()
+		})
+		report.Marked = len(next.Blobs)
+		if !dryRun && (len(next.Blobs) > 0 || previous.MarkedAt != 0) {
+			now := time.Now()
+			next.MarkedAt = unixtime.Seconds(now.Unix())
+			if err := putDocument(ctx, store, keyStore.absoluteKeyForPendingDeletes(), &next); err != nil {
+				return report, err
+			}
+			pendingDeletesCaches.Delete(*keyStore)
+			report.MarkedAt = next.MarkedAt.Time()
+			report.SweepAfter = report.MarkedAt.Add(grace)
+		}
+	}
+
+	if report.Failed > 0 {
+		return report, fmt.Errorf("failed to delete %d blobs", report.Failed)
+	}
+	return report, nil
+}
+
+// listBlobObjects groups the blob and data key objects in the bucket by blob.
+func listBlobObjects(ctx context.Context, store blobStore) (map[digest.ForRestore]*blobObjects, error) {
+	lgr := zap.S()
+	keyStore := store.KeyStore()
+	result := make(map[digest.ForRestore]*blobObjects)
+	get := func(digests digest.ForRestore) *blobObjects {
+		objects := result[digests]
+		if objects == nil {
+			objects = &blobObjects{}
+			result[digests] = objects
+		}
+		return objects
+	}
+
+	blobs, err := store.listObjects(ctx, keyStore.absoluteKeyPrefixForBlobs())
+	if err != nil {
+		return nil, err
+	}
+	for i := range blobs {
+		digests, err := keyStore.DecodeBlobKey(blobs[i].Key)
+		if err != nil {
+			lgr.Warnw("prune_ignoring_unexpected_object", "key", blobs[i].Key, "err", err)
+			continue
+		}
+		objects := get(digests)
+		objects.blob = &blobs[i]
+		objects.add(blobs[i])
+	}
+
+	keys, err := store.listObjects(ctx, keyStore.absoluteKeyPrefixForDataKeys())
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		digests, err := keyStore.decodeDataKeyKey(key.Key)
+		if err != nil {
+			lgr.Warnw("prune_ignoring_unexpected_object", "key", key.Key, "err", err)
+			continue
+		}
+		objects := get(digests)
+		objects.keys = append(objects.keys, key)
+		objects.add(key)
+	}
+	return result, nil
+}
+
+type sweepOutcome int
+
+const (
+	sweepDeleted sweepOutcome = iota
+	sweepLocked
+	sweepReuploaded
+	sweepFailed
+)
+
+// sweepBlob deletes a marked blob and then its data keys, after checking that none of them is locked
+// or has been written since it was listed as old enough to delete.
+func sweepBlob(ctx context.Context, store objectStore, objects *blobObjects, cutoff time.Time, dryRun bool) (sweepOutcome, error) {
+	var candidates []objectInfo
+	if objects.blob != nil {
+		candidates = append(candidates, *objects.blob)
+	}
+	candidates = append(candidates, objects.keys...)
+
+	now := time.Now()
+	var existing []objectInfo
+	for _, candidate := range candidates {
+		info, err := store.statObject(ctx, candidate.Key)
+		if IsNoSuchKey(err) {
+			continue
+		}
+		if err != nil {
+			return sweepFailed, err
+		}
+		if info.LastModified.After(cutoff) {
+			return sweepReuploaded, nil
+		}
+		if info.locked(now) {
+			return sweepLocked, nil
+		}
+		existing = append(existing, info)
+	}
+	if dryRun {
+		return sweepDeleted, nil
+	}
+	for _, info := range existing {
+		if err := store.deleteObject(ctx, info.Key); err != nil && !IsNoSuchKey(err) {
+			return sweepFailed, err
+		}
+	}
+	return sweepDeleted, nil
+}
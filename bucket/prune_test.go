@@ -0,0 +1,128 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bucket
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/retailnext/cassandrabackup/digest"
+	"github.com/retailnext/cassandrabackup/manifests"
+)
+
+func TestCollectGarbage(t *testing.T) {
+	ctx := context.Background()
+	c := &fileClient{
+		keyStore: newKeyStore(t.TempDir(), ""),
+		keys:     testKeyring(t, testKeyFile(t, "k1"), ""),
+	}
+	kept, keptDigests := testBlobFile(t, 1000)
+	unreferenced, unreferencedDigests := testBlobFile(t, 2000)
+	reused, reusedDigests := testBlobFile(t, 3000)
+	if err := c.PutBlob(ctx, kept, keptDigests); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.PutBlob(ctx, unreferenced, unreferencedDigests); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.PutBlob(ctx, reused, reusedDigests); err != nil {
+		t.Fatal(err)
+	}
+
+	identity := manifests.NodeIdentity{Cluster: "cluster", Hostname: "host"}
+	old := manifests.Manifest{Time: 100, ManifestType: manifests.ManifestTypeSnapshot, DataFiles: map[string]digest.ForRestore{
+		"a": keptDigests.ForRestore(), "b": unreferencedDigests.ForRestore(), "c": reusedDigests.ForRestore(),
+	}}
+	if err := c.PutManifest(ctx, identity, old); err != nil {
+		t.Fatal(err)
+	}
+	if err := DeleteManifest(ctx, c, identity, old.Key(), false); err != nil {
+		t.Fatal(err)
+	}
+	if keys, err := c.ListManifests(ctx, identity, 0, 0); err != nil || len(keys) != 0 {
+		t.Fatalf("expected manifest to be deleted, got %v %v", keys, err)
+	}
+	referenced := map[digest.ForRestore]struct{}{keptDigests.ForRestore(): {}}
+
+	// Blobs younger than the grace period are left alone.
+	grace := 2 * time.Hour
+	report, err := CollectGarbage(ctx, c, referenced, time.Now(), grace, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Blobs != 3 || report.Unreferenced != 0 || report.Marked != 0 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+
+	// The first run only marks.
+	report, err = CollectGarbage(ctx, c, referenced, time.Now().Add(grace), grace, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Unreferenced != 2 || report.Marked != 2 || report.Deleted != 0 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+
+	// A backup needing a marked blob uploads it again instead of reusing it, and references it.
+	if err := c.PutBlob(ctx, reused, reusedDigests); err != nil {
+		t.Fatalf("expected marked blob to be uploaded again, got %v", err)
+	}
+	latest := manifests.Manifest{Time: 200, ManifestType: manifests.ManifestTypeSnapshot, DataFiles: map[string]digest.ForRestore{
+		"a": keptDigests.ForRestore(), "c": reusedDigests.ForRestore(),
+	}}
+	if err := c.PutManifest(ctx, identity, latest); err != nil {
+		t.Fatal(err)
+	}
+	referenced[reusedDigests.ForRestore()] = struct{}{}
+
+	// Too soon after marking nothing is deleted.
+	report, err = CollectGarbage(ctx, c, referenced, time.Now().Add(grace/2), grace, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Deleted != 0 || report.Marked != 2 || report.SweepAfter.IsZero() {
+		t.Fatalf("unexpected report %+v", report)
+	}
+
+	listedAt := time.Now().Add(2 * grace)
+	report, err = CollectGarbage(ctx, c, referenced, listedAt, grace, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Deleted != 1 {
+		t.Fatalf("unexpected dry run report %+v", report)
+	}
+	report, err = CollectGarbage(ctx, c, referenced, listedAt, grace, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Deleted != 1 || report.DeletedBytes == 0 || report.Marked != 0 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+
+	stored, err := listBlobObjects(ctx, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := stored[unreferencedDigests.ForRestore()]; ok {
+		t.Fatal("unreferenced blob or its data key was not deleted")
+	}
+	for _, digests := range []digest.ForRestore{keptDigests.ForRestore(), reusedDigests.ForRestore()} {
+		if objects := stored[digests]; objects == nil || objects.blob == nil || len(objects.keys) == 0 {
+			t.Fatalf("blob %v was deleted", digests)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bucket
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/retailnext/cassandrabackup/envelope"
+	"go.uber.org/zap"
+)
+
+const rewrapConcurrency = 8
+
+type RewrapResult struct {
+	DataKeys           int
+	DataKeysRewrapped  int
+	Manifests          int
+	ManifestsRewrapped int
+	PlaintextManifests int
+	Failed             int
+}
+
+// RewrapKeys re-wraps every blob data key and sealed manifest in the bucket with the active master key.
+// Only wrapped keys are rewritten; blob data is never downloaded or uploaded. Hosts still configured
+// with the old master key keep wrapping new data keys with it, so once they have been switched over it
+// is worth running again; a dry run reporting nothing to re-wrap means the old key can be retired.
+func RewrapKeys(ctx context.Context, c Client, dryRun bool) (RewrapResult, error) {
+	var result RewrapResult
+	store, ok := c.(blobStore)
+	if !ok {
+		return result, fmt.Errorf("storage backend does not support key rotation")
+	}
+	keyring := store.keyring()
+	if keyring == nil {
+		return result, fmt.Errorf("client-side encryption is not configured")
+	}
+	keyStore := store.KeyStore()
+
+	dataKeys, err := store.listObjects(ctx, keyStore.absoluteKeyPrefixForDataKeys())
+	if err != nil {
+		return result, err
+	}
+	manifestKeys, err := store.listObjects(ctx, keyStore.absoluteKeyPrefixForClusters())
+	if err != nil {
+		return result, err
+	}
+
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	limiter := make(chan struct{}, rewrapConcurrency)
+	rewrap := func(key string, document bool) {
+		defer func() {
+			<-limiter
+			wg.Done()
+		}()
+		sealed, changed, err := rewrapObject(ctx, store, keyring, key, document, dryRun)
+		lock.Lock()
+		defer lock.Unlock()
+		switch {
+		case err != nil:
+			result.Failed++
+			if ctx.Err() == nil {
+				zap.S().Errorw("rewrap_key_error", "key", key, "err", err)
+			}
+		case !document:
+			result.DataKeys++
+			if changed {
+				result.DataKeysRewrapped++
+			}
+		case !sealed:
+			result.PlaintextManifests++
+		default:
+			result.Manifests++
+			if changed {
+				result.ManifestsRewrapped++
+			}
+		}
+	}
+
+	doneCh := ctx.Done()
+	schedule := func(objects []objectInfo, document bool) {
+		for _, object := range objects {
+			select {
+			case <-doneCh:
+				return
+			case limiter <- struct{}{}:
+				wg.Add(1)
+				go rewrap(object.Key, document)
+			}
+		}
+	}
+	schedule(dataKeys, false)
+	schedule(manifestKeys, true)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+	if result.Failed > 0 {
+		return result, fmt.Errorf("failed to re-wrap %d keys", result.Failed)
+	}
+	return result, nil
+}
+
+func rewrapObject(ctx context.Context, store blobStore, keyring *envelope.Keyring, key string, document, dryRun bool) (bool, bool, error) {
+	data, err := store.getObject(ctx, key)
+	if err != nil {
+		return false, false, err
+	}
+	var rewrapped []byte
+	var changed bool
+	if document {
+		if !envelope.IsSealedDocument(data) {
+			return false, false, nil
+		}
+		rewrapped, changed, err = keyring.RewrapDocument(ctx, data)
+	} else {
+		var wrapped envelope.WrappedKey
+		if err := wrapped.UnmarshalBinary(data); err != nil {
+			return true, false, err
+		}
+		if wrapped, changed, err = keyring.Rewrap(ctx, wrapped); err == nil && changed {
+			rewrapped, err = wrapped.MarshalBinary()
+		}
+	}
+	if err != nil || !changed || dryRun {
+		return true, changed, err
+	}
+	return true, true, store.putObject(ctx, key, rewrapped, "application/octet-stream", "")
+}
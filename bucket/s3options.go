@@ -0,0 +1,210 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bucket
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/retailnext/cassandrabackup/bucket/safeuploader"
+)
+
+// SSE modes accepted by --s3-sse.
+const (
+	sseNone     = "none"
+	sseAES256   = s3.ServerSideEncryptionAes256
+	sseKMS      = s3.ServerSideEncryptionAwsKms
+	sseCustomer = "SSE-C"
+
+	// defaultS3Region signs requests to S3-compatible stores, which mostly ignore the region.
+	defaultS3Region   = "us-east-1"
+	customerKeyLength = 32
+
+	// minObjectLockRetention keeps blobs reused from the exists cache retained for longer than
+	// objectLockSafetyMargin.
+	minObjectLockRetention = 24 * time.Hour
+)
+
+var (
+	s3Endpoint       = kingpin.Flag("s3-endpoint", "Use this S3-compatible endpoint, such as MinIO or Ceph RGW, instead of AWS.").String()
+	s3ForcePathStyle = kingpin.Flag("s3-force-path-style", "Address the bucket in the path rather than the host name, as many S3-compatible stores require.").Bool()
+	s3CABundle       = kingpin.Flag("s3-ca-bundle", "PEM file of CA certificates to trust for the S3 endpoint.").ExistingFile()
+	s3SSE            = kingpin.Flag("s3-sse", "Server-side encryption for objects written to S3: none, AES256, aws:kms or SSE-C.").Default(sseAES256).Enum(sseNone, sseAES256, sseKMS, sseCustomer)
+	s3SSEKMSKeyID    = kingpin.Flag("s3-sse-kms-key-id", "KMS key to encrypt with when --s3-sse=aws:kms (default: the AWS managed key).").String()
+	s3SSEBucketKey   = kingpin.Flag("s3-sse-bucket-key", "Use an S3 Bucket Key with --s3-sse=aws:kms, to reduce requests to KMS.").Bool()
+	s3SSECustomerKey = kingpin.Flag("s3-sse-customer-key-file", "File holding the base64 256 bit key to encrypt with when --s3-sse=SSE-C.").ExistingFile()
+
+	s3ObjectLockMode      = kingpin.Flag("s3-object-lock-mode", "Object Lock mode to retain objects written to S3 in: GOVERNANCE or COMPLIANCE (default: the bucket default retention).").Enum(s3.ObjectLockModeGovernance, s3.ObjectLockModeCompliance)
+	s3ObjectLockRetention = kingpin.Flag("s3-object-lock-retention", "How long to retain objects written to S3 with --s3-object-lock-mode, such as 720h. Reused blobs are retained for longer when needed.").Duration()
+)
+
+// newAWSSession makes the session for the S3 bucket, which may be on an S3-compatible store.
+func newAWSSession(region string) (*session.Session, error) {
+	if region == "" && *s3Endpoint != "" {
+		region = defaultS3Region
+	}
+	awsConf := aws.NewConfig().WithRegion(region)
+	if *s3Endpoint != "" {
+		awsConf = awsConf.WithEndpoint(*s3Endpoint)
+	}
+	if *s3ForcePathStyle {
+		awsConf = awsConf.WithS3ForcePathStyle(true)
+	}
+	options := session.Options{Config: *awsConf}
+	if *s3CABundle != "" {
+		bundle, err := os.Open(*s3CABundle)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			_ = bundle.Close()
+		}()
+		options.CustomCABundle = bundle
+	}
+	return session.NewSessionWithOptions(options)
+}
+
+// s3Encryption returns the request fields for the encryption --s3-sse asks for.
+func s3Encryption(mode, kmsKeyID string, bucketKey bool, customerKeyFile string) (safeuploader.Encryption, error) {
+	var e safeuploader.Encryption
+	if kmsKeyID != "" && mode != sseKMS {
+		return e, fmt.Errorf("--s3-sse-kms-key-id requires --s3-sse=%s", sseKMS)
+	}
+	if bucketKey && mode != sseKMS {
+		return e, fmt.Errorf("--s3-sse-bucket-key requires --s3-sse=%s", sseKMS)
+	}
+	if customerKeyFile != "" && mode != sseCustomer {
+		return e, fmt.Errorf("--s3-sse-customer-key-file requires --s3-sse=%s", sseCustomer)
+	}
+	switch mode {
+	case sseNone:
+	case sseAES256:
+		e.ServerSideEncryption = aws.String(s3.ServerSideEncryptionAes256)
+	case sseKMS:
+		e.ServerSideEncryption = aws.String(s3.ServerSideEncryptionAwsKms)
+		if kmsKeyID != "" {
+			e.SSEKMSKeyID = aws.String(kmsKeyID)
+		}
+		if bucketKey {
+			e.BucketKeyEnabled = aws.Bool(true)
+		}
+	case sseCustomer:
+		if customerKeyFile == "" {
+			return e, fmt.Errorf("--s3-sse=%s requires --s3-sse-customer-key-file", sseCustomer)
+		}
+		key, err := readCustomerKey(customerKeyFile)
+		if err != nil {
+			return e, err
+		}
+		e.SSECustomerAlgorithm = aws.String(s3.ServerSideEncryptionAes256)
+		e.SSECustomerKey = aws.String(string(key))
+	default:
+		return e, fmt.Errorf("unknown --s3-sse %q", mode)
+	}
+	return e, nil
+}
+
+// s3ObjectLock returns the retention --s3-object-lock-mode and --s3-object-lock-retention ask for.
+func s3ObjectLock(mode string, retention time.Duration) (safeuploader.ObjectLock, error) {
+	if mode == "" {
+		if retention != 0 {
+			return safeuploader.ObjectLock{}, errors.New("--s3-object-lock-retention requires --s3-object-lock-mode")
+		}
+		return safeuploader.ObjectLock{}, nil
+	}
+	if retention < minObjectLockRetention {
+		return safeuploader.ObjectLock{}, fmt.Errorf("--s3-object-lock-mode requires --s3-object-lock-retention of at least %s", minObjectLockRetention)
+	}
+	return safeuploader.ObjectLock{Mode: mode, Retention: retention}, nil
+}
+
+func readCustomerKey(name string) ([]byte, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("customer key in %s is not base64: %w", name, err)
+	}
+	if len(key) != customerKeyLength {
+		return nil, fmt.Errorf("customer key in %s is %d bytes, not %d", name, len(key), customerKeyLength)
+	}
+	return key, nil
+}
+
+// checkBucketEncryption returns an error unless the bucket's default encryption matches e. A bucket
+// default of SSE-KMS is good enough for AES256.
+func checkBucketEncryption(config *s3.ServerSideEncryptionConfiguration, e safeuploader.Encryption) error {
+	var defaults []*s3.ServerSideEncryptionByDefault
+	if config != nil {
+		for _, rule := range config.Rules {
+			if rule.ApplyServerSideEncryptionByDefault != nil && rule.ApplyServerSideEncryptionByDefault.SSEAlgorithm != nil {
+				defaults = append(defaults, rule.ApplyServerSideEncryptionByDefault)
+			}
+		}
+	}
+	if len(defaults) == 0 {
+		return errors.New("bucket is not configured with a default SSE algorithm")
+	}
+	if aws.StringValue(e.ServerSideEncryption) != sseKMS {
+		return nil
+	}
+	want := aws.StringValue(e.SSEKMSKeyID)
+	for _, d := range defaults {
+		if !strings.HasPrefix(aws.StringValue(d.SSEAlgorithm), sseKMS) {
+			continue
+		}
+		if sameKMSKey(want, aws.StringValue(d.KMSMasterKeyID)) {
+			return nil
+		}
+	}
+	algorithm, key := aws.StringValue(defaults[0].SSEAlgorithm), aws.StringValue(defaults[0].KMSMasterKeyID)
+	if want == "" {
+		want = "the AWS managed key"
+	}
+	if key == "" {
+		key = "the AWS managed key"
+	}
+	return fmt.Errorf("bucket default encryption is %s with %s, not %s with %s", algorithm, key, sseKMS, want)
+}
+
+// sameKMSKey reports whether two ways of naming a KMS key, as a key ID, alias or ARN of either,
+// name the same key. An empty name is the AWS managed key. An alias and a key ID can't be compared
+// without asking KMS, so they don't match.
+func sameKMSKey(a, b string) bool {
+	return kmsKeyName(a) == kmsKeyName(b)
+}
+
+func kmsKeyName(id string) string {
+	if strings.HasPrefix(id, "arn:") {
+		// arn:aws:kms:region:account:key/id or arn:aws:kms:region:account:alias/name
+		if i := strings.LastIndex(id, ":"); i >= 0 {
+			return id[i+1:]
+		}
+	}
+	if id == "" || strings.HasPrefix(id, "alias/") {
+		return id
+	}
+	return "key/" + id
+}
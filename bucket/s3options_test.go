@@ -0,0 +1,183 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bucket
+
+import (
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/go-test/deep"
+	"github.com/retailnext/cassandrabackup/bucket/safeuploader"
+)
+
+func TestS3Encryption(t *testing.T) {
+	dir := t.TempDir()
+	key := strings.Repeat("k", 32)
+	keyFile := filepath.Join(dir, "key")
+	if err := os.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString([]byte(key))+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	shortKeyFile := filepath.Join(dir, "short")
+	if err := os.WriteFile(shortKeyFile, []byte(base64.StdEncoding.EncodeToString([]byte("short"))), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		mode, kmsKeyID, keyFile string
+		bucketKey               bool
+		expected                safeuploader.Encryption
+		err                     bool
+	}{
+		{mode: sseNone},
+		{mode: sseAES256, expected: safeuploader.Encryption{ServerSideEncryption: aws.String("AES256")}},
+		{mode: sseKMS, expected: safeuploader.Encryption{ServerSideEncryption: aws.String("aws:kms")}},
+		{mode: sseKMS, kmsKeyID: "alias/backups", expected: safeuploader.Encryption{ServerSideEncryption: aws.String("aws:kms"), SSEKMSKeyID: aws.String("alias/backups")}},
+		{mode: sseKMS, kmsKeyID: "alias/backups", bucketKey: true, expected: safeuploader.Encryption{ServerSideEncryption: aws.String("aws:kms"), SSEKMSKeyID: aws.String("alias/backups"), BucketKeyEnabled: aws.Bool(true)}},
+		{mode: sseAES256, bucketKey: true, err: true},
+		{mode: sseCustomer, keyFile: keyFile, expected: safeuploader.Encryption{SSECustomerAlgorithm: aws.String("AES256"), SSECustomerKey: aws.String(key)}},
+		{mode: sseCustomer, err: true},
+		{mode: sseCustomer, keyFile: shortKeyFile, err: true},
+		{mode: sseAES256, kmsKeyID: "alias/backups", err: true},
+		{mode: sseKMS, keyFile: keyFile, err: true},
+	} {
+		got, err := s3Encryption(c.mode, c.kmsKeyID, c.bucketKey, c.keyFile)
+		if c.err {
+			if err == nil {
+				t.Errorf("%+v: expected an error", c)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%+v: %v", c, err)
+			continue
+		}
+		if diff := deep.Equal(got, c.expected); diff != nil {
+			t.Errorf("%+v: %v", c, diff)
+		}
+	}
+}
+
+func TestS3ObjectLock(t *testing.T) {
+	for _, c := range []struct {
+		mode      string
+		retention time.Duration
+		expected  safeuploader.ObjectLock
+		err       bool
+	}{
+		{},
+		{mode: "GOVERNANCE", retention: 720 * time.Hour, expected: safeuploader.ObjectLock{Mode: "GOVERNANCE", Retention: 720 * time.Hour}},
+		{mode: "COMPLIANCE", retention: 24 * time.Hour, expected: safeuploader.ObjectLock{Mode: "COMPLIANCE", Retention: 24 * time.Hour}},
+		{mode: "GOVERNANCE", err: true},
+		{mode: "GOVERNANCE", retention: time.Hour, err: true},
+		{retention: 720 * time.Hour, err: true},
+	} {
+		got, err := s3ObjectLock(c.mode, c.retention)
+		if c.err {
+			if err == nil {
+				t.Errorf("%+v: expected an error", c)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%+v: %v", c, err)
+			continue
+		}
+		if diff := deep.Equal(got, c.expected); diff != nil {
+			t.Errorf("%+v: %v", c, diff)
+		}
+	}
+}
+
+func TestIsNotImplemented(t *testing.T) {
+	if !isNotImplemented(awserr.New("NotImplemented", "", nil)) {
+		t.Error("expected NotImplemented to be recognized")
+	}
+	if isNotImplemented(awserr.New("ServerSideEncryptionConfigurationNotFoundError", "", nil)) {
+		t.Error("expected a missing configuration not to be recognized")
+	}
+}
+
+func TestCheckBucketEncryption(t *testing.T) {
+	config := func(algorithm, key string) *s3.ServerSideEncryptionConfiguration {
+		byDefault := &s3.ServerSideEncryptionByDefault{SSEAlgorithm: aws.String(algorithm)}
+		if key != "" {
+			byDefault.KMSMasterKeyID = aws.String(key)
+		}
+		return &s3.ServerSideEncryptionConfiguration{Rules: []*s3.ServerSideEncryptionRule{{ApplyServerSideEncryptionByDefault: byDefault}}}
+	}
+	aes := safeuploader.Encryption{ServerSideEncryption: aws.String("AES256")}
+	kms := func(key string) safeuploader.Encryption {
+		e := safeuploader.Encryption{ServerSideEncryption: aws.String("aws:kms")}
+		if key != "" {
+			e.SSEKMSKeyID = aws.String(key)
+		}
+		return e
+	}
+	const (
+		keyID  = "1234abcd-12ab-34cd-56ef-1234567890ab"
+		keyARN = "arn:aws:kms:us-east-1:111122223333:key/" + keyID
+	)
+	for _, c := range []struct {
+		name     string
+		config   *s3.ServerSideEncryptionConfiguration
+		e        safeuploader.Encryption
+		expectOK bool
+	}{
+		{"no default", &s3.ServerSideEncryptionConfiguration{}, aes, false},
+		{"aes default", config("AES256", ""), aes, true},
+		{"kms default for aes", config("aws:kms", keyARN), aes, true},
+		{"aes default for kms", config("AES256", ""), kms(keyID), false},
+		{"same key by id and arn", config("aws:kms", keyARN), kms(keyID), true},
+		{"same key by arn and id", config("aws:kms", keyID), kms(keyARN), true},
+		{"other key", config("aws:kms", keyARN), kms("9999abcd-12ab-34cd-56ef-1234567890ab"), false},
+		{"managed key", config("aws:kms", ""), kms(""), true},
+		{"managed key for customer key", config("aws:kms", ""), kms(keyID), false},
+		{"same alias", config("aws:kms", "arn:aws:kms:us-east-1:111122223333:alias/backups"), kms("alias/backups"), true},
+		{"dsse", config("aws:kms:dsse", keyID), kms(keyID), true},
+	} {
+		if err := checkBucketEncryption(c.config, c.e); (err == nil) != c.expectOK {
+			t.Errorf("%s: unexpected result %v", c.name, err)
+		}
+	}
+}
+
+func TestAsDecryptError(t *testing.T) {
+	for _, c := range []struct {
+		err     error
+		decrypt bool
+	}{
+		{awserr.New("AccessDenied", "User is not authorized to perform: kms:Decrypt", nil), true},
+		{awserr.New("KMS.DisabledException", "key is disabled", nil), true},
+		{awserr.New("InvalidRequest", "The object was stored using a form of Server Side Encryption. The correct parameters must be provided to retrieve the object.", nil), true},
+		{awserr.New("AccessDenied", "Access Denied", nil), false},
+		{awserr.New("NoSuchKey", "", nil), false},
+	} {
+		err := asDecryptError("key", c.err)
+		if isDecryptError(err) != c.decrypt {
+			t.Errorf("%v: expected decrypt error %v", c.err, c.decrypt)
+		}
+		if !errors.Is(err, c.err) {
+			t.Errorf("%v: expected the original error to be wrapped", c.err)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package safeuploader
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxCopyObjectSize is the largest object S3 copies in a single CopyObject request.
+	maxCopyObjectSize = 5 * 1024 * 1024 * 1024
+	minCopyPartSize   = 64 * 1024 * 1024
+	maxParts          = 10000
+)
+
+// Source is an object in S3 to copy from, with the encryption it was written with.
+type Source struct {
+	Bucket     string
+	Key        string
+	Size       int64
+	Encryption Encryption
+}
+
+func (s Source) copySource() *string {
+	u := url.URL{Path: s.Bucket + "/" + s.Key}
+	return aws.String(u.EscapedPath())
+}
+
+// Copy copies source to key within S3, encrypted, stored and locked as Upload would. S3 checks the
+// copy itself, and objects larger than a single request can copy are copied in parts.
+func (u *SafeUploader) Copy(ctx context.Context, key string, source Source) error {
+	now := time.Now()
+	if source.Size <= maxCopyObjectSize {
+		input := &s3.CopyObjectInput{
+			Bucket:       &u.Bucket,
+			Key:          &key,
+			CopySource:   source.copySource(),
+			StorageClass: u.StorageClass,
+		}
+		u.Encryption.applyCopyObject(input)
+		source.Encryption.applyCopySourceObject(input)
+		u.ObjectLock.applyCopyObject(input, now)
+		_, err := u.S3.CopyObjectWithContext(ctx, input)
+		return err
+	}
+
+	createInput := &s3.CreateMultipartUploadInput{
+		Bucket:       &u.Bucket,
+		Key:          &key,
+		StorageClass: u.StorageClass,
+	}
+	u.Encryption.applyCreateMultipartUpload(createInput)
+	u.ObjectLock.applyCreateMultipartUpload(createInput, now)
+	createOutput, err := u.S3.CreateMultipartUploadWithContext(ctx, createInput)
+	if err != nil {
+		return err
+	}
+	uploadID := createOutput.UploadId
+	parts, err := u.copyParts(ctx, key, *uploadID, source)
+	if err == nil {
+		_, err = u.S3.CompleteMultipartUploadWithContext(ctx, &s3.CompleteMultipartUploadInput{
+			Bucket:          &u.Bucket,
+			Key:             &key,
+			UploadId:        uploadID,
+			MultipartUpload: &s3.CompletedMultipartUpload{Parts: parts},
+		})
+	}
+	if err != nil {
+		_, abortErr := u.S3.AbortMultipartUpload(&s3.AbortMultipartUploadInput{
+			Bucket:   &u.Bucket,
+			Key:      &key,
+			UploadId: uploadID,
+		})
+		if abortErr != nil {
+			zap.S().Errorw("abort_multipart_copy_error", "key", key, "err", abortErr)
+		}
+	}
+	return err
+}
+
+// copyPartSize keeps the number of parts within what S3 allows.
+func copyPartSize(size int64) int64 {
+	partSize := int64(minCopyPartSize)
+	if perPart := (size + maxParts - 1) / maxParts; perPart > partSize {
+		partSize = perPart
+	}
+	return partSize
+}
+
+func (u *SafeUploader) copyParts(ctx context.Context, key, uploadID string, source Source) ([]*s3.CompletedPart, error) {
+	partSize := copyPartSize(source.Size)
+	numParts := (source.Size + partSize - 1) / partSize
+	parts := make([]*s3.CompletedPart, numParts)
+	concurrency := u.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var lock sync.Mutex
+	var firstErr error
+	var wg sync.WaitGroup
+	limiter := make(chan struct{}, concurrency)
+	doneCh := ctx.Done()
+schedule:
+	for i := int64(0); i < numParts; i++ {
+		select {
+		case <-doneCh:
+			break schedule
+		case limiter <- struct{}{}:
+		}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-limiter
+				wg.Done()
+			}()
+			first, last := i*partSize, min((i+1)*partSize, source.Size)-1
+			input := &s3.UploadPartCopyInput{
+				Bucket:          &u.Bucket,
+				Key:             &key,
+				UploadId:        &uploadID,
+				PartNumber:      aws.Int64(i + 1),
+				CopySource:      source.copySource(),
+				CopySourceRange: aws.String(fmt.Sprintf("bytes=%d-%d", first, last)),
+			}
+			u.Encryption.applyUploadPartCopy(input)
+			source.Encryption.applyCopySourceUploadPartCopy(input)
+			output, err := u.S3.UploadPartCopyWithContext(ctx, input)
+			lock.Lock()
+			defer lock.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				cancel()
+				return
+			}
+			parts[i] = &s3.CompletedPart{PartNumber: aws.Int64(i + 1), ETag: output.CopyPartResult.ETag}
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return parts, nil
+}
@@ -0,0 +1,43 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package safeuploader
+
+import "testing"
+
+func TestCopyPartSize(t *testing.T) {
+	for _, c := range []struct {
+		size     int64
+		expected int64
+	}{
+		{6 * 1024 * 1024 * 1024, minCopyPartSize},
+		{maxParts * minCopyPartSize, minCopyPartSize},
+		{maxParts*minCopyPartSize + 1, minCopyPartSize + 1},
+	} {
+		got := copyPartSize(c.size)
+		if got != c.expected {
+			t.Errorf("%d: expected %d got %d", c.size, c.expected, got)
+		}
+		if parts := (c.size + got - 1) / got; parts > maxParts {
+			t.Errorf("%d: %d parts", c.size, parts)
+		}
+	}
+}
+
+func TestCopySource(t *testing.T) {
+	s := Source{Bucket: "backups", Key: "prefix/files/blake2b/a/b/c-d_e=="}
+	if got, expected := *s.copySource(), "backups/prefix/files/blake2b/a/b/c-d_e=="; got != expected {
+		t.Errorf("expected %q got %q", expected, got)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package safeuploader
+
+import "github.com/aws/aws-sdk-go/service/s3"
+
+// Encryption is how S3 encrypts the objects written, as the request fields that ask for it. The
+// zero value leaves objects to the bucket default.
+//
+// With SSE-C, S3 keeps no key: the same key has to be sent to upload each part and to read the
+// object back, so ApplyGetObject and ApplyHeadObject are needed for reads as well as writes.
+type Encryption struct {
+	// ServerSideEncryption is "AES256" or "aws:kms", or nil.
+	ServerSideEncryption *string
+	// SSEKMSKeyID is the KMS key to encrypt with when ServerSideEncryption is "aws:kms", or nil for
+	// the AWS managed key.
+	SSEKMSKeyID *string
+	// BucketKeyEnabled asks S3 to use a bucket key with SSE-KMS, to make fewer requests to KMS.
+	BucketKeyEnabled *bool
+	// SSECustomerAlgorithm is "AES256" for SSE-C, with the raw 256 bit key in SSECustomerKey.
+	SSECustomerAlgorithm *string
+	SSECustomerKey       *string
+}
+
+// etagIsMD5 reports whether S3 will return the MD5 of the content as the ETag, which it doesn't for
+// objects encrypted with KMS or a customer key.
+func (e Encryption) etagIsMD5() bool {
+	if e.SSECustomerAlgorithm != nil {
+		return false
+	}
+	return e.ServerSideEncryption == nil || *e.ServerSideEncryption != s3.ServerSideEncryptionAwsKms
+}
+
+func (e Encryption) ApplyPutObject(input *s3.PutObjectInput) {
+	input.ServerSideEncryption = e.ServerSideEncryption
+	input.SSEKMSKeyId = e.SSEKMSKeyID
+	input.BucketKeyEnabled = e.BucketKeyEnabled
+	input.SSECustomerAlgorithm = e.SSECustomerAlgorithm
+	input.SSECustomerKey = e.SSECustomerKey
+}
+
+func (e Encryption) ApplyGetObject(input *s3.GetObjectInput) {
+	input.SSECustomerAlgorithm = e.SSECustomerAlgorithm
+	input.SSECustomerKey = e.SSECustomerKey
+}
+
+func (e Encryption) ApplyHeadObject(input *s3.HeadObjectInput) {
+	input.SSECustomerAlgorithm = e.SSECustomerAlgorithm
+	input.SSECustomerKey = e.SSECustomerKey
+}
+
+func (e Encryption) applyCreateMultipartUpload(input *s3.CreateMultipartUploadInput) {
+	input.ServerSideEncryption = e.ServerSideEncryption
+	input.SSEKMSKeyId = e.SSEKMSKeyID
+	input.BucketKeyEnabled = e.BucketKeyEnabled
+	input.SSECustomerAlgorithm = e.SSECustomerAlgorithm
+	input.SSECustomerKey = e.SSECustomerKey
+}
+
+func (e Encryption) applyUploadPart(input *s3.UploadPartInput) {
+	input.SSECustomerAlgorithm = e.SSECustomerAlgorithm
+	input.SSECustomerKey = e.SSECustomerKey
+}
+
+func (e Encryption) applyCopyObject(input *s3.CopyObjectInput) {
+	input.ServerSideEncryption = e.ServerSideEncryption
+	input.SSEKMSKeyId = e.SSEKMSKeyID
+	input.BucketKeyEnabled = e.BucketKeyEnabled
+	input.SSECustomerAlgorithm = e.SSECustomerAlgorithm
+	input.SSECustomerKey = e.SSECustomerKey
+}
+
+func (e Encryption) applyCopySourceObject(input *s3.CopyObjectInput) {
+	input.CopySourceSSECustomerAlgorithm = e.SSECustomerAlgorithm
+	input.CopySourceSSECustomerKey = e.SSECustomerKey
+}
+
+func (e Encryption) applyUploadPartCopy(input *s3.UploadPartCopyInput) {
+	input.SSECustomerAlgorithm = e.SSECustomerAlgorithm
+	input.SSECustomerKey = e.SSECustomerKey
+}
+
+func (e Encryption) applyCopySourceUploadPartCopy(input *s3.UploadPartCopyInput) {
+	input.CopySourceSSECustomerAlgorithm = e.SSECustomerAlgorithm
+	input.CopySourceSSECustomerKey = e.SSECustomerKey
+}
@@ -0,0 +1,51 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package safeuploader
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func TestRetainUntil(t *testing.T) {
+	lock := ObjectLock{Mode: "GOVERNANCE", Retention: 30 * 24 * time.Hour}
+	for _, c := range []struct {
+		now      time.Time
+		expected time.Time
+	}{
+		{time.Date(2026, 1, 1, 10, 30, 0, 0, time.UTC), time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2026, 1, 1, 20, 0, 0, 0, time.FixedZone("PST", -8*3600)), time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC)},
+	} {
+		if got := lock.RetainUntil(c.now); !got.Equal(c.expected) {
+			t.Errorf("%s: expected %s got %s", c.now, c.expected, got)
+		}
+	}
+}
+
+func TestObjectLockApplyPutObject(t *testing.T) {
+	now := time.Date(2026, 1, 1, 10, 30, 0, 0, time.UTC)
+	var input s3.PutObjectInput
+	ObjectLock{}.ApplyPutObject(&input, now)
+	if input.ObjectLockMode != nil || input.ObjectLockRetainUntilDate != nil {
+		t.Errorf("expected no retention, got %v", input)
+	}
+	ObjectLock{Mode: "COMPLIANCE", Retention: 24 * time.Hour}.ApplyPutObject(&input, now)
+	if *input.ObjectLockMode != "COMPLIANCE" || !input.ObjectLockRetainUntilDate.Equal(time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected retention %v", input)
+	}
+}
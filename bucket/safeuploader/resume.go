@@ -0,0 +1,221 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:generate go run github.com/mailru/easyjson/easyjson -disallow_unknown_fields $GOFILE
+
+package safeuploader
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/retailnext/cassandrabackup/cache"
+	"go.uber.org/zap"
+)
+
+// uploadState is what's remembered about a multipart upload in progress, so that a later attempt to
+// upload the same blob can continue it rather than start over. It is keyed by the object key, which
+// is named after the blob's digest.
+//
+//easyjson:json
+type uploadState struct {
+	UploadID string          `json:"upload_id"`
+	Parts    []completedPart `json:"parts"`
+}
+
+// completedPart records the SHA256 that a part was uploaded with, which S3 doesn't report back.
+//
+//easyjson:json
+type completedPart struct {
+	PartNumber int64  `json:"part_number"`
+	ETag       string `json:"etag"`
+	SHA256     string `json:"sha256"`
+}
+
+func (u *fileUploader) loadState() (uploadState, bool) {
+	var state uploadState
+	if u.resumeCache == nil {
+		return state, false
+	}
+	err := u.resumeCache.Get([]byte(u.key), func(value []byte) error {
+		if err := state.UnmarshalJSON(value); err != nil {
+			return cache.DoNotPromote
+		}
+		return nil
+	})
+	return state, err == nil && state.UploadID != ""
+}
+
+// saveState must be called with u.lock held once the upload has started.
+func (u *fileUploader) saveState() {
+	if u.resumeCache == nil {
+		return
+	}
+	value, err := u.state.MarshalJSON()
+	if err == nil {
+		err = u.resumeCache.Put([]byte(u.key), value)
+	}
+	if err != nil {
+		zap.S().Warnw("save_multipart_upload_state_error", "key", u.key, "err", err)
+	}
+}
+
+func (u *fileUploader) deleteState() {
+	if u.resumeCache == nil {
+		return
+	}
+	if err := u.resumeCache.Delete([]byte(u.key)); err != nil {
+		zap.S().Warnw("delete_multipart_upload_state_error", "key", u.key, "err", err)
+	}
+}
+
+// resume continues a multipart upload left behind by an earlier attempt, if there is one and it
+// still exists. Parts that S3 lists with the size, ETag and SHA256 expected for this body are kept;
+// the rest are uploaded again. Sealed blobs get a new data key on every attempt, so none of their
+// parts ever match.
+func (u *fileUploader) resume(ctx context.Context) bool {
+	lgr := zap.S().With("key", u.key)
+	state, ok := u.loadState()
+	if !ok {
+		return false
+	}
+	recorded := make(map[int64]completedPart, len(state.Parts))
+	for _, part := range state.Parts {
+		recorded[part.PartNumber] = part
+	}
+
+	var kept []completedPart
+	input := &s3.ListPartsInput{
+		Bucket:   &u.bucket,
+		Key:      &u.key,
+		UploadId: &state.UploadID,
+	}
+	err := u.s3Svc.ListPartsPagesWithContext(ctx, input, func(output *s3.ListPartsOutput, lastPage bool) bool {
+		for _, part := range output.Parts {
+			if part.PartNumber == nil || part.ETag == nil || part.Size == nil {
+				continue
+			}
+			partNumber := *part.PartNumber
+			if record, ok := recorded[partNumber]; ok && u.partMatches(partNumber, *part.Size, *part.ETag, record) {
+				kept = append(kept, record)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == s3.ErrCodeNoSuchUpload {
+			lgr.Infow("resume_multipart_upload_gone", "upload_id", state.UploadID)
+		} else {
+			lgr.Warnw("resume_multipart_upload_error", "upload_id", state.UploadID, "err", err)
+		}
+		u.deleteState()
+		return false
+	}
+
+	u.uploadId = state.UploadID
+	u.state = uploadState{UploadID: state.UploadID, Parts: kept}
+	for _, part := range kept {
+		u.etags[part.PartNumber] = part.ETag
+	}
+	resumedUploads.Inc()
+	resumedParts.Add(float64(len(kept)))
+	lgr.Infow("resume_multipart_upload", "upload_id", state.UploadID, "kept_parts", len(kept), "parts", u.partDigests.Parts())
+	return true
+}
+
+// extendRetention extends the Object Lock retention of a completed upload that was resumed, which
+// got the retention of when the upload was first created, to that of an object written at now. It
+// never shortens a retention, and keeps the mode the object already has.
+func (u *fileUploader) extendRetention(ctx context.Context, now time.Time) error {
+	if !u.objectLock.Enabled() {
+		return nil
+	}
+	until := u.objectLock.RetainUntil(now)
+	headObjectInput := &s3.HeadObjectInput{
+		Bucket: &u.bucket,
+		Key:    &u.key,
+	}
+	u.encryption.ApplyHeadObject(headObjectInput)
+	headObjectOutput, err := u.s3Svc.HeadObjectWithContext(ctx, headObjectInput)
+	if err != nil {
+		return err
+	}
+	if headObjectOutput.ObjectLockRetainUntilDate != nil && !headObjectOutput.ObjectLockRetainUntilDate.Before(until) {
+		return nil
+	}
+	mode := u.objectLock.Mode
+	if headObjectOutput.ObjectLockMode != nil && *headObjectOutput.ObjectLockMode != "" {
+		mode = *headObjectOutput.ObjectLockMode
+	}
+	_, err = u.s3Svc.PutObjectRetentionWithContext(ctx, &s3.PutObjectRetentionInput{
+		Bucket:    &u.bucket,
+		Key:       &u.key,
+		VersionId: headObjectOutput.VersionId,
+		Retention: &s3.ObjectLockRetention{
+			Mode:            &mode,
+			RetainUntilDate: &until,
+		},
+	})
+	if err == nil {
+		zap.S().Infow("resume_multipart_upload_extended_retention", "key", u.key, "retain_until", until)
+	}
+	return err
+}
+
+func (u *fileUploader) partMatches(partNumber, size int64, etag string, record completedPart) bool {
+	pd := u.partDigests
+	if partNumber > pd.Parts() || size != pd.PartLength(partNumber) {
+		return false
+	}
+	if etag != record.ETag || record.SHA256 != pd.PartContentSHA256(partNumber) {
+		return false
+	}
+	// The ETag of a part is its MD5, unless it was encrypted with KMS or a customer key.
+	if !u.encryption.etagIsMD5() {
+		return true
+	}
+	if etagMD5, err := hex.DecodeString(strings.Trim(etag, `"`)); err == nil && len(etagMD5) == 16 {
+		expected, err := base64.StdEncoding.DecodeString(pd.PartContentMD5(partNumber))
+		if err != nil || string(expected) != string(etagMD5) {
+			return false
+		}
+	}
+	return true
+}
+
+var (
+	resumedUploads = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "cassandrabackup",
+		Subsystem: "safeuploader",
+		Name:      "resumed_uploads_total",
+		Help:      "Number of multipart uploads resumed from an earlier attempt.",
+	})
+	resumedParts = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "cassandrabackup",
+		Subsystem: "safeuploader",
+		Name:      "resumed_parts_total",
+		Help:      "Number of parts of resumed multipart uploads that did not need to be uploaded again.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(resumedUploads)
+	prometheus.MustRegister(resumedParts)
+}
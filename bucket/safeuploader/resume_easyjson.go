@@ -0,0 +1,222 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package safeuploader
+
+import (
+	json "encoding/json"
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjson39b3a2f5DecodeGithubComRetailnextCassandrabackupBucketSafeuploader(in *jlexer.Lexer, out *uploadState) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "upload_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.UploadID = string(in.String())
+			}
+		case "parts":
+			if in.IsNull() {
+				in.Skip()
+				out.Parts = nil
+			} else {
+				in.Delim('[')
+				if out.Parts == nil {
+					if !in.IsDelim(']') {
+						out.Parts = make([]completedPart, 0, 1)
+					} else {
+						out.Parts = []completedPart{}
+					}
+				} else {
+					out.Parts = (out.Parts)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 completedPart
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v1).UnmarshalEasyJSON(in)
+					}
+					out.Parts = append(out.Parts, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson39b3a2f5EncodeGithubComRetailnextCassandrabackupBucketSafeuploader(out *jwriter.Writer, in uploadState) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"upload_id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.UploadID))
+	}
+	{
+		const prefix string = ",\"parts\":"
+		out.RawString(prefix)
+		if in.Parts == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v2, v3 := range in.Parts {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				(v3).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v uploadState) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson39b3a2f5EncodeGithubComRetailnextCassandrabackupBucketSafeuploader(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v uploadState) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson39b3a2f5EncodeGithubComRetailnextCassandrabackupBucketSafeuploader(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *uploadState) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson39b3a2f5DecodeGithubComRetailnextCassandrabackupBucketSafeuploader(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *uploadState) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson39b3a2f5DecodeGithubComRetailnextCassandrabackupBucketSafeuploader(l, v)
+}
+func easyjson39b3a2f5DecodeGithubComRetailnextCassandrabackupBucketSafeuploader1(in *jlexer.Lexer, out *completedPart) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "part_number":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.PartNumber = int64(in.Int64())
+			}
+		case "etag":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ETag = string(in.String())
+			}
+		case "sha256":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.SHA256 = string(in.String())
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson39b3a2f5EncodeGithubComRetailnextCassandrabackupBucketSafeuploader1(out *jwriter.Writer, in completedPart) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"part_number\":"
+		out.RawString(prefix[1:])
+		out.Int64(int64(in.PartNumber))
+	}
+	{
+		const prefix string = ",\"etag\":"
+		out.RawString(prefix)
+		out.String(string(in.ETag))
+	}
+	{
+		const prefix string = ",\"sha256\":"
+		out.RawString(prefix)
+		out.String(string(in.SHA256))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v completedPart) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson39b3a2f5EncodeGithubComRetailnextCassandrabackupBucketSafeuploader1(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v completedPart) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson39b3a2f5EncodeGithubComRetailnextCassandrabackupBucketSafeuploader1(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *completedPart) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson39b3a2f5DecodeGithubComRetailnextCassandrabackupBucketSafeuploader1(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *completedPart) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson39b3a2f5DecodeGithubComRetailnextCassandrabackupBucketSafeuploader1(l, v)
+}
@@ -0,0 +1,238 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package safeuploader
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/retailnext/cassandrabackup/cache"
+	"github.com/retailnext/cassandrabackup/digest/parts"
+)
+
+type fakePart struct {
+	etag string
+	size int64
+}
+
+// fakeS3 keeps multipart uploads in memory, failing the upload of failPart.
+type fakeS3 struct {
+	s3iface.S3API
+
+	lock      sync.Mutex
+	uploads   map[string]map[int64]fakePart
+	sent      []int64
+	failPart  int64
+	completed int
+	aborted   int
+
+	// retainUntil is the retention of the object, which multipart uploads set on completion.
+	retainUntil time.Time
+	retainMode  string
+	retentions  int
+}
+
+func (f *fakeS3) CreateMultipartUploadWithContext(ctx aws.Context, input *s3.CreateMultipartUploadInput, opts ...request.Option) (*s3.CreateMultipartUploadOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	id := fmt.Sprintf("upload-%d", len(f.uploads))
+	if input.ObjectLockRetainUntilDate != nil {
+		f.retainUntil, f.retainMode = *input.ObjectLockRetainUntilDate, *input.ObjectLockMode
+	}
+	f.uploads[id] = make(map[int64]fakePart)
+	return &s3.CreateMultipartUploadOutput{UploadId: aws.String(id)}, nil
+}
+
+func (f *fakeS3) UploadPartWithContext(ctx aws.Context, input *s3.UploadPartInput, opts ...request.Option) (*s3.UploadPartOutput, error) {
+	data, err := io.ReadAll(input.Body)
+	if err != nil {
+		return nil, err
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.sent = append(f.sent, *input.PartNumber)
+	if *input.PartNumber == f.failPart {
+		return nil, fmt.Errorf("connection reset")
+	}
+	sum := md5.Sum(data)
+	etag := `"` + hex.EncodeToString(sum[:]) + `"`
+	f.uploads[*input.UploadId][*input.PartNumber] = fakePart{etag: etag, size: int64(len(data))}
+	return &s3.UploadPartOutput{ETag: aws.String(etag)}, nil
+}
+
+func (f *fakeS3) ListPartsPagesWithContext(ctx aws.Context, input *s3.ListPartsInput, fn func(*s3.ListPartsOutput, bool) bool, opts ...request.Option) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	var output s3.ListPartsOutput
+	for partNumber, part := range f.uploads[*input.UploadId] {
+		output.Parts = append(output.Parts, &s3.Part{PartNumber: aws.Int64(partNumber), ETag: aws.String(part.etag), Size: aws.Int64(part.size)})
+	}
+	fn(&output, true)
+	return nil
+}
+
+func (f *fakeS3) CompleteMultipartUpload(input *s3.CompleteMultipartUploadInput) (*s3.CompleteMultipartUploadOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	for _, part := range input.MultipartUpload.Parts {
+		if f.uploads[*input.UploadId][*part.PartNumber].etag != *part.ETag {
+			return nil, fmt.Errorf("invalid part %d", *part.PartNumber)
+		}
+	}
+	f.completed++
+	return &s3.CompleteMultipartUploadOutput{}, nil
+}
+
+func (f *fakeS3) AbortMultipartUpload(input *s3.AbortMultipartUploadInput) (*s3.AbortMultipartUploadOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.aborted++
+	delete(f.uploads, *input.UploadId)
+	return &s3.AbortMultipartUploadOutput{}, nil
+}
+
+func (f *fakeS3) HeadObjectWithContext(ctx aws.Context, input *s3.HeadObjectInput, opts ...request.Option) (*s3.HeadObjectOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	output := &s3.HeadObjectOutput{}
+	if !f.retainUntil.IsZero() {
+		output.ObjectLockRetainUntilDate = aws.Time(f.retainUntil)
+		output.ObjectLockMode = aws.String(f.retainMode)
+	}
+	return output, nil
+}
+
+func (f *fakeS3) PutObjectRetentionWithContext(ctx aws.Context, input *s3.PutObjectRetentionInput, opts ...request.Option) (*s3.PutObjectRetentionOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.retainUntil, f.retainMode = *input.Retention.RetainUntilDate, *input.Retention.Mode
+	f.retentions++
+	return &s3.PutObjectRetentionOutput{}, nil
+}
+
+func TestResume(t *testing.T) {
+	storage, err := cache.Open(filepath.Join(t.TempDir(), "cache.db"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer storage.Close()
+
+	body := bytes.Repeat([]byte("0123456789"), 3)
+	var maker parts.PartDigestsMaker
+	maker.Reset(10)
+	if _, err := maker.Write(body); err != nil {
+		t.Fatal(err)
+	}
+	pd := maker.Finish()
+
+	fake := &fakeS3{uploads: make(map[string]map[int64]fakePart), failPart: 3}
+	u := &SafeUploader{S3: fake, Bucket: "bucket", Concurrency: 1, ResumeCache: storage.Cache("multipart_uploads")}
+	ctx := context.Background()
+
+	if err := u.Upload(ctx, "files/blob", bytes.NewReader(body), &pd); err == nil {
+		t.Fatal("expected the first upload to fail")
+	}
+	if fake.aborted != 0 || len(fake.uploads) != 1 {
+		t.Fatalf("expected the failed upload to be kept, aborted %d, uploads %d", fake.aborted, len(fake.uploads))
+	}
+
+	fake.failPart = 0
+	fake.sent = nil
+	if err := u.Upload(ctx, "files/blob", bytes.NewReader(body), &pd); err != nil {
+		t.Fatal(err)
+	}
+	if len(fake.sent) != 1 || fake.sent[0] != 3 || fake.completed != 1 || len(fake.uploads) != 1 {
+		t.Errorf("expected only part 3 to be sent to the same upload, sent %v, completed %d, uploads %d", fake.sent, fake.completed, len(fake.uploads))
+	}
+
+	// Once complete, the state is forgotten and the next upload starts afresh.
+	fake.sent = nil
+	if err := u.Upload(ctx, "files/blob", bytes.NewReader(body), &pd); err != nil {
+		t.Fatal(err)
+	}
+	if len(fake.sent) != 3 || len(fake.uploads) != 2 {
+		t.Errorf("expected a new upload of every part, sent %v, uploads %d", fake.sent, len(fake.uploads))
+	}
+
+	// Parts whose content no longer matches are sent again.
+	fake.failPart = 2
+	fake.sent = nil
+	if err := u.Upload(ctx, "files/other", bytes.NewReader(body), &pd); err == nil {
+		t.Fatal("expected the upload to fail")
+	}
+	changed := bytes.Repeat([]byte("abcdefghij"), 3)
+	maker.Reset(10)
+	if _, err := maker.Write(changed); err != nil {
+		t.Fatal(err)
+	}
+	changedPD := maker.Finish()
+	fake.failPart = 0
+	fake.sent = nil
+	if err := u.Upload(ctx, "files/other", bytes.NewReader(changed), &changedPD); err != nil {
+		t.Fatal(err)
+	}
+	if len(fake.sent) != 3 {
+		t.Errorf("expected every part to be sent again, sent %v", fake.sent)
+	}
+}
+
+func TestResumeExtendsRetention(t *testing.T) {
+	storage, err := cache.Open(filepath.Join(t.TempDir(), "cache.db"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer storage.Close()
+
+	body := bytes.Repeat([]byte("0123456789"), 3)
+	var maker parts.PartDigestsMaker
+	maker.Reset(10)
+	if _, err := maker.Write(body); err != nil {
+		t.Fatal(err)
+	}
+	pd := maker.Finish()
+
+	fake := &fakeS3{uploads: make(map[string]map[int64]fakePart), failPart: 3}
+	lock := ObjectLock{Mode: s3.ObjectLockModeGovernance, Retention: 30 * 24 * time.Hour}
+	u := &SafeUploader{S3: fake, Bucket: "bucket", ObjectLock: lock, Concurrency: 1, ResumeCache: storage.Cache("multipart_uploads")}
+	ctx := context.Background()
+
+	if err := u.Upload(ctx, "files/blob", bytes.NewReader(body), &pd); err == nil {
+		t.Fatal("expected the first upload to fail")
+	}
+	// The upload is resumed days later, when objects are to be retained for longer.
+	fake.retainUntil = fake.retainUntil.Add(-3 * 24 * time.Hour)
+	fake.failPart = 0
+	if err := u.Upload(ctx, "files/blob", bytes.NewReader(body), &pd); err != nil {
+		t.Fatal(err)
+	}
+	if fake.completed != 1 || fake.retentions != 1 {
+		t.Fatalf("expected the resumed upload to have its retention extended, completed %d, retentions %d", fake.completed, fake.retentions)
+	}
+	if expected := lock.RetainUntil(time.Now()); !fake.retainUntil.Equal(expected) || fake.retainMode != lock.Mode {
+		t.Errorf("expected retention until %s in %s, got %s in %s", expected, lock.Mode, fake.retainUntil, fake.retainMode)
+	}
+}
@@ -18,36 +18,50 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"os"
 	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
-	"github.com/retailnext/cassandrabackup/digest"
-	"github.com/retailnext/cassandrabackup/paranoid"
+	"github.com/retailnext/cassandrabackup/cache"
+	"github.com/retailnext/cassandrabackup/digest/parts"
+	"github.com/retailnext/cassandrabackup/throttle"
 	"go.uber.org/zap"
 )
 
 type SafeUploader struct {
-	S3                   s3iface.S3API
-	Bucket               string
-	ServerSideEncryption *string
-	StorageClass         *string
+	S3           s3iface.S3API
+	Bucket       string
+	Encryption   Encryption
+	ObjectLock   ObjectLock
+	StorageClass *string
+	// Concurrency is the number of parts to upload at once; 4 if not set.
+	Concurrency int
+	// ResumeCache, if set, remembers multipart uploads in progress so that they are resumed rather
+	// than aborted when an upload fails or the process is interrupted.
+	ResumeCache *cache.Cache
 }
 
-func (u *SafeUploader) UploadFile(ctx context.Context, key string, file paranoid.File, digests digest.ForUpload) error {
+const defaultConcurrency = 4
+
+// Upload sends body to key, as a multipart upload if partDigests has more than one part.
+// Every request carries the MD5 and SHA256 of its part so S3 rejects anything that does not match.
+func (u *SafeUploader) Upload(ctx context.Context, key string, body io.ReaderAt, partDigests *parts.PartDigests) error {
 	upl := fileUploader{
 		s3Svc: u.S3,
 
-		bucket:               u.Bucket,
-		key:                  key,
-		serverSideEncryption: u.ServerSideEncryption,
-		storageClass:         u.StorageClass,
+		bucket:       u.Bucket,
+		key:          key,
+		encryption:   u.Encryption,
+		objectLock:   u.ObjectLock,
+		storageClass: u.StorageClass,
+		concurrency:  u.Concurrency,
+		resumeCache:  u.ResumeCache,
 
-		file:    file,
-		digests: digests,
+		body:        throttle.ReaderAt(ctx, body, throttle.Upload),
+		partDigests: partDigests,
 
 		errors: make(map[int64]error),
 		etags:  make(map[int64]string),
@@ -58,14 +72,16 @@ func (u *SafeUploader) UploadFile(ctx context.Context, key string, file paranoid
 type fileUploader struct {
 	s3Svc s3iface.S3API
 
-	bucket               string
-	key                  string
-	serverSideEncryption *string
-	storageClass         *string
+	bucket       string
+	key          string
+	encryption   Encryption
+	objectLock   ObjectLock
+	storageClass *string
+	concurrency  int
+	resumeCache  *cache.Cache
 
-	file    paranoid.File
-	osFile  *os.File
-	digests digest.ForUpload
+	body        io.ReaderAt
+	partDigests *parts.PartDigests
 
 	ctx       context.Context
 	ctxCancel context.CancelFunc
@@ -77,54 +93,61 @@ type fileUploader struct {
 	errors   map[int64]error
 	etags    map[int64]string
 	uploadId string
+	state    uploadState
 }
 
 func (u *fileUploader) Upload(ctx context.Context) error {
-	pd := u.digests.PartDigests()
-	if osFile, err := u.file.Open(); err != nil {
-		return err
-	} else {
-		u.osFile = osFile
-	}
-	defer func() {
-		if closeErr := u.osFile.Close(); closeErr != nil {
-			panic(closeErr)
-		}
-	}()
-
+	pd := u.partDigests
 	if pd.Parts() == 1 {
 		return u.uploadSinglePart(ctx)
 	}
 
 	createMultipartUploadInput := s3.CreateMultipartUploadInput{
-		Bucket:               &u.bucket,
-		Key:                  &u.key,
-		ServerSideEncryption: u.serverSideEncryption,
-		StorageClass:         u.storageClass,
+		Bucket:       &u.bucket,
+		Key:          &u.key,
+		StorageClass: u.storageClass,
 	}
+	u.encryption.applyCreateMultipartUpload(&createMultipartUploadInput)
+	u.objectLock.applyCreateMultipartUpload(&createMultipartUploadInput, time.Now())
 	u.ctx, u.ctxCancel = context.WithCancel(ctx)
 
 	var err error
-	var createMultipartUploadOutput *s3.CreateMultipartUploadOutput
-	createMultipartUploadOutput, err = u.s3Svc.CreateMultipartUploadWithContext(u.ctx, &createMultipartUploadInput)
-	if err != nil {
-		return err
+	resumed := u.resume(u.ctx)
+	if !resumed {
+		var createMultipartUploadOutput *s3.CreateMultipartUploadOutput
+		createMultipartUploadOutput, err = u.s3Svc.CreateMultipartUploadWithContext(u.ctx, &createMultipartUploadInput)
+		if err != nil {
+			return err
+		}
+		u.uploadId = *createMultipartUploadOutput.UploadId
+		u.state = uploadState{UploadID: u.uploadId}
+		u.saveState()
 	}
-	u.uploadId = *createMultipartUploadOutput.UploadId
 	defer func() {
-		if err != nil {
+		if err == nil {
+			u.deleteState()
+		} else if u.resumeCache == nil {
 			u.abort()
+		} else {
+			zap.S().Infow("keep_multipart_upload", "key", u.key, "upload_id", u.uploadId, "err", err)
 		}
 	}()
 
-	u.limiter = make(chan struct{}, 4)
+	if u.concurrency <= 0 {
+		u.concurrency = defaultConcurrency
+	}
+	u.limiter = make(chan struct{}, u.concurrency)
 	u.errors = make(map[int64]error)
 	doneCh := u.ctx.Done()
 	var partNumber int64
+schedule:
 	for partNumber = 1; partNumber <= pd.Parts(); partNumber++ {
+		if _, done := u.etags[partNumber]; done {
+			continue
+		}
 		select {
 		case <-doneCh:
-			break
+			break schedule
 		case u.limiter <- struct{}{}:
 			u.wg.Add(1)
 			go u.uploadPart(partNumber)
@@ -133,6 +156,9 @@ func (u *fileUploader) Upload(ctx context.Context) error {
 	u.wg.Wait()
 
 	err = u.tryToComplete()
+	if err == nil && resumed {
+		err = u.extendRetention(u.ctx, time.Now())
+	}
 	return err
 }
 
@@ -142,7 +168,7 @@ func (u *fileUploader) tryToComplete() error {
 
 	var parts []*s3.CompletedPart
 	var partNumber int64
-	for partNumber = 1; partNumber <= u.digests.PartDigests().Parts(); partNumber++ {
+	for partNumber = 1; partNumber <= u.partDigests.Parts(); partNumber++ {
 		etag, etagOk := u.etags[partNumber]
 		if !etagOk {
 			if len(u.errors) == 0 {
@@ -204,10 +230,10 @@ func (u *fileUploader) uploadPart(partNumber int64) {
 		u.wg.Done()
 	}()
 
-	pd := u.digests.PartDigests()
+	pd := u.partDigests
 	offset := pd.PartOffset(partNumber)
 	length := pd.PartLength(partNumber)
-	reader := io.NewSectionReader(u.osFile, offset, length)
+	reader := io.NewSectionReader(u.body, offset, length)
 
 	uploadPartInput := &s3.UploadPartInput{
 		Bucket:        &u.bucket,
@@ -217,6 +243,7 @@ func (u *fileUploader) uploadPart(partNumber int64) {
 		ContentLength: &length,
 		Body:          reader,
 	}
+	u.encryption.applyUploadPart(uploadPartInput)
 	var uploadPartOutput *s3.UploadPartOutput
 	uploadPartOutput, err = u.s3Svc.UploadPartWithContext(u.ctx, uploadPartInput, func(request *request.Request) {
 		request.HTTPRequest.Header.Set(md5Header, pd.PartContentMD5(partNumber))
@@ -228,19 +255,26 @@ func (u *fileUploader) uploadPart(partNumber int64) {
 
 	u.lock.Lock()
 	u.etags[partNumber] = *uploadPartOutput.ETag
+	u.state.Parts = append(u.state.Parts, completedPart{
+		PartNumber: partNumber,
+		ETag:       *uploadPartOutput.ETag,
+		SHA256:     pd.PartContentSHA256(partNumber),
+	})
+	u.saveState()
 	u.lock.Unlock()
 }
 
 func (u *fileUploader) uploadSinglePart(ctx context.Context) error {
-	pd := u.digests.PartDigests()
+	pd := u.partDigests
 	putObjectInput := s3.PutObjectInput{
-		Bucket:               &u.bucket,
-		Key:                  &u.key,
-		ContentLength:        aws.Int64(pd.PartLength(1)),
-		ServerSideEncryption: u.serverSideEncryption,
-		StorageClass:         u.storageClass,
-		Body:                 u.osFile,
+		Bucket:        &u.bucket,
+		Key:           &u.key,
+		ContentLength: aws.Int64(pd.PartLength(1)),
+		StorageClass:  u.storageClass,
+		Body:          io.NewSectionReader(u.body, 0, pd.PartLength(1)),
 	}
+	u.encryption.ApplyPutObject(&putObjectInput)
+	u.objectLock.ApplyPutObject(&putObjectInput, time.Now())
 	_, err := u.s3Svc.PutObjectWithContext(ctx, &putObjectInput, func(i *request.Request) {
 		i.HTTPRequest.Header.Set(md5Header, pd.PartContentMD5(1))
 		i.HTTPRequest.Header.Set(sha256Header, pd.PartContentSHA256(1))
@@ -178,6 +178,23 @@ func (c *Cache) Put(key, value []byte) error {
 	return c.put(key, value)
 }
 
+// Delete removes the key from the current and previous buckets.
+func (c *Cache) Delete(key []byte) error {
+	return c.storage.db.Update(func(tx *bbolt.Tx) error {
+		currentTop, previousTop := c.storage.currentAndPreviousTopBuckets()
+		for _, top := range [][]byte{currentTop, previousTop} {
+			if topBucket := tx.Bucket(top); topBucket != nil {
+				if bucket := topBucket.Bucket(c.name); bucket != nil {
+					if err := bucket.Delete(key); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	})
+}
+
 func (c *Cache) put(key, value []byte) error {
 	lgr := zap.S()
 	return c.storage.db.Update(func(tx *bbolt.Tx) error {
@@ -26,10 +26,16 @@ import (
 	"github.com/retailnext/cassandrabackup/backup"
 	"github.com/retailnext/cassandrabackup/bucket"
 	"github.com/retailnext/cassandrabackup/cache"
-	"github.com/retailnext/cassandrabackup/manifests"
+	"github.com/retailnext/cassandrabackup/inspect"
+	"github.com/retailnext/cassandrabackup/list"
 	"github.com/retailnext/cassandrabackup/periodic"
+	"github.com/retailnext/cassandrabackup/prune"
+	"github.com/retailnext/cassandrabackup/replicate"
 	"github.com/retailnext/cassandrabackup/restore"
-	"github.com/retailnext/cassandrabackup/unixtime"
+	"github.com/retailnext/cassandrabackup/retention"
+	"github.com/retailnext/cassandrabackup/throttle"
+	"github.com/retailnext/cassandrabackup/tier"
+	"github.com/retailnext/cassandrabackup/verify"
 	"go.uber.org/zap"
 	"golang.org/x/term"
 )
@@ -108,16 +114,14 @@ var (
 	metricsListenAddress = kingpin.Flag("web.listen-address", "Address on which to expose metrics.").String()
 	metricsPath          = kingpin.Flag("web.telemetry-path", "Path under which to expose metrics.").Default("/metrics").String()
 
-	listCmd = kingpin.Command("list", "")
+	keysCmd             = kingpin.Command("keys", "")
+	keysRotateCmd       = keysCmd.Command("rotate", "Re-wrap all data keys with the active master key, without re-uploading data")
+	keysRotateCmdDryRun = keysRotateCmd.Flag("dry-run", "Only count the data keys that would be re-wrapped").Bool()
 
-	listManifestsCmd          = listCmd.Command("manifests", "List manifests for a host")
-	listManifestsCmdCluster   = listManifestsCmd.Flag("cluster", "Cluster name to restore from").Required().String()
-	listManifestsCmdHostname  = listManifestsCmd.Flag("hostname", "Hostname to restore from").Required().String()
-	listManifestsCmdNotBefore = listManifestsCmd.Flag("not-before", "Ignore manifests before this time (unix seconds)").Int64()
-	listManifestsCmdNotAfter  = listManifestsCmd.Flag("not-after", "Ignore manifests after this time (unix seconds)").Int64()
-
-	listHostsCmd        = listCmd.Command("hosts", "List hosts in a cluster")
-	listHostsCmdCluster = listHostsCmd.Flag("cluster", "Cluster name").Required().String()
+	uploadsCmd               = kingpin.Command("uploads", "")
+	uploadsAbortCmd          = uploadsCmd.Command("abort-stale", "Abort multipart uploads left behind by backups that never finished them")
+	uploadsAbortCmdOlderThan = uploadsAbortCmd.Flag("older-than", "Abort uploads started longer ago than this").Default("168h").Duration()
+	uploadsAbortCmdDryRun    = uploadsAbortCmd.Flag("dry-run", "Only list the uploads that would be aborted").Bool()
 )
 
 func main() {
@@ -135,6 +139,7 @@ func main() {
 	defer stopProfile()
 
 	setupPrometheus()
+	throttle.Configure()
 
 	defer func() {
 		if cache.Shared != nil {
@@ -162,7 +167,7 @@ func main() {
 			lgr.Fatalw("backup_error", "err", err)
 		}
 	case "backup run":
-		err := periodic.Main(ctx)
+		err := periodic.Main(ctx, *metricsListenAddress, *metricsPath)
 		if err == context.Canceled {
 			return
 		}
@@ -185,29 +190,119 @@ func main() {
 		if err != nil {
 			lgr.Fatalw("restore_error", "err", err)
 		}
+	case "restore load":
+		err := restore.RestoreLoad(ctx)
+		if err == context.Canceled {
+			return
+		}
+		if err != nil {
+			lgr.Fatalw("restore_error", "err", err)
+		}
+	case "restore schema":
+		err := restore.RestoreSchema(ctx)
+		if err == context.Canceled {
+			return
+		}
+		if err != nil {
+			lgr.Fatalw("restore_error", "err", err)
+		}
+	case "prune":
+		err := prune.Main(ctx)
+		if err == context.Canceled {
+			return
+		}
+		if err != nil {
+			lgr.Fatalw("prune_error", "err", err)
+		}
+	case "verify":
+		err := verify.Main(ctx)
+		if err == context.Canceled {
+			return
+		}
+		if err != nil {
+			lgr.Fatalw("verify_error", "err", err)
+		}
+	case "tier":
+		err := tier.Main(ctx)
+		if err == context.Canceled {
+			return
+		}
+		if err != nil {
+			lgr.Fatalw("tier_error", "err", err)
+		}
+	case "copy":
+		err := replicate.Main(ctx)
+		if err == context.Canceled {
+			return
+		}
+		if err != nil {
+			lgr.Fatalw("copy_error", "err", err)
+		}
+	case "retention extend":
+		err := retention.Main(ctx)
+		if err == context.Canceled {
+			return
+		}
+		if err != nil {
+			lgr.Fatalw("retention_extend_error", "err", err)
+		}
+	case "manifest show":
+		err := inspect.Show(ctx)
+		if err == context.Canceled {
+			return
+		}
+		if err != nil {
+			lgr.Fatalw("manifest_show_error", "err", err)
+		}
+	case "manifest diff":
+		err := inspect.Diff(ctx)
+		if err == context.Canceled {
+			return
+		}
+		if err != nil {
+			lgr.Fatalw("manifest_diff_error", "err", err)
+		}
 	case "list manifests":
-		lgr := zap.S()
-		identity := manifests.NodeIdentity{
-			Cluster:  *listManifestsCmdCluster,
-			Hostname: *listManifestsCmdHostname,
+		err := list.Manifests(ctx)
+		if err == context.Canceled {
+			return
 		}
-		bkt := bucket.OpenShared()
-		manifestKeys, err := bkt.ListManifests(ctx, identity, unixtime.Seconds(*listManifestsCmdNotBefore), unixtime.Seconds(*listManifestsCmdNotAfter))
 		if err != nil {
 			lgr.Fatalw("list_manifests_error", "err", err)
 		}
-		for _, mk := range manifestKeys {
-			lgr.Infow("got_manifest", "manifest", mk)
-		}
 	case "list hosts":
-		lgr := zap.S()
-		bkt := bucket.OpenShared()
-		results, err := bkt.ListHostNames(ctx, *listHostsCmdCluster)
+		err := list.Hosts(ctx)
+		if err == context.Canceled {
+			return
+		}
 		if err != nil {
 			lgr.Fatalw("list_hosts_error", "err", err)
 		}
-		for _, ni := range results {
-			lgr.Infow("got_host", "identity", ni)
+	case "list clusters":
+		err := list.Clusters(ctx)
+		if err == context.Canceled {
+			return
+		}
+		if err != nil {
+			lgr.Fatalw("list_clusters_error", "err", err)
+		}
+	case "keys rotate":
+		result, err := bucket.RewrapKeys(ctx, bucket.OpenShared(), *keysRotateCmdDryRun)
+		if err == context.Canceled {
+			return
+		}
+		lgr.Infow("rewrap_keys_result", "dry_run", *keysRotateCmdDryRun, "result", result)
+		if err != nil {
+			lgr.Fatalw("rewrap_keys_error", "err", err)
+		}
+	case "uploads abort-stale":
+		result, err := bucket.AbortStaleUploads(ctx, bucket.OpenShared(), *uploadsAbortCmdOlderThan, *uploadsAbortCmdDryRun)
+		if err == context.Canceled {
+			return
+		}
+		lgr.Infow("abort_stale_uploads_result", "dry_run", *uploadsAbortCmdDryRun, "result", result)
+		if err != nil {
+			lgr.Fatalw("abort_stale_uploads_error", "err", err)
 		}
 	default:
 		lgr.Fatalw("unhandled_command", "cmd", cmd)
@@ -0,0 +1,121 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package compression implements the framing used for compressed blobs.
+//
+// A compressed stream is a fixed header naming the codec followed by the codec's output. Blobs that
+// are not worth compressing are stored as-is without a header, so existing blobs stay valid.
+// Compression is deterministic: the same file and codec always produce the same bytes, which lets
+// the digest cache record part digests of the compressed stream once and reuse them at upload time.
+package compression
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+type Codec byte
+
+const (
+	None Codec = 0
+	Zstd Codec = 1
+)
+
+func (c Codec) String() string {
+	switch c {
+	case None:
+		return "none"
+	case Zstd:
+		return "zstd"
+	default:
+		return fmt.Sprintf("codec(%d)", byte(c))
+	}
+}
+
+func ParseCodec(name string) (Codec, error) {
+	switch name {
+	case "none", "":
+		return None, nil
+	case "zstd":
+		return Zstd, nil
+	default:
+		return None, fmt.Errorf("unknown compression codec %q", name)
+	}
+}
+
+const (
+	magic     = "CBCOMP\x00\x01"
+	magicSize = 8
+
+	// HeaderSize is the length of the header that starts every compressed stream.
+	HeaderSize = magicSize + 1
+)
+
+var ErrMalformed = errors.New("malformed compressed stream")
+
+// StreamCodec returns the codec of the compressed stream starting with header, or None when header
+// (the first HeaderSize or more bytes of an object) does not start a compressed stream.
+func StreamCodec(header []byte) Codec {
+	if len(header) < HeaderSize || string(header[:magicSize]) != magic {
+		return None
+	}
+	return Codec(header[magicSize])
+}
+
+// NewWriter returns a writer that compresses to w. The caller must Close it to flush the stream;
+// closing does not close w.
+func NewWriter(w io.Writer, codec Codec) (io.WriteCloser, error) {
+	if codec != Zstd {
+		return nil, fmt.Errorf("cannot compress with %v", codec)
+	}
+	// A single encoder goroutine keeps the output independent of the number of CPUs.
+	encoder, err := zstd.NewWriter(w, zstd.WithEncoderConcurrency(1), zstd.WithEncoderLevel(zstd.SpeedDefault))
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, HeaderSize)
+	copy(header, magic)
+	header[magicSize] = byte(codec)
+	if _, err := w.Write(header); err != nil {
+		encoder.Close()
+		return nil, err
+	}
+	return encoder, nil
+}
+
+// NewReader returns a reader that decompresses the compressed stream read from r.
+func NewReader(r io.Reader) (io.ReadCloser, error) {
+	header := make([]byte, HeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, ErrMalformed
+		}
+		return nil, err
+	}
+	switch StreamCodec(header) {
+	case Zstd:
+		decoder, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		return decoder.IOReadCloser(), nil
+	case None:
+		return nil, ErrMalformed
+	default:
+		return nil, fmt.Errorf("unsupported compression codec %v", StreamCodec(header))
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compression
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func compress(t *testing.T, data []byte) []byte {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, Zstd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestRoundTrip(t *testing.T) {
+	data := bytes.Repeat([]byte("partition key,clustering,value\n"), 10000)
+	compressed := compress(t, data)
+	if StreamCodec(compressed) != Zstd || len(compressed) >= len(data)/10 {
+		t.Fatalf("unexpected compressed stream of %d bytes", len(compressed))
+	}
+	if !bytes.Equal(compressed, compress(t, data)) {
+		t.Fatal("compression is not deterministic")
+	}
+
+	r, err := NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	decompressed, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decompressed, data) {
+		t.Fatal("round trip mismatch")
+	}
+
+	if StreamCodec(data) != None {
+		t.Fatal("plain data detected as compressed")
+	}
+	if _, err := NewReader(bytes.NewReader(compressed[:4])); err != ErrMalformed {
+		t.Fatalf("expected ErrMalformed got %v", err)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"nb-1-big-CompressionInfo.db", "nb-1-big-Data.db", "nb-2-big-Data.db"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	components := []string{"Data.db", "Index.db"}
+	cases := []struct {
+		name     string
+		size     int64
+		expected Codec
+	}{
+		{"nb-1-big-Data.db", 1 << 20, None},
+		{"nb-2-big-Data.db", 1 << 20, Zstd},
+		{"nb-2-big-Index.db", 1 << 20, Zstd},
+		{"nb-2-big-Index.db", 100, None},
+		{"nb-2-big-Filter.db", 1 << 20, None},
+		{"manifest.json", 1 << 20, None},
+	}
+	for _, c := range cases {
+		if codec := Select(Zstd, components, filepath.Join(dir, c.name), c.size); codec != c.expected {
+			t.Errorf("%s (%d bytes): expected %v got %v", c.name, c.size, c.expected, codec)
+		}
+	}
+	if codec := Select(None, components, filepath.Join(dir, "nb-2-big-Data.db"), 1<<20); codec != None {
+		t.Errorf("expected none got %v", codec)
+	}
+}
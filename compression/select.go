@@ -0,0 +1,72 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compression
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/alecthomas/kingpin/v2"
+)
+
+var (
+	codecName  = kingpin.Flag("compression", "Compress uploaded blobs with this codec.").Default("none").Enum("none", "zstd")
+	components = kingpin.Flag("compression-components", "SSTable components to compress. Data.db is only compressed for tables with Cassandra compression disabled.").Default(
+		"Data.db", "Index.db", "Summary.db", "Statistics.db", "Partitions.db", "Rows.db",
+	).Strings()
+)
+
+const (
+	dataComponent            = "Data.db"
+	compressionInfoComponent = "CompressionInfo.db"
+
+	// Below this size the header and frame overhead outweigh any saving.
+	minSize = 4096
+)
+
+// ForFile returns the codec that the named file should be uploaded with.
+func ForFile(name string, size int64) Codec {
+	codec, err := ParseCodec(*codecName)
+	if err != nil {
+		panic(err)
+	}
+	return Select(codec, *components, name, size)
+}
+
+// Select picks codec for name when it is one of components and is worth compressing.
+// Data files of tables that Cassandra already compresses have a CompressionInfo.db beside them
+// and are always left alone.
+func Select(codec Codec, components []string, name string, size int64) Codec {
+	if codec == None || size < minSize {
+		return None
+	}
+	base := filepath.Base(name)
+	dash := strings.LastIndexByte(base, '-')
+	if dash < 0 {
+		return None
+	}
+	prefix, component := base[:dash+1], base[dash+1:]
+	if !slices.Contains(components, component) {
+		return None
+	}
+	if component == dataComponent {
+		if _, err := os.Lstat(filepath.Join(filepath.Dir(name), prefix+compressionInfoComponent)); !os.IsNotExist(err) {
+			return None
+		}
+	}
+	return codec
+}
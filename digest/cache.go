@@ -20,19 +20,23 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/retailnext/cassandrabackup/cache"
+	"github.com/retailnext/cassandrabackup/compression"
 	"github.com/retailnext/cassandrabackup/paranoid"
 )
 
 type Cache struct {
 	c *cache.Cache
 	f ForUploadFactory
+	// codecFor picks the compression for a file; nil means no compression.
+	codecFor func(name string, size int64) compression.Codec
 }
 
 func OpenShared() *Cache {
 	cache.OpenShared()
 	return &Cache{
-		c: cache.Shared.Cache(cacheName),
-		f: &awsForUploadFactory{},
+		c:        cache.Shared.Cache(cacheName),
+		f:        &awsForUploadFactory{},
+		codecFor: compression.ForFile,
 	}
 }
 
@@ -42,14 +46,24 @@ func (c *Cache) CreateForUpload() ForUpload {
 	return c.f.CreateForUpload()
 }
 
+// Forget removes the cached digests of file, so that the next Get computes them again.
+func (c *Cache) Forget(file paranoid.File) error {
+	return c.c.Delete(file.CacheKey())
+}
+
 func (c *Cache) Get(ctx context.Context, file paranoid.File) (ForUpload, error) {
 	key := file.CacheKey()
+	codec := compression.None
+	if c.codecFor != nil {
+		codec = c.codecFor(file.Name(), file.Len())
+	}
 	var result ForUpload
 
 	getErr := c.c.Get(key, func(wrapped []byte) error {
 		if unwrapped := file.UnwrapCacheEntry(key, wrapped); unwrapped != nil {
 			maybeResult := c.CreateForUpload()
-			if err := maybeResult.UnmarshalBinary(unwrapped); err == nil {
+			// Entries for a different codec describe a different stored blob.
+			if err := maybeResult.UnmarshalBinary(unwrapped); err == nil && maybeResult.Codec() == codec {
 				result = maybeResult
 				return nil
 			} else {
@@ -72,7 +86,7 @@ func (c *Cache) Get(ctx context.Context, file paranoid.File) (ForUpload, error)
 
 	t0 := time.Now()
 	result = c.CreateForUpload()
-	if populateErr := result.populate(ctx, file); populateErr != nil {
+	if populateErr := result.populate(ctx, file, codec); populateErr != nil {
 		return nil, populateErr
 	}
 	missFilesTotal.Inc()
@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"github.com/retailnext/cassandrabackup/cache"
+	"github.com/retailnext/cassandrabackup/compression"
 	"github.com/retailnext/cassandrabackup/paranoid"
 )
 
@@ -113,3 +114,42 @@ func TestCacheAWS(t *testing.T) {
 		t.Fatalf("restore entry mismatch %+v %+v", entry1, entry2)
 	}
 }
+
+func TestCacheCodecChange(t *testing.T) {
+	dir := t.TempDir()
+	storage, err := cache.Open(filepath.Join(dir, "cache.db"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if closeErr := storage.Close(); closeErr != nil {
+			panic(closeErr)
+		}
+	}()
+	testFilePath := filepath.Join(dir, "nb-1-big-Index.db")
+	if err := os.WriteFile(testFilePath, make([]byte, 1024*1024), 0o644); err != nil {
+		panic(err)
+	}
+	safeFile, err := paranoid.NewFile(testFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	codec := compression.None
+	c := &Cache{
+		c: storage.Cache(cacheName),
+		f: &awsForUploadFactory{},
+		codecFor: func(string, int64) compression.Codec {
+			return codec
+		},
+	}
+	for _, codec = range []compression.Codec{compression.None, compression.Zstd, compression.Zstd, compression.None} {
+		entry, err := c.Get(context.Background(), safeFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if entry.Codec() != codec {
+			t.Fatalf("expected %v entry got %v", codec, entry.Codec())
+		}
+	}
+}
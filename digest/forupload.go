@@ -21,8 +21,10 @@ import (
 	"hash"
 	"io"
 
+	"github.com/retailnext/cassandrabackup/compression"
 	"github.com/retailnext/cassandrabackup/digest/parts"
 	"github.com/retailnext/cassandrabackup/paranoid"
+	"github.com/retailnext/cassandrabackup/throttle"
 	"golang.org/x/crypto/blake2b"
 )
 
@@ -39,16 +41,20 @@ func (f *awsForUploadFactory) CreateForUpload() ForUpload {
 type ForUpload interface {
 	UnmarshalBinary(data []byte) error
 	MarshalBinary() ([]byte, error)
-	populate(ctx context.Context, file paranoid.File) error
+	populate(ctx context.Context, file paranoid.File, codec compression.Codec) error
 	onWrite(buf []byte, len int)
+	// PartDigests describes the blob as stored: the compressed stream when Codec is not None.
 	PartDigests() *parts.PartDigests
 	ForRestore() ForRestore
+	Codec() compression.Codec
 }
 
 type awsForUpload struct {
 	blake2b          blake2bDigest
 	partDigestsMaker parts.PartDigestsMaker
 	partDigests      parts.PartDigests
+	codec            compression.Codec
+	compressor       io.WriteCloser
 }
 
 func (u *awsForUpload) URLSafe() string {
@@ -59,8 +65,16 @@ func (u *awsForUpload) PartDigests() *parts.PartDigests {
 	return &u.partDigests
 }
 
+func (u *awsForUpload) Codec() compression.Codec {
+	return u.codec
+}
+
 func (u *awsForUpload) onWrite(buf []byte, len int) {
-	if n, err := u.partDigestsMaker.Write(buf[0:len]); err != nil {
+	var w io.Writer = &u.partDigestsMaker
+	if u.compressor != nil {
+		w = u.compressor
+	}
+	if n, err := w.Write(buf[0:len]); err != nil {
 		panic(err)
 	} else if n != len {
 		panic(io.ErrShortWrite)
@@ -94,12 +108,13 @@ func makeHash(ctx context.Context, file paranoid.File, u ForUpload) (hash.Hash,
 		panic(err)
 	}
 
+	r := throttle.Reader(ctx, osFile, throttle.DiskRead)
 	buf := make([]byte, 32*1024)
 	var doneCh <-chan struct{}
 	var lastCheckedDoneCh int64
 	var size int64
 	for {
-		bytesRead, err := osFile.Read(buf)
+		bytesRead, err := r.Read(buf)
 		if err != nil && err != io.EOF {
 			return nil, err
 		}
@@ -134,10 +149,26 @@ func makeHash(ctx context.Context, file paranoid.File, u ForUpload) (hash.Hash,
 	return blake2b512Hash, err
 }
 
-func (u *awsForUpload) populate(ctx context.Context, file paranoid.File) error {
+func (u *awsForUpload) populate(ctx context.Context, file paranoid.File, codec compression.Codec) error {
 	u.partDigestsMaker.Reset(partSize)
+	u.codec = codec
+	if codec != compression.None {
+		compressor, err := compression.NewWriter(&u.partDigestsMaker, codec)
+		if err != nil {
+			return err
+		}
+		u.compressor = compressor
+		defer func() {
+			u.compressor = nil
+		}()
+	}
 
 	blake2b512Hash, err := makeHash(ctx, file, u)
+	if u.compressor != nil {
+		if closeErr := u.compressor.Close(); err == nil {
+			err = closeErr
+		}
+	}
 	if err != nil {
 		return err
 	}
@@ -152,13 +183,22 @@ func (u *awsForUpload) MarshalBinary() ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	result := make([]byte, 64+len(partDigests))
+	// The codec is appended only for compressed blobs so that entries cached before compression
+	// existed still decode.
+	size := 64 + len(partDigests)
+	if u.codec != compression.None {
+		size++
+	}
+	result := make([]byte, size)
 	if copy(result[0:], u.blake2b[:]) != 64 {
 		panic("bad copy")
 	}
 	if copy(result[64:], partDigests) != len(partDigests) {
 		panic("bad copy")
 	}
+	if u.codec != compression.None {
+		result[size-1] = byte(u.codec)
+	}
 	return result, nil
 }
 
@@ -169,5 +209,17 @@ func (u *awsForUpload) UnmarshalBinary(data []byte) error {
 	if copy(u.blake2b[:], data) != 64 {
 		panic("bad copy")
 	}
-	return u.partDigests.UnmarshalBinary(data[64:])
+	n, err := u.partDigests.UnmarshalBinaryPrefix(data[64:])
+	if err != nil {
+		return err
+	}
+	switch rest := data[64+n:]; len(rest) {
+	case 0:
+		u.codec = compression.None
+	case 1:
+		u.codec = compression.Codec(rest[0])
+	default:
+		return fmt.Errorf("invalid data")
+	}
+	return nil
 }
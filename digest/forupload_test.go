@@ -0,0 +1,71 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package digest
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/retailnext/cassandrabackup/compression"
+	"github.com/retailnext/cassandrabackup/paranoid"
+)
+
+func TestForUploadCompressed(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "nb-1-big-Index.db")
+	data := bytes.Repeat([]byte("0123456789abcdef"), 64*1024)
+	if err := os.WriteFile(name, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	file, err := paranoid.NewFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	plain, err := GetUncached(ctx, file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compressed, err := GetUncachedWithCodec(ctx, file, compression.Zstd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain.ForRestore() != compressed.ForRestore() {
+		t.Fatal("compression changed the blob identity")
+	}
+	if plain.PartDigests().TotalLength() != int64(len(data)) || compressed.PartDigests().TotalLength() >= int64(len(data)) {
+		t.Fatalf("unexpected lengths %d %d", plain.PartDigests().TotalLength(), compressed.PartDigests().TotalLength())
+	}
+
+	for _, u := range []ForUpload{plain, compressed} {
+		encoded, err := u.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded awsForUpload
+		if err := decoded.UnmarshalBinary(encoded); err != nil {
+			t.Fatal(err)
+		}
+		if decoded.Codec() != u.Codec() || decoded.ForRestore() != u.ForRestore() || decoded.PartDigests().TotalLength() != u.PartDigests().TotalLength() {
+			t.Fatalf("round trip mismatch for %v", u.Codec())
+		}
+		if err := decoded.UnmarshalBinary(append(encoded, 1, 1)); err == nil {
+			t.Fatal("expected error for trailing data")
+		}
+	}
+}
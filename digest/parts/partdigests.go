@@ -31,6 +31,10 @@ func (pd *PartDigests) TotalLength() int64 {
 	return int64(pd.totalLength)
 }
 
+func (pd *PartDigests) PartSize() int64 {
+	return int64(pd.partSize)
+}
+
 func (pd *PartDigests) Parts() int64 {
 	numParts := int64(pd.totalLength) / int64(pd.partSize)
 	if pd.totalLength%pd.partSize > 0 || pd.totalLength == 0 {
@@ -88,19 +92,32 @@ func (pd *PartDigests) MarshalBinary() (data []byte, err error) {
 }
 
 func (pd *PartDigests) UnmarshalBinary(data []byte) error {
+	n, err := pd.UnmarshalBinaryPrefix(data)
+	if err == nil && n != len(data) {
+		err = fmt.Errorf("invalid data")
+	}
+	return err
+}
+
+// UnmarshalBinaryPrefix decodes PartDigests from the start of data and returns the number of bytes used.
+func (pd *PartDigests) UnmarshalBinaryPrefix(data []byte) (int, error) {
 	if len(data) < headerLength {
-		return fmt.Errorf("invalid data")
+		return 0, fmt.Errorf("invalid data")
 	}
 	pd.partSize = binary.BigEndian.Uint64(data[partSizeOffset:])
 	pd.totalLength = binary.BigEndian.Uint64(data[totalLengthOffset:])
+	if pd.partSize == 0 {
+		return 0, fmt.Errorf("invalid data")
+	}
 	pd.md5Parts = make(md5PartDigests, pd.Parts())
 	pd.sha256Parts = make(sha256PartDigests, pd.Parts())
-	if len(data) != headerLength+pd.md5Parts.size()+pd.sha256Parts.size() {
-		return fmt.Errorf("invalid data")
+	n := headerLength + pd.md5Parts.size() + pd.sha256Parts.size()
+	if len(data) < n {
+		return 0, fmt.Errorf("invalid data")
 	}
 	pd.md5Parts.unmarshal(data[headerLength:])
 	pd.sha256Parts.unmarshal(data[headerLength+pd.md5Parts.size():])
-	return nil
+	return n, nil
 }
 
 func (pd *PartDigests) MarshalText() ([]byte, error) {
@@ -17,11 +17,16 @@ package digest
 import (
 	"context"
 
+	"github.com/retailnext/cassandrabackup/compression"
 	"github.com/retailnext/cassandrabackup/paranoid"
 )
 
 func GetUncached(ctx context.Context, file paranoid.File) (ForUpload, error) {
+	return GetUncachedWithCodec(ctx, file, compression.None)
+}
+
+func GetUncachedWithCodec(ctx context.Context, file paranoid.File, codec compression.Codec) (ForUpload, error) {
 	result := &awsForUpload{}
-	err := result.populate(ctx, file)
+	err := result.populate(ctx, file, codec)
 	return result, err
 }
@@ -0,0 +1,93 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envelope
+
+import (
+	"context"
+	"encoding/binary"
+)
+
+// A sealed document carries its own wrapped data key ahead of the sealed stream,
+// so it can be read (or re-wrapped) without looking anything else up.
+const documentMagic = "CBSDOC\x00\x01"
+
+func IsSealedDocument(data []byte) bool {
+	return len(data) >= len(documentMagic) && string(data[:len(documentMagic)]) == documentMagic
+}
+
+func (k *Keyring) SealDocument(ctx context.Context, plaintext []byte) ([]byte, error) {
+	key, wrapped, err := k.NewDataKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return assembleDocument(wrapped, Seal(key, plaintext))
+}
+
+func (k *Keyring) OpenDocument(ctx context.Context, data []byte) ([]byte, error) {
+	wrapped, sealed, err := splitDocument(data)
+	if err != nil {
+		return nil, err
+	}
+	key, err := k.Unwrap(ctx, wrapped)
+	if err != nil {
+		return nil, err
+	}
+	return Open(key, sealed)
+}
+
+// RewrapDocument replaces the wrapped key of a sealed document with one wrapped by the
+// active master key. It reports false if the document was already current.
+func (k *Keyring) RewrapDocument(ctx context.Context, data []byte) ([]byte, bool, error) {
+	wrapped, sealed, err := splitDocument(data)
+	if err != nil {
+		return nil, false, err
+	}
+	rewrapped, changed, err := k.Rewrap(ctx, wrapped)
+	if err != nil || !changed {
+		return data, false, err
+	}
+	result, err := assembleDocument(rewrapped, sealed)
+	return result, err == nil, err
+}
+
+func assembleDocument(wrapped WrappedKey, sealed []byte) ([]byte, error) {
+	encodedKey, err := wrapped.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, 0, len(documentMagic)+4+len(encodedKey)+len(sealed))
+	out = append(out, documentMagic...)
+	out = binary.BigEndian.AppendUint32(out, uint32(len(encodedKey)))
+	out = append(out, encodedKey...)
+	out = append(out, sealed...)
+	return out, nil
+}
+
+func splitDocument(data []byte) (WrappedKey, []byte, error) {
+	var wrapped WrappedKey
+	if !IsSealedDocument(data) || len(data) < len(documentMagic)+4 {
+		return wrapped, nil, ErrMalformed
+	}
+	data = data[len(documentMagic):]
+	keyLen := binary.BigEndian.Uint32(data)
+	data = data[4:]
+	if uint64(len(data)) < uint64(keyLen) {
+		return wrapped, nil, ErrMalformed
+	}
+	if err := wrapped.UnmarshalBinary(data[:keyLen]); err != nil {
+		return wrapped, nil, err
+	}
+	return wrapped, data[keyLen:], nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envelope
+
+import (
+	"bufio"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// KeyFile holds master keys read from a local file. Each non-blank line that does not start
+// with # is a key id and a base64 encoded 32 byte key separated by whitespace, for example
+//
+//	2026-10 mG0k2Vr6bUfqg2S3XzSk3mHZ0oTzJgT0vJqL2y3rZqE=
+//
+// To rotate, append a new key and run the key rotation command; old keys must stay in the
+// file until no wrapped keys refer to them.
+type KeyFile struct {
+	keys   map[string]cipher.AEAD
+	active string
+}
+
+// LoadKeyFile reads a key file. New data keys are wrapped with activeID, or with the last key
+// in the file if activeID is empty.
+func LoadKeyFile(name, activeID string) (*KeyFile, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	kf := &KeyFile{
+		keys: make(map[string]cipher.AEAD),
+	}
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s:%d: expected key id and key", name, lineNumber)
+		}
+		key, err := base64.StdEncoding.DecodeString(fields[1])
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("%s:%d: key must be 32 bytes of base64", name, lineNumber)
+		}
+		if _, ok := kf.keys[fields[0]]; ok {
+			return nil, fmt.Errorf("%s:%d: duplicate key id %q", name, lineNumber, fields[0])
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			panic(err)
+		}
+		if kf.keys[fields[0]], err = cipher.NewGCM(block); err != nil {
+			panic(err)
+		}
+		kf.active = fields[0]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if activeID != "" {
+		kf.active = activeID
+	}
+	if _, ok := kf.keys[kf.active]; !ok {
+		return nil, fmt.Errorf("%s: no key with id %q", name, kf.active)
+	}
+	return kf, nil
+}
+
+func (kf *KeyFile) Name() string {
+	return "file"
+}
+
+func (kf *KeyFile) KeyID() string {
+	return kf.active
+}
+
+func (kf *KeyFile) WrapKey(ctx context.Context, key, aad []byte) (string, []byte, error) {
+	aead := kf.keys[kf.active]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		panic(err)
+	}
+	return kf.active, aead.Seal(nonce, nonce, key, kf.additionalData(kf.active, aad)), nil
+}
+
+func (kf *KeyFile) UnwrapKey(ctx context.Context, keyID string, ciphertext, aad []byte) ([]byte, error) {
+	aead, ok := kf.keys[keyID]
+	if !ok {
+		return nil, UnknownKeyError{Provider: kf.Name(), KeyID: keyID}
+	}
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, fmt.Errorf("wrapped key too short")
+	}
+	nonce := ciphertext[:aead.NonceSize()]
+	return aead.Open(nil, nonce, ciphertext[aead.NonceSize():], kf.additionalData(keyID, aad))
+}
+
+func (kf *KeyFile) additionalData(keyID string, aad []byte) []byte {
+	return append([]byte(keyID+"\x00"), aad...)
+}
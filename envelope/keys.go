@@ -0,0 +1,209 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envelope
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+)
+
+const (
+	dataKeySize   = 32
+	dataKeyIDSize = 16
+)
+
+const wrappedKeyMagic = "CBWKEY\x00\x01"
+
+// DataKeyID is a random identifier for a data key. It is part of every sealed stream header
+// and is bound to the wrapped key, so a stream can only be opened with the key it was sealed with.
+type DataKeyID [dataKeyIDSize]byte
+
+func (id DataKeyID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// DataKey is a single-use key for sealing one blob or document.
+type DataKey struct {
+	id  DataKeyID
+	key [dataKeySize]byte
+}
+
+func (k DataKey) ID() DataKeyID {
+	return k.id
+}
+
+func newDataKey() DataKey {
+	var k DataKey
+	if _, err := rand.Read(k.id[:]); err != nil {
+		panic(err)
+	}
+	if _, err := rand.Read(k.key[:]); err != nil {
+		panic(err)
+	}
+	return k
+}
+
+// WrappedKey is a data key encrypted by a master key. It records which provider and master key
+// wrapped it so that it can be unwrapped, or re-wrapped, after the active master key changes.
+type WrappedKey struct {
+	DataKeyID  DataKeyID
+	Provider   string
+	KeyID      string
+	Ciphertext []byte
+}
+
+func (w WrappedKey) MarshalBinary() ([]byte, error) {
+	if len(w.Provider) > 0xff || len(w.KeyID) > 0xffff {
+		return nil, fmt.Errorf("wrapped key provider or key id too long")
+	}
+	out := make([]byte, 0, len(wrappedKeyMagic)+dataKeyIDSize+1+len(w.Provider)+2+len(w.KeyID)+len(w.Ciphertext))
+	out = append(out, wrappedKeyMagic...)
+	out = append(out, w.DataKeyID[:]...)
+	out = append(out, byte(len(w.Provider)))
+	out = append(out, w.Provider...)
+	out = binary.BigEndian.AppendUint16(out, uint16(len(w.KeyID)))
+	out = append(out, w.KeyID...)
+	out = append(out, w.Ciphertext...)
+	return out, nil
+}
+
+func (w *WrappedKey) UnmarshalBinary(data []byte) error {
+	invalid := fmt.Errorf("invalid wrapped key")
+	if len(data) < len(wrappedKeyMagic)+dataKeyIDSize+1 || string(data[:len(wrappedKeyMagic)]) != wrappedKeyMagic {
+		return invalid
+	}
+	data = data[len(wrappedKeyMagic):]
+	copy(w.DataKeyID[:], data)
+	data = data[dataKeyIDSize:]
+
+	providerLen := int(data[0])
+	data = data[1:]
+	if len(data) < providerLen+2 {
+		return invalid
+	}
+	w.Provider = string(data[:providerLen])
+	data = data[providerLen:]
+
+	keyIDLen := int(binary.BigEndian.Uint16(data))
+	data = data[2:]
+	if len(data) < keyIDLen {
+		return invalid
+	}
+	w.KeyID = string(data[:keyIDLen])
+	w.Ciphertext = append([]byte(nil), data[keyIDLen:]...)
+	return nil
+}
+
+// KeyProvider wraps data keys with master keys it holds or has access to.
+type KeyProvider interface {
+	// Name identifies the provider in wrapped keys.
+	Name() string
+	// KeyID is the master key new data keys are wrapped with.
+	KeyID() string
+	// WrapKey encrypts key with the active master key, binding it to aad.
+	WrapKey(ctx context.Context, key, aad []byte) (keyID string, ciphertext []byte, err error)
+	// UnwrapKey decrypts a key wrapped by master key keyID.
+	UnwrapKey(ctx context.Context, keyID string, ciphertext, aad []byte) ([]byte, error)
+}
+
+// UnknownKeyError is returned when a data key was wrapped by a master key that is not available.
+type UnknownKeyError struct {
+	Provider string
+	KeyID    string
+}
+
+func (e UnknownKeyError) Error() string {
+	return fmt.Sprintf("master key %q from provider %q is not available", e.KeyID, e.Provider)
+}
+
+// Keyring creates, unwraps and re-wraps data keys. New data keys are always wrapped by the
+// active provider; the other providers are only used to unwrap keys during a migration.
+type Keyring struct {
+	active    KeyProvider
+	providers map[string]KeyProvider
+}
+
+func NewKeyring(active KeyProvider, others ...KeyProvider) *Keyring {
+	k := &Keyring{
+		active:    active,
+		providers: map[string]KeyProvider{active.Name(): active},
+	}
+	for _, p := range others {
+		if _, ok := k.providers[p.Name()]; !ok {
+			k.providers[p.Name()] = p
+		}
+	}
+	return k
+}
+
+// NewDataKey returns a fresh data key along with its wrapped form for storage.
+func (k *Keyring) NewDataKey(ctx context.Context) (DataKey, WrappedKey, error) {
+	key := newDataKey()
+	wrapped, err := k.wrap(ctx, key)
+	return key, wrapped, err
+}
+
+func (k *Keyring) wrap(ctx context.Context, key DataKey) (WrappedKey, error) {
+	keyID, ciphertext, err := k.active.WrapKey(ctx, key.key[:], key.id[:])
+	if err != nil {
+		return WrappedKey{}, err
+	}
+	return WrappedKey{
+		DataKeyID:  key.id,
+		Provider:   k.active.Name(),
+		KeyID:      keyID,
+		Ciphertext: ciphertext,
+	}, nil
+}
+
+func (k *Keyring) Unwrap(ctx context.Context, wrapped WrappedKey) (DataKey, error) {
+	var key DataKey
+	provider, ok := k.providers[wrapped.Provider]
+	if !ok {
+		return key, UnknownKeyError{Provider: wrapped.Provider, KeyID: wrapped.KeyID}
+	}
+	plaintext, err := provider.UnwrapKey(ctx, wrapped.KeyID, wrapped.Ciphertext, wrapped.DataKeyID[:])
+	if err != nil {
+		return key, err
+	}
+	if len(plaintext) != dataKeySize {
+		return key, fmt.Errorf("unwrapped data key %s has length %d", wrapped.DataKeyID, len(plaintext))
+	}
+	key.id = wrapped.DataKeyID
+	copy(key.key[:], plaintext)
+	return key, nil
+}
+
+// Current reports whether wrapped was wrapped by the active master key.
+func (k *Keyring) Current(wrapped WrappedKey) bool {
+	return wrapped.Provider == k.active.Name() && wrapped.KeyID == k.active.KeyID()
+}
+
+// Rewrap re-encrypts a data key with the active master key. The data key itself, and so
+// everything sealed with it, is unchanged. It reports false if there was nothing to do.
+func (k *Keyring) Rewrap(ctx context.Context, wrapped WrappedKey) (WrappedKey, bool, error) {
+	if k.Current(wrapped) {
+		return wrapped, false, nil
+	}
+	key, err := k.Unwrap(ctx, wrapped)
+	if err != nil {
+		return wrapped, false, err
+	}
+	rewrapped, err := k.wrap(ctx, key)
+	return rewrapped, err == nil, err
+}
@@ -0,0 +1,160 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envelope
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeKeyFile(t *testing.T, ids ...string) string {
+	var lines []string
+	lines = append(lines, "# test keys")
+	for _, id := range ids {
+		lines = append(lines, fmt.Sprintf("%s %s", id, base64.StdEncoding.EncodeToString(randomBytes(32))))
+	}
+	name := filepath.Join(t.TempDir(), "keys")
+	if err := os.WriteFile(name, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return name
+}
+
+func TestKeyFileActiveKey(t *testing.T) {
+	name := writeKeyFile(t, "old", "new")
+	kf, err := LoadKeyFile(name, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kf.KeyID() != "new" {
+		t.Fatalf("expected last key to be active, got %q", kf.KeyID())
+	}
+	if kf, err = LoadKeyFile(name, "old"); err != nil || kf.KeyID() != "old" {
+		t.Fatalf("expected old key to be active, got %v %v", kf, err)
+	}
+	if _, err = LoadKeyFile(name, "missing"); err == nil {
+		t.Fatal("expected error for missing active key")
+	}
+}
+
+func TestKeyringRewrap(t *testing.T) {
+	ctx := context.Background()
+	name := writeKeyFile(t, "old", "new")
+	oldFile, err := LoadKeyFile(name, "old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newFile, err := LoadKeyFile(name, "new")
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldRing := NewKeyring(oldFile)
+	newRing := NewKeyring(newFile)
+
+	key, wrapped, err := oldRing.NewDataKey(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded, err := wrapped.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded WrappedKey
+	if err := decoded.UnmarshalBinary(encoded); err != nil {
+		t.Fatal(err)
+	}
+	if !oldRing.Current(decoded) || newRing.Current(decoded) {
+		t.Fatal("unexpected Current result")
+	}
+
+	rewrapped, changed, err := newRing.Rewrap(ctx, decoded)
+	if err != nil || !changed {
+		t.Fatalf("Rewrap changed=%v err=%v", changed, err)
+	}
+	if rewrapped.KeyID != "new" || rewrapped.DataKeyID != key.ID() {
+		t.Fatalf("unexpected rewrapped key %+v", rewrapped)
+	}
+	unwrapped, err := newRing.Unwrap(ctx, rewrapped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unwrapped != key {
+		t.Fatal("rewrapped data key differs")
+	}
+	if _, changed, err := newRing.Rewrap(ctx, rewrapped); err != nil || changed {
+		t.Fatalf("second Rewrap changed=%v err=%v", changed, err)
+	}
+
+	// A wrapped key moved onto another data key's id must not unwrap.
+	moved := rewrapped
+	moved.DataKeyID[0] ^= 1
+	if _, err := newRing.Unwrap(ctx, moved); err == nil {
+		t.Fatal("expected error for mismatched data key id")
+	}
+
+	onlyNew, err := LoadKeyFile(writeKeyFile(t, "new"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var unknown UnknownKeyError
+	if _, err := NewKeyring(onlyNew).Unwrap(ctx, decoded); !errors.As(err, &unknown) || unknown.KeyID != "old" {
+		t.Fatalf("expected UnknownKeyError, got %v", err)
+	}
+}
+
+func TestDocumentRewrap(t *testing.T) {
+	ctx := context.Background()
+	name := writeKeyFile(t, "old", "new")
+	oldFile, err := LoadKeyFile(name, "old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newFile, err := LoadKeyFile(name, "new")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext := []byte(`{"hello":"world"}`)
+	sealed, err := NewKeyring(oldFile).SealDocument(ctx, plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsSealedDocument(sealed) || bytes.Contains(sealed, plaintext) {
+		t.Fatal("document not sealed")
+	}
+
+	newRing := NewKeyring(newFile)
+	rewrapped, changed, err := newRing.RewrapDocument(ctx, sealed)
+	if err != nil || !changed {
+		t.Fatalf("RewrapDocument changed=%v err=%v", changed, err)
+	}
+	if !bytes.HasSuffix(rewrapped, sealed[len(sealed)-int(SealedSize(int64(len(plaintext)))):]) {
+		t.Fatal("rewrap changed the sealed body")
+	}
+	opened, err := newRing.OpenDocument(ctx, rewrapped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(opened, plaintext) {
+		t.Fatal("round trip mismatch")
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envelope
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+)
+
+const kmsDataKeyContext = "cassandrabackup:data-key-id"
+
+// KMS wraps data keys with an AWS KMS key. Wrapped keys record the key ARN rather than
+// whatever alias was configured, so they stay readable after the alias is moved to a new key.
+type KMS struct {
+	svc    kmsiface.KMSAPI
+	keyARN string
+}
+
+// NewKMS resolves keyID (a key id, ARN or alias) and checks it can be used for wrapping.
+func NewKMS(ctx context.Context, svc kmsiface.KMSAPI, keyID string) (*KMS, error) {
+	output, err := svc.DescribeKeyWithContext(ctx, &kms.DescribeKeyInput{
+		KeyId: aws.String(keyID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	metadata := output.KeyMetadata
+	if metadata.Enabled == nil || !*metadata.Enabled {
+		return nil, fmt.Errorf("kms key %s is not enabled", *metadata.Arn)
+	}
+	if metadata.KeyUsage == nil || *metadata.KeyUsage != kms.KeyUsageTypeEncryptDecrypt {
+		return nil, fmt.Errorf("kms key %s is not an ENCRYPT_DECRYPT key", *metadata.Arn)
+	}
+	return &KMS{
+		svc:    svc,
+		keyARN: *metadata.Arn,
+	}, nil
+}
+
+func (k *KMS) Name() string {
+	return "kms"
+}
+
+func (k *KMS) KeyID() string {
+	return k.keyARN
+}
+
+func (k *KMS) WrapKey(ctx context.Context, key, aad []byte) (string, []byte, error) {
+	output, err := k.svc.EncryptWithContext(ctx, &kms.EncryptInput{
+		KeyId:             aws.String(k.keyARN),
+		Plaintext:         key,
+		EncryptionContext: kmsEncryptionContext(aad),
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return *output.KeyId, output.CiphertextBlob, nil
+}
+
+func (k *KMS) UnwrapKey(ctx context.Context, keyID string, ciphertext, aad []byte) ([]byte, error) {
+	output, err := k.svc.DecryptWithContext(ctx, &kms.DecryptInput{
+		KeyId:             aws.String(keyID),
+		CiphertextBlob:    ciphertext,
+		EncryptionContext: kmsEncryptionContext(aad),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output.Plaintext, nil
+}
+
+func kmsEncryptionContext(aad []byte) map[string]*string {
+	return map[string]*string{
+		kmsDataKeyContext: aws.String(hex.EncodeToString(aad)),
+	}
+}
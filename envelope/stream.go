@@ -0,0 +1,315 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package envelope implements client-side authenticated encryption of blobs and documents.
+//
+// A sealed stream is a fixed header followed by the plaintext split into ChunkSize chunks,
+// each sealed with AES-256-GCM under a single-use data key. The chunk index and a final-chunk
+// flag are part of each nonce, so chunks cannot be reordered, dropped or truncated unnoticed,
+// and because the layout is fixed any byte range of a sealed stream can be produced on demand.
+package envelope
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+const (
+	ChunkSize = 64 * 1024
+
+	tagSize         = 16
+	sealedChunkSize = ChunkSize + tagSize
+	noncePrefixSize = 7
+
+	// HeaderSize is the length of the header that starts every sealed stream.
+	HeaderSize = streamMagicSize + dataKeyIDSize
+
+	sealedReaderCacheChunks = 16
+)
+
+const (
+	streamMagic     = "CBSEAL\x00\x01"
+	streamMagicSize = 8
+)
+
+var ErrMalformed = errors.New("malformed sealed stream")
+
+// IsSealed reports whether header (the first HeaderSize or more bytes of an object) starts a sealed stream.
+func IsSealed(header []byte) bool {
+	return len(header) >= HeaderSize && string(header[:streamMagicSize]) == streamMagic
+}
+
+// StreamKeyID returns the ID of the data key a sealed stream was written with.
+func StreamKeyID(header []byte) (DataKeyID, error) {
+	var id DataKeyID
+	if !IsSealed(header) {
+		return id, ErrMalformed
+	}
+	copy(id[:], header[streamMagicSize:HeaderSize])
+	return id, nil
+}
+
+// SealedSize returns the length of the sealed stream for plainSize bytes of plaintext.
+func SealedSize(plainSize int64) int64 {
+	return int64(HeaderSize) + plainSize + numChunks(plainSize)*tagSize
+}
+
+// PlainSize is the inverse of SealedSize.
+func PlainSize(sealedSize int64) (int64, error) {
+	body := sealedSize - int64(HeaderSize)
+	if body < tagSize {
+		return 0, ErrMalformed
+	}
+	full := body / sealedChunkSize
+	rem := body % sealedChunkSize
+	if rem == 0 {
+		return full * ChunkSize, nil
+	}
+	if rem < tagSize || (rem == tagSize && full > 0) {
+		return 0, ErrMalformed
+	}
+	return full*ChunkSize + rem - tagSize, nil
+}
+
+// numChunks is never zero; empty plaintext is sealed as a single empty final chunk.
+func numChunks(plainSize int64) int64 {
+	n := plainSize / ChunkSize
+	if plainSize%ChunkSize != 0 || plainSize == 0 {
+		n++
+	}
+	return n
+}
+
+type streamCipher struct {
+	aead   cipher.AEAD
+	header [HeaderSize]byte
+}
+
+func newStreamCipher(key DataKey) streamCipher {
+	block, err := aes.NewCipher(key.key[:])
+	if err != nil {
+		panic(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		panic(err)
+	}
+	s := streamCipher{aead: aead}
+	copy(s.header[:], streamMagic)
+	copy(s.header[streamMagicSize:], key.id[:])
+	return s
+}
+
+func (s *streamCipher) nonce(index int64, final bool) []byte {
+	if index > 0xffffffff {
+		panic("sealed stream too long")
+	}
+	nonce := make([]byte, s.aead.NonceSize())
+	copy(nonce, s.header[streamMagicSize:streamMagicSize+noncePrefixSize])
+	binary.BigEndian.PutUint32(nonce[noncePrefixSize:], uint32(index))
+	if final {
+		nonce[noncePrefixSize+4] = 1
+	}
+	return nonce
+}
+
+// SealedReader presents the sealed form of a plaintext as a random access reader without
+// materializing it, so uploads can send (and retry) any part of it independently.
+type SealedReader struct {
+	cipher    streamCipher
+	plaintext io.ReaderAt
+	plainSize int64
+
+	lock  sync.Mutex
+	cache map[int64][]byte
+}
+
+func NewSealedReader(key DataKey, plaintext io.ReaderAt, plainSize int64) *SealedReader {
+	return &SealedReader{
+		cipher:    newStreamCipher(key),
+		plaintext: plaintext,
+		plainSize: plainSize,
+		cache:     make(map[int64][]byte),
+	}
+}
+
+func (r *SealedReader) Size() int64 {
+	return SealedSize(r.plainSize)
+}
+
+func (r *SealedReader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, fmt.Errorf("negative offset")
+	}
+	size := r.Size()
+	var n int
+	for n < len(p) && off < size {
+		if off < HeaderSize {
+			c := copy(p[n:], r.cipher.header[off:])
+			n += c
+			off += int64(c)
+			continue
+		}
+		index := (off - HeaderSize) / sealedChunkSize
+		chunk, err := r.chunk(index)
+		if err != nil {
+			return n, err
+		}
+		c := copy(p[n:], chunk[(off-HeaderSize)%sealedChunkSize:])
+		n += c
+		off += int64(c)
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// chunk seals one chunk of the plaintext. Readers rarely ask for a whole chunk at once,
+// so recently sealed chunks are kept to avoid sealing each one several times.
+func (r *SealedReader) chunk(index int64) ([]byte, error) {
+	r.lock.Lock()
+	cached, ok := r.cache[index]
+	r.lock.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	offset := index * ChunkSize
+	length := r.plainSize - offset
+	if length > ChunkSize {
+		length = ChunkSize
+	}
+	buf := make([]byte, length, length+tagSize)
+	if n, err := r.plaintext.ReadAt(buf, offset); n != len(buf) {
+		if err == nil || err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	final := index == numChunks(r.plainSize)-1
+	sealed := r.cipher.aead.Seal(buf[:0], r.cipher.nonce(index, final), buf, r.cipher.header[:])
+
+	r.lock.Lock()
+	if len(r.cache) >= sealedReaderCacheChunks {
+		for k := range r.cache {
+			delete(r.cache, k)
+			break
+		}
+	}
+	r.cache[index] = sealed
+	r.lock.Unlock()
+	return sealed, nil
+}
+
+// Seal returns the sealed form of a plaintext held in memory.
+func Seal(key DataKey, plaintext []byte) []byte {
+	r := NewSealedReader(key, bytes.NewReader(plaintext), int64(len(plaintext)))
+	sealed := make([]byte, r.Size())
+	if _, err := r.ReadAt(sealed, 0); err != nil {
+		panic(err)
+	}
+	return sealed
+}
+
+// Open authenticates and decrypts a sealed stream held in memory.
+func Open(key DataKey, sealed []byte) ([]byte, error) {
+	plainSize, err := PlainSize(int64(len(sealed)))
+	if err != nil {
+		return nil, err
+	}
+	plaintext := make(sliceWriter, plainSize)
+	if err := openStream(key, bytes.NewReader(sealed), int64(len(sealed)), plaintext); err != nil {
+		return nil, err
+	}
+	return plaintext, nil
+}
+
+// OpenFile authenticates and decrypts a sealed stream in place, leaving the plaintext in file.
+// Each chunk's plaintext is written no later in the file than where its sealed form was read,
+// so no temporary space is needed. On error the contents of file are unspecified.
+func OpenFile(key DataKey, file *os.File) error {
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if err := openStream(key, file, info.Size(), file); err != nil {
+		return err
+	}
+	plainSize, _ := PlainSize(info.Size())
+	if err := file.Truncate(plainSize); err != nil {
+		return err
+	}
+	_, err = file.Seek(0, io.SeekStart)
+	return err
+}
+
+func openStream(key DataKey, src io.ReaderAt, sealedSize int64, dst io.WriterAt) error {
+	plainSize, err := PlainSize(sealedSize)
+	if err != nil {
+		return err
+	}
+	s := newStreamCipher(key)
+	var header [HeaderSize]byte
+	if _, err := src.ReadAt(header[:], 0); err != nil {
+		return err
+	}
+	if !IsSealed(header[:]) {
+		return ErrMalformed
+	}
+	if header != s.header {
+		return fmt.Errorf("sealed stream was not written with data key %s", key.ID())
+	}
+
+	chunks := numChunks(plainSize)
+	buf := make([]byte, sealedChunkSize)
+	for index := int64(0); index < chunks; index++ {
+		offset := HeaderSize + index*sealedChunkSize
+		length := sealedSize - offset
+		if length > sealedChunkSize {
+			length = sealedChunkSize
+		}
+		sealed := buf[:length]
+		if n, err := src.ReadAt(sealed, offset); n != len(sealed) {
+			if err == nil || err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		plain, err := s.aead.Open(sealed[:0], s.nonce(index, index == chunks-1), sealed, s.header[:])
+		if err != nil {
+			return fmt.Errorf("%w: chunk %d: %w", ErrMalformed, index, err)
+		}
+		if _, err := dst.WriteAt(plain, index*ChunkSize); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type sliceWriter []byte
+
+func (w sliceWriter) WriteAt(p []byte, off int64) (int, error) {
+	if off < 0 || off+int64(len(p)) > int64(len(w)) {
+		return 0, io.ErrShortWrite
+	}
+	return copy(w[off:], p), nil
+}
@@ -0,0 +1,134 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envelope
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var streamTestSizes = []int64{0, 1, ChunkSize - 1, ChunkSize, ChunkSize + 1, 3*ChunkSize + 17}
+
+func TestSealOpen(t *testing.T) {
+	for _, size := range streamTestSizes {
+		key := newDataKey()
+		plaintext := randomBytes(size)
+		sealed := Seal(key, plaintext)
+		if int64(len(sealed)) != SealedSize(size) {
+			t.Fatalf("size=%d: sealed length %d != SealedSize %d", size, len(sealed), SealedSize(size))
+		}
+		if plainSize, err := PlainSize(int64(len(sealed))); err != nil || plainSize != size {
+			t.Fatalf("size=%d: PlainSize=%d err=%v", size, plainSize, err)
+		}
+		if id, err := StreamKeyID(sealed); err != nil || id != key.ID() {
+			t.Fatalf("size=%d: StreamKeyID=%v err=%v", size, id, err)
+		}
+		opened, err := Open(key, sealed)
+		if err != nil {
+			t.Fatalf("size=%d: %v", size, err)
+		}
+		if !bytes.Equal(opened, plaintext) {
+			t.Fatalf("size=%d: round trip mismatch", size)
+		}
+	}
+}
+
+func TestSealedReaderReadAt(t *testing.T) {
+	key := newDataKey()
+	plaintext := randomBytes(5*ChunkSize + 123)
+	expected := Seal(key, plaintext)
+	r := NewSealedReader(key, bytes.NewReader(plaintext), int64(len(plaintext)))
+
+	for _, span := range [][2]int64{{0, 5}, {3, HeaderSize + 10}, {HeaderSize + ChunkSize - 3, 40}, {2 * sealedChunkSize, sealedChunkSize + 7}} {
+		buf := make([]byte, span[1])
+		if _, err := r.ReadAt(buf, span[0]); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf, expected[span[0]:span[0]+span[1]]) {
+			t.Fatalf("ReadAt(%d, %d) mismatch", span[0], span[1])
+		}
+	}
+
+	var copied bytes.Buffer
+	if _, err := io.Copy(&copied, io.NewSectionReader(r, 0, r.Size())); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(copied.Bytes(), expected) {
+		t.Fatal("sequential read mismatch")
+	}
+
+	tail := make([]byte, 10)
+	if n, err := r.ReadAt(tail, r.Size()-4); n != 4 || err != io.EOF {
+		t.Fatalf("ReadAt past end: n=%d err=%v", n, err)
+	}
+}
+
+func TestOpenDetectsTampering(t *testing.T) {
+	key := newDataKey()
+	sealed := Seal(key, randomBytes(2*ChunkSize+5))
+
+	flipped := append([]byte(nil), sealed...)
+	flipped[HeaderSize+ChunkSize+3] ^= 1
+	if _, err := Open(key, flipped); err == nil {
+		t.Fatal("expected error for modified chunk")
+	}
+
+	truncated := sealed[:HeaderSize+2*sealedChunkSize]
+	if _, err := Open(key, truncated); err == nil {
+		t.Fatal("expected error for dropped final chunk")
+	}
+
+	if _, err := Open(newDataKey(), sealed); err == nil {
+		t.Fatal("expected error for wrong key")
+	}
+}
+
+func TestOpenFile(t *testing.T) {
+	for _, size := range streamTestSizes {
+		key := newDataKey()
+		plaintext := randomBytes(size)
+		name := filepath.Join(t.TempDir(), "blob")
+		if err := os.WriteFile(name, Seal(key, plaintext), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		file, err := os.OpenFile(name, os.O_RDWR, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := OpenFile(key, file); err != nil {
+			t.Fatalf("size=%d: %v", size, err)
+		}
+		opened, err := io.ReadAll(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = file.Close()
+		if !bytes.Equal(opened, plaintext) {
+			t.Fatalf("size=%d: round trip mismatch", size)
+		}
+	}
+}
+
+func randomBytes(n int64) []byte {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return b
+}
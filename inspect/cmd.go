@@ -0,0 +1,42 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inspect
+
+import (
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/retailnext/cassandrabackup/list"
+	"github.com/retailnext/cassandrabackup/unixtime"
+)
+
+var (
+	Cmd = kingpin.Command("manifest", "Inspect backup manifests")
+
+	cmdOutput = Cmd.Flag("output", "Output format").Default(list.FormatTable).Enum(list.Formats...)
+
+	ShowCmd      = Cmd.Command("show", "Show a manifest with its files rolled up by table")
+	showCluster  = ShowCmd.Flag("cluster", "Cluster name").Required().String()
+	showHostname = ShowCmd.Flag("hostname", "Hostname").Required().String()
+	showAt       = unixtime.Flag(ShowCmd.Flag("at", "Show the latest manifest at or before this time, instead of the latest "+unixtime.TimeHelp))
+	showFiles    = ShowCmd.Flag("files", "List every file instead of rolling them up by table").Bool()
+	showSizes    = ShowCmd.Flag("sizes", "Look up the stored size of each blob, which takes a request per blob").Bool()
+
+	DiffCmd        = Cmd.Command("diff", "Show the files added, removed and changed between two manifests")
+	diffCluster    = DiffCmd.Flag("cluster", "Cluster name").Required().String()
+	diffHostname   = DiffCmd.Flag("hostname", "Hostname").Required().String()
+	diffAt         = unixtime.Flag(DiffCmd.Flag("at", "Compare from the latest manifest at or before this time, instead of the latest "+unixtime.TimeHelp))
+	diffToCluster  = DiffCmd.Flag("to-cluster", "Compare to a host in this cluster (default: --cluster)").String()
+	diffToHostname = DiffCmd.Flag("to-hostname", "Compare to this host (default: --hostname)").String()
+	diffToAt       = unixtime.Flag(DiffCmd.Flag("to-at", "Compare to the latest manifest at or before this time, instead of the latest "+unixtime.TimeHelp))
+)
@@ -0,0 +1,322 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inspect
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/retailnext/cassandrabackup/bucket"
+	"github.com/retailnext/cassandrabackup/digest"
+	"github.com/retailnext/cassandrabackup/list"
+	"github.com/retailnext/cassandrabackup/manifests"
+	"github.com/retailnext/cassandrabackup/restore/plan"
+	"github.com/retailnext/cassandrabackup/unixtime"
+)
+
+// Select returns the host's latest manifest at or before at, or its latest manifest if at is zero.
+func Select(ctx context.Context, client bucket.Client, identity manifests.NodeIdentity, at unixtime.Seconds) (manifests.Manifest, error) {
+	notAfter, err := plan.NotAfter(at, 0)
+	if err != nil {
+		return manifests.Manifest{}, err
+	}
+	keys, err := client.ListManifests(ctx, identity, 0, notAfter)
+	if err != nil {
+		return manifests.Manifest{}, err
+	}
+	if len(keys) == 0 {
+		return manifests.Manifest{}, fmt.Errorf("no manifests found for %s/%s", identity.Cluster, identity.Hostname)
+	}
+	got, err := client.GetManifests(ctx, identity, keys[len(keys)-1:])
+	if err != nil {
+		return manifests.Manifest{}, err
+	}
+	return got[0], nil
+}
+
+var TableHeader = []string{"keyspace", "table", "files", "size", "bytes"}
+
+// TableRow has the size of the table's files if the manifest records them, and the stored size
+// of their blobs if that was looked up.
+type TableRow struct {
+	Keyspace string `json:"keyspace"`
+	Table    string `json:"table"`
+	Files    int    `json:"files"`
+	Size     *int64 `json:"size,omitempty"`
+	Bytes    *int64 `json:"bytes,omitempty"`
+}
+
+func (r TableRow) Fields() []string {
+	return []string{r.Keyspace, r.Table, strconv.Itoa(r.Files), list.FormatSize(r.Size), list.FormatSize(r.Bytes)}
+}
+
+var FileHeader = []string{"name", "blob", "size", "bytes"}
+
+type FileRow struct {
+	Name  string            `json:"name"`
+	Blob  digest.ForRestore `json:"blob"`
+	Size  *int64            `json:"size,omitempty"`
+	Bytes *int64            `json:"bytes,omitempty"`
+}
+
+func (r FileRow) Fields() []string {
+	blob, _ := r.Blob.MarshalText()
+	return []string{r.Name, string(blob), list.FormatSize(r.Size), list.FormatSize(r.Bytes)}
+}
+
+// Rollup counts the manifest's files by table, with the sizes it records added up if it records
+// them all, and the stored sizes of their blobs added up if sizes is not nil.
+// Files of secondary indexes count towards their table.
+func Rollup(m manifests.Manifest, sizes map[digest.ForRestore]int64) []TableRow {
+	byTable := make(map[[2]string]*TableRow)
+	for name, file := range m.DataFiles {
+		keyspace, table, ok := plan.Table(name)
+		if !ok {
+			keyspace, table = "", name
+		}
+		row := byTable[[2]string{keyspace, table}]
+		if row == nil {
+			row = &TableRow{Keyspace: keyspace, Table: table}
+			if m.FileInfo != nil {
+				row.Size = new(int64)
+			}
+			if sizes != nil {
+				row.Bytes = new(int64)
+			}
+			byTable[[2]string{keyspace, table}] = row
+		}
+		row.Files++
+		if row.Size != nil {
+			if size := m.Size(name); size != nil {
+				*row.Size += *size
+			} else {
+				row.Size = nil
+			}
+		}
+		if sizes != nil {
+			*row.Bytes += sizes[file]
+		}
+	}
+	rows := make([]TableRow, 0, len(byTable))
+	for _, row := range byTable {
+		rows = append(rows, *row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Keyspace != rows[j].Keyspace {
+			return rows[i].Keyspace < rows[j].Keyspace
+		}
+		return rows[i].Table < rows[j].Table
+	})
+	return rows
+}
+
+// Files lists the manifest's files by name, with the sizes it records, and the stored sizes
+// of their blobs if sizes is not nil.
+func Files(m manifests.Manifest, sizes map[digest.ForRestore]int64) []FileRow {
+	rows := make([]FileRow, 0, len(m.DataFiles))
+	for name, file := range m.DataFiles {
+		row := FileRow{Name: name, Blob: file, Size: m.Size(name)}
+		if sizes != nil {
+			size := sizes[file]
+			row.Bytes = &size
+		}
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].Name < rows[j].Name
+	})
+	return rows
+}
+
+type showJSON struct {
+	Cluster     string     `json:"cluster"`
+	Hostname    string     `json:"hostname"`
+	Manifest    string     `json:"manifest"`
+	Time        time.Time  `json:"time"`
+	Type        string     `json:"type"`
+	HostID      string     `json:"host_id"`
+	Address     string     `json:"address"`
+	DataCenter  string     `json:"data_center,omitempty"`
+	Rack        string     `json:"rack,omitempty"`
+	Peers       int        `json:"peers,omitempty"`
+	Partitioner string     `json:"partitioner"`
+	Tokens      []string   `json:"tokens"`
+	Size        *int64     `json:"size,omitempty"`
+	ToolVersion string     `json:"tool_version,omitempty"`
+	Tables      []TableRow `json:"tables"`
+	Files       []FileRow  `json:"files,omitempty"`
+}
+
+func Show(ctx context.Context) error {
+	client := bucket.OpenShared()
+	identity := manifests.NodeIdentity{Cluster: *showCluster, Hostname: *showHostname}
+	m, err := Select(ctx, client, identity, *showAt)
+	if err != nil {
+		return err
+	}
+	var sizes map[digest.ForRestore]int64
+	if *showSizes {
+		if sizes, err = bucket.StatBlobs(ctx, client, manifests.Blobs([]manifests.Manifest{m})); err != nil {
+			return err
+		}
+	}
+	return writeShow(os.Stdout, *cmdOutput, identity, m, sizes, *showFiles)
+}
+
+func writeShow(w io.Writer, format string, identity manifests.NodeIdentity, m manifests.Manifest, sizes map[digest.ForRestore]int64, files bool) error {
+	tables := Rollup(m, sizes)
+	var fileRows []FileRow
+	if files {
+		fileRows = Files(m, sizes)
+	}
+
+	switch format {
+	case list.FormatJSON:
+		doc := showJSON{
+			Cluster:     identity.Cluster,
+			Hostname:    identity.Hostname,
+			Manifest:    m.Key().FileName(),
+			Time:        m.Time.Time().UTC(),
+			Type:        m.ManifestType.String(),
+			HostID:      m.HostID,
+			Address:     m.Address,
+			DataCenter:  m.DataCenter,
+			Rack:        m.Rack,
+			Peers:       len(m.Peers),
+			Partitioner: m.Partitioner,
+			Tokens:      m.Tokens,
+			ToolVersion: m.ToolVersion,
+			Tables:      tables,
+			Files:       fileRows,
+		}
+		if _, size, known := m.Totals(); known {
+			doc.Size = &size
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(doc)
+	case list.FormatTable:
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		for _, line := range [][2]string{
+			{"cluster", identity.Cluster},
+			{"hostname", identity.Hostname},
+			{"time", m.Time.String()},
+			{"type", m.ManifestType.String()},
+			{"host_id", m.HostID},
+			{"address", m.Address},
+			{"data_center", m.DataCenter},
+			{"rack", m.Rack},
+			{"peers", strconv.Itoa(len(m.Peers))},
+			{"partitioner", m.Partitioner},
+			{"tokens", strconv.Itoa(len(m.Tokens))},
+			{"files", strconv.Itoa(len(m.DataFiles))},
+			{"size", totalSize(m)},
+			{"tool_version", m.ToolVersion},
+		} {
+			if _, err := fmt.Fprintf(tw, "%s:\t%s\n", line[0], line[1]); err != nil {
+				return err
+			}
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+	if files {
+		return list.Write(w, format, FileHeader, fileRows)
+	}
+	return list.Write(w, format, TableHeader, tables)
+}
+
+func totalSize(m manifests.Manifest) string {
+	if _, size, known := m.Totals(); known {
+		return strconv.FormatInt(size, 10)
+	}
+	return ""
+}
+
+var DiffHeader = []string{"change", "name", "from", "to"}
+
+type DiffRow struct {
+	Change string             `json:"change"`
+	Name   string             `json:"name"`
+	From   *digest.ForRestore `json:"from,omitempty"`
+	To     *digest.ForRestore `json:"to,omitempty"`
+}
+
+func (r DiffRow) Fields() []string {
+	var from, to []byte
+	if r.From != nil {
+		from, _ = r.From.MarshalText()
+	}
+	if r.To != nil {
+		to, _ = r.To.MarshalText()
+	}
+	return []string{r.Change, r.Name, string(from), string(to)}
+}
+
+// DiffRows lists the changes between two manifests by file name. A snapshot manifest lists every file
+// the host had, but an incremental one only lists the files added since the backup before it.
+func DiffRows(from, to manifests.Manifest) []DiffRow {
+	diff := plan.Diff(from, to)
+	var rows []DiffRow
+	for name, file := range diff.Added {
+		rows = append(rows, DiffRow{Change: "added", Name: name, To: &file})
+	}
+	for name, file := range diff.Removed {
+		rows = append(rows, DiffRow{Change: "removed", Name: name, From: &file})
+	}
+	for name, history := range diff.Changed {
+		rows = append(rows, DiffRow{Change: "changed", Name: name, From: &history[0].Digest, To: &history[len(history)-1].Digest})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].Name < rows[j].Name
+	})
+	return rows
+}
+
+func Diff(ctx context.Context) error {
+	client := bucket.OpenShared()
+	fromIdentity := manifests.NodeIdentity{Cluster: *diffCluster, Hostname: *diffHostname}
+	toIdentity := fromIdentity
+	if *diffToCluster != "" {
+		toIdentity.Cluster = *diffToCluster
+	}
+	if *diffToHostname != "" {
+		toIdentity.Hostname = *diffToHostname
+	}
+	from, err := Select(ctx, client, fromIdentity, *diffAt)
+	if err != nil {
+		return err
+	}
+	to, err := Select(ctx, client, toIdentity, *diffToAt)
+	if err != nil {
+		return err
+	}
+	if *cmdOutput == list.FormatTable {
+		fmt.Printf("from:\t%s/%s %s\nto:\t%s/%s %s\n\n",
+			fromIdentity.Cluster, fromIdentity.Hostname, from.Key(), toIdentity.Cluster, toIdentity.Hostname, to.Key())
+	}
+	return list.Write(os.Stdout, *cmdOutput, DiffHeader, DiffRows(from, to))
+}
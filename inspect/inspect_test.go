@@ -0,0 +1,105 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inspect
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/retailnext/cassandrabackup/digest"
+	"github.com/retailnext/cassandrabackup/list"
+	"github.com/retailnext/cassandrabackup/manifests"
+)
+
+func testDigest(t *testing.T, c string) digest.ForRestore {
+	var d digest.ForRestore
+	if err := d.UnmarshalText([]byte(strings.Repeat(c, 86) + "==")); err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestRollup(t *testing.T) {
+	a, b, c := testDigest(t, "B"), testDigest(t, "C"), testDigest(t, "D")
+	m := manifests.Manifest{DataFiles: map[string]digest.ForRestore{
+		"ks/users-0123/nb-1-big-Data.db":              a,
+		"ks/users-0123/nb-1-big-Index.db":             b,
+		"ks/users-0123/.users_email/nb-1-big-Data.db": c,
+		"system/local-4567/nb-2-big-Data.db":          a,
+	}}
+	size := func(n int64) *int64 { return &n }
+
+	expected := []TableRow{
+		{Keyspace: "ks", Table: "users", Files: 3, Bytes: size(60)},
+		{Keyspace: "system", Table: "local", Files: 1, Bytes: size(10)},
+	}
+	if diff := deep.Equal(Rollup(m, map[digest.ForRestore]int64{a: 10, b: 20, c: 30}), expected); diff != nil {
+		t.Error(diff)
+	}
+
+	var out bytes.Buffer
+	if err := list.Write(&out, list.FormatCSV, TableHeader, Rollup(m, nil)); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "keyspace,table,files,size,bytes\nks,users,3,,\nsystem,local,1,,\n" {
+		t.Errorf("unexpected csv:\n%s", out.String())
+	}
+
+	// Tables have a size only if the manifest records the size of all their files.
+	m.FileInfo = map[string]manifests.FileInfo{
+		"ks/users-0123/nb-1-big-Data.db":     {Size: 100},
+		"ks/users-0123/nb-1-big-Index.db":    {Size: 200},
+		"system/local-4567/nb-2-big-Data.db": {Size: 5},
+	}
+	expected = []TableRow{
+		{Keyspace: "ks", Table: "users", Files: 3},
+		{Keyspace: "system", Table: "local", Files: 1, Size: size(5)},
+	}
+	if diff := deep.Equal(Rollup(m, nil), expected); diff != nil {
+		t.Error(diff)
+	}
+	m.FileInfo["ks/users-0123/.users_email/nb-1-big-Data.db"] = manifests.FileInfo{Size: 300}
+	expected[0].Size = size(600)
+	if diff := deep.Equal(Rollup(m, nil), expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestDiffRows(t *testing.T) {
+	a, b, c := testDigest(t, "B"), testDigest(t, "C"), testDigest(t, "D")
+	from := manifests.Manifest{Time: 100, ManifestType: manifests.ManifestTypeSnapshot, DataFiles: map[string]digest.ForRestore{
+		"ks/t-1/nb-1-big-Data.db": a,
+		"ks/t-1/nb-2-big-Data.db": b,
+		"ks/t-1/nb-3-big-Data.db": a,
+	}}
+	to := manifests.Manifest{Time: 200, ManifestType: manifests.ManifestTypeSnapshot, DataFiles: map[string]digest.ForRestore{
+		"ks/t-1/nb-1-big-Data.db": a,
+		"ks/t-1/nb-3-big-Data.db": c,
+		"ks/t-1/nb-4-big-Data.db": b,
+	}}
+	expected := []DiffRow{
+		{Change: "removed", Name: "ks/t-1/nb-2-big-Data.db", From: &b},
+		{Change: "changed", Name: "ks/t-1/nb-3-big-Data.db", From: &a, To: &c},
+		{Change: "added", Name: "ks/t-1/nb-4-big-Data.db", To: &b},
+	}
+	if diff := deep.Equal(DiffRows(from, to), expected); diff != nil {
+		t.Error(diff)
+	}
+	if rows := DiffRows(to, to); len(rows) != 0 {
+		t.Errorf("expected no changes, got %+v", rows)
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package list
+
+import (
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/retailnext/cassandrabackup/unixtime"
+)
+
+var (
+	Cmd = kingpin.Command("list", "")
+
+	cmdOutput = Cmd.Flag("output", "Output format").Default(FormatTable).Enum(Formats...)
+
+	ManifestsCmd       = Cmd.Command("manifests", "List manifests for a host")
+	manifestsCluster   = ManifestsCmd.Flag("cluster", "Cluster name to restore from").Required().String()
+	manifestsHostname  = ManifestsCmd.Flag("hostname", "Hostname to restore from").Required().String()
+	manifestsNotBefore = unixtime.Flag(ManifestsCmd.Flag("not-before", "Ignore manifests before this time "+unixtime.TimeHelp))
+	manifestsNotAfter  = unixtime.Flag(ManifestsCmd.Flag("not-after", "Ignore manifests after this time "+unixtime.TimeHelp))
+	manifestsAt        = unixtime.Flag(ManifestsCmd.Flag("at", "Only list the manifests a restore at this time would use "+unixtime.TimeHelp))
+	manifestsSizes     = ManifestsCmd.Flag("sizes", "Also add up the stored size of each manifest's blobs, which takes a request per blob").Bool()
+
+	HostsCmd     = Cmd.Command("hosts", "List hosts in a cluster")
+	hostsCluster = HostsCmd.Flag("cluster", "Cluster name").Required().String()
+
+	ClustersCmd = Cmd.Command("clusters", "List clusters in the bucket")
+)
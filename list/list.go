@@ -0,0 +1,202 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package list
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/retailnext/cassandrabackup/bucket"
+	"github.com/retailnext/cassandrabackup/digest"
+	"github.com/retailnext/cassandrabackup/manifests"
+	"github.com/retailnext/cassandrabackup/restore/plan"
+)
+
+var ManifestHeader = []string{"cluster", "hostname", "time", "type", "host_id", "tokens", "files", "size", "bytes"}
+
+type ManifestRow struct {
+	Cluster  string    `json:"cluster"`
+	Hostname string    `json:"hostname"`
+	Time     time.Time `json:"time"`
+	Type     string    `json:"type"`
+	HostID   string    `json:"host_id"`
+	Tokens   int       `json:"tokens"`
+	Files    int       `json:"files"`
+	// Size is the size of the manifest's files as recorded in it, if it records them.
+	Size *int64 `json:"size,omitempty"`
+	// Bytes is the stored size of the manifest's blobs, when it was asked for.
+	Bytes *int64 `json:"bytes,omitempty"`
+}
+
+func (r ManifestRow) Fields() []string {
+	return []string{r.Cluster, r.Hostname, formatTime(r.Time), r.Type, r.HostID, strconv.Itoa(r.Tokens), strconv.Itoa(r.Files), FormatSize(r.Size), FormatSize(r.Bytes)}
+}
+
+// FormatSize formats an optional size, which is empty when unknown.
+func FormatSize(size *int64) string {
+	if size == nil {
+		return ""
+	}
+	return strconv.FormatInt(*size, 10)
+}
+
+var HostHeader = []string{"cluster", "hostname", "manifests", "last_snapshot", "last_incremental"}
+
+type HostRow struct {
+	Cluster         string    `json:"cluster"`
+	Hostname        string    `json:"hostname"`
+	Manifests       int       `json:"manifests"`
+	LastSnapshot    time.Time `json:"last_snapshot,omitzero"`
+	LastIncremental time.Time `json:"last_incremental,omitzero"`
+}
+
+func (r HostRow) Fields() []string {
+	return []string{r.Cluster, r.Hostname, strconv.Itoa(r.Manifests), formatTime(r.LastSnapshot), formatTime(r.LastIncremental)}
+}
+
+var ClusterHeader = []string{"cluster", "hosts"}
+
+type ClusterRow struct {
+	Cluster string `json:"cluster"`
+	Hosts   int    `json:"hosts"`
+}
+
+func (r ClusterRow) Fields() []string {
+	return []string{r.Cluster, strconv.Itoa(r.Hosts)}
+}
+
+func Manifests(ctx context.Context) error {
+	identity := manifests.NodeIdentity{
+		Cluster:  *manifestsCluster,
+		Hostname: *manifestsHostname,
+	}
+	notAfter, err := plan.NotAfter(*manifestsAt, *manifestsNotAfter)
+	if err != nil {
+		return err
+	}
+	client := bucket.OpenShared()
+	keys, err := client.ListManifests(ctx, identity, *manifestsNotBefore, notAfter)
+	if err != nil {
+		return err
+	}
+	if *manifestsAt != 0 {
+		keys = keys.FromLatestSnapshot()
+	}
+	rows, err := ManifestRows(ctx, client, identity, keys, *manifestsSizes)
+	if err != nil {
+		return err
+	}
+	return Write(os.Stdout, *cmdOutput, ManifestHeader, rows)
+}
+
+// ManifestRows describes the host's manifests with the given keys. With sizes, the stored size of each
+// blob is looked up so that the manifests' sizes can be added up; missing blobs count as empty.
+func ManifestRows(ctx context.Context, client bucket.Client, identity manifests.NodeIdentity, keys manifests.ManifestKeys, sizes bool) ([]ManifestRow, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	got, err := client.GetManifests(ctx, identity, keys)
+	if err != nil {
+		return nil, err
+	}
+	var blobSizes map[digest.ForRestore]int64
+	if sizes {
+		if blobSizes, err = bucket.StatBlobs(ctx, client, manifests.Blobs(got)); err != nil {
+			return nil, err
+		}
+	}
+
+	rows := make([]ManifestRow, 0, len(got))
+	for _, m := range got {
+		row := ManifestRow{
+			Cluster:  identity.Cluster,
+			Hostname: identity.Hostname,
+			Time:     m.Time.Time().UTC(),
+			Type:     m.ManifestType.String(),
+			HostID:   m.HostID,
+			Tokens:   len(m.Tokens),
+			Files:    len(m.DataFiles),
+		}
+		if _, size, known := m.Totals(); known {
+			row.Size = &size
+		}
+		if sizes {
+			var total int64
+			for _, file := range m.DataFiles {
+				total += blobSizes[file]
+			}
+			row.Bytes = &total
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func Hosts(ctx context.Context) error {
+	rows, err := HostRows(ctx, bucket.OpenShared(), *hostsCluster)
+	if err != nil {
+		return err
+	}
+	return Write(os.Stdout, *cmdOutput, HostHeader, rows)
+}
+
+// HostRows describes the hosts in cluster, with the times of their latest snapshot and incremental backups.
+func HostRows(ctx context.Context, client bucket.Client, cluster string) ([]HostRow, error) {
+	identities, err := client.ListHostNames(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]HostRow, 0, len(identities))
+	for _, identity := range identities {
+		keys, err := client.ListManifests(ctx, identity, 0, 0)
+		if err != nil {
+			return nil, err
+		}
+		row := HostRow{
+			Cluster:   identity.Cluster,
+			Hostname:  identity.Hostname,
+			Manifests: len(keys),
+		}
+		for _, key := range keys {
+			switch key.ManifestType {
+			case manifests.ManifestTypeSnapshot:
+				row.LastSnapshot = key.Time.Time().UTC()
+			case manifests.ManifestTypeIncremental:
+				row.LastIncremental = key.Time.Time().UTC()
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func Clusters(ctx context.Context) error {
+	client := bucket.OpenShared()
+	clusters, err := client.ListClusters(ctx)
+	if err != nil {
+		return err
+	}
+	rows := make([]ClusterRow, 0, len(clusters))
+	for _, cluster := range clusters {
+		identities, err := client.ListHostNames(ctx, cluster)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, ClusterRow{Cluster: cluster, Hosts: len(identities)})
+	}
+	return Write(os.Stdout, *cmdOutput, ClusterHeader, rows)
+}
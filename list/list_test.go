@@ -0,0 +1,124 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package list
+
+import (
+	"bytes"
+	"context"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/retailnext/cassandrabackup/bucket"
+	"github.com/retailnext/cassandrabackup/digest"
+	"github.com/retailnext/cassandrabackup/manifests"
+	"github.com/retailnext/cassandrabackup/unixtime"
+)
+
+type fakeClient struct {
+	bucket.Client
+	hosts     []manifests.NodeIdentity
+	manifests []manifests.Manifest
+	sizes     map[digest.ForRestore]int64
+}
+
+func (c *fakeClient) ListHostNames(ctx context.Context, cluster string) ([]manifests.NodeIdentity, error) {
+	return c.hosts, nil
+}
+
+func (c *fakeClient) ListManifests(ctx context.Context, identity manifests.NodeIdentity, startAfter, notAfter unixtime.Seconds) (manifests.ManifestKeys, error) {
+	if identity != c.hosts[0] {
+		return nil, nil
+	}
+	var keys manifests.ManifestKeys
+	for _, m := range c.manifests {
+		keys = append(keys, m.Key())
+	}
+	return keys, nil
+}
+
+func (c *fakeClient) GetManifests(ctx context.Context, identity manifests.NodeIdentity, keys manifests.ManifestKeys) ([]manifests.Manifest, error) {
+	return c.manifests, nil
+}
+
+func (c *fakeClient) StatBlob(ctx context.Context, digests digest.ForRestore) (int64, error) {
+	size, ok := c.sizes[digests]
+	if !ok {
+		return 0, fs.ErrNotExist
+	}
+	return size, nil
+}
+
+func TestRows(t *testing.T) {
+	ctx := context.Background()
+	var a, b, missing digest.ForRestore
+	if err := a.UnmarshalText([]byte(strings.Repeat("C", 86) + "==")); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.UnmarshalText([]byte(strings.Repeat("B", 86) + "==")); err != nil {
+		t.Fatal(err)
+	}
+	identity := manifests.NodeIdentity{Cluster: "c1", Hostname: "h1"}
+	client := &fakeClient{
+		hosts: []manifests.NodeIdentity{identity, {Cluster: "c1", Hostname: "h2"}},
+		manifests: []manifests.Manifest{
+			{Time: 1790856000, ManifestType: manifests.ManifestTypeSnapshot, HostID: "id", Tokens: []string{"1", "2"},
+				DataFiles: map[string]digest.ForRestore{"a": a, "b": b},
+				FileInfo:  map[string]manifests.FileInfo{"a": {Size: 5}, "b": {Size: 25}}},
+			{Time: 1790859600, ManifestType: manifests.ManifestTypeIncremental, HostID: "id", Tokens: []string{"1", "2"},
+				DataFiles: map[string]digest.ForRestore{"a": a, "c": missing}},
+		},
+		sizes: map[digest.ForRestore]int64{a: 10, b: 20},
+	}
+
+	manifestRows, err := ManifestRows(ctx, client, identity, manifests.ManifestKeys{client.manifests[0].Key(), client.manifests[1].Key()}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := Write(&out, FormatCSV, ManifestHeader, manifestRows); err != nil {
+		t.Fatal(err)
+	}
+	expected := "cluster,hostname,time,type,host_id,tokens,files,size,bytes\n" +
+		"c1,h1,2026-10-01T12:00:00Z,snapshot,id,2,2,30,30\n" +
+		"c1,h1,2026-10-01T13:00:00Z,incremental,id,2,2,,10\n"
+	if out.String() != expected {
+		t.Errorf("unexpected csv:\n%s", out.String())
+	}
+
+	hostRows, err := HostRows(ctx, client, "c1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := Write(&out, FormatTable, HostHeader, hostRows); err != nil {
+		t.Fatal(err)
+	}
+	expected = "CLUSTER  HOSTNAME  MANIFESTS  LAST_SNAPSHOT         LAST_INCREMENTAL\n" +
+		"c1       h1        2          2026-10-01T12:00:00Z  2026-10-01T13:00:00Z\n" +
+		"c1       h2        0                                \n"
+	if out.String() != expected {
+		t.Errorf("unexpected table:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := Write(&out, FormatJSON, HostHeader, hostRows[1:]); err != nil {
+		t.Fatal(err)
+	}
+	expected = "[\n  {\n    \"cluster\": \"c1\",\n    \"hostname\": \"h2\",\n    \"manifests\": 0\n  }\n]\n"
+	if out.String() != expected {
+		t.Errorf("unexpected json:\n%s", out.String())
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package list
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+	FormatCSV   = "csv"
+)
+
+var Formats = []string{FormatTable, FormatJSON, FormatCSV}
+
+// Row is one record of a listing. JSON output encodes the row itself; table and CSV output use
+// Fields, in the order of the listing's header.
+type Row interface {
+	Fields() []string
+}
+
+// Write writes rows to w in format.
+func Write[T Row](w io.Writer, format string, header []string, rows []T) error {
+	switch format {
+	case FormatJSON:
+		if rows == nil {
+			rows = []T{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(rows)
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(header); err != nil {
+			return err
+		}
+		for _, row := range rows {
+			if err := cw.Write(row.Fields()); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	case FormatTable:
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		if _, err := fmt.Fprintln(tw, strings.ToUpper(strings.Join(header, "\t"))); err != nil {
+			return err
+		}
+		for _, row := range rows {
+			if _, err := fmt.Fprintln(tw, strings.Join(row.Fields(), "\t")); err != nil {
+				return err
+			}
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// formatTime formats t for table and CSV output, leaving it empty when it is unknown.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,266 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:generate go run github.com/mailru/easyjson/easyjson -disallow_unknown_fields $GOFILE
+
+package manifests
+
+import (
+	"fmt"
+
+	"github.com/mailru/easyjson/jlexer"
+	"github.com/mailru/easyjson/jwriter"
+	"github.com/retailnext/cassandrabackup/digest"
+	"github.com/retailnext/cassandrabackup/unixtime"
+)
+
+// FormatVersion is the version of the format manifests are written in.
+//
+// Version 1 manifests have no "version" field and map each file to its digest. Version 2 manifests map
+// each file to its digest, size, mtime and SSTable name, add up the files and their sizes, place the
+// host by data center and rack, and may reference a blob of the CQL schema and list the ring's peers.
+// Version 3 manifests also record the length each file's blob was uploaded with. Each version is
+// decoded strictly, so a manifest written by a newer version of the tool is rejected rather than
+// partly understood.
+const FormatVersion = 3
+
+//easyjson:json
+type manifestV1 struct {
+	Time         unixtime.Seconds             `json:"time"`
+	ManifestType ManifestType                 `json:"manifest_type"`
+	HostID       string                       `json:"host_id"`
+	Address      string                       `json:"address"`
+	Partitioner  string                       `json:"partitioner"`
+	Tokens       []string                     `json:"tokens"`
+	DataFiles    map[string]digest.ForRestore `json:"data_files"`
+}
+
+// manifestV2 is the layout of version 2 and 3 manifests.
+//
+//easyjson:json
+type manifestV2 struct {
+	Version      int                   `json:"version"`
+	ToolVersion  string                `json:"tool_version,omitempty"`
+	Time         unixtime.Seconds      `json:"time"`
+	ManifestType ManifestType          `json:"manifest_type"`
+	HostID       string                `json:"host_id"`
+	Address      string                `json:"address"`
+	Partitioner  string                `json:"partitioner"`
+	Tokens       []string              `json:"tokens"`
+	DataFiles    map[string]dataFileV2 `json:"data_files"`
+	Totals       totalsV2              `json:"totals"`
+	Schema       *digest.ForRestore    `json:"schema,omitempty"`
+	DataCenter   string                `json:"data_center,omitempty"`
+	Rack         string                `json:"rack,omitempty"`
+	Peers        []peerV2              `json:"peers,omitempty"`
+}
+
+//easyjson:json
+type peerV2 struct {
+	HostID     string   `json:"host_id"`
+	Address    string   `json:"address"`
+	DataCenter string   `json:"data_center"`
+	Rack       string   `json:"rack"`
+	Tokens     []string `json:"tokens"`
+}
+
+// dataFileV2 has no size when the manifest was made without one, such as when converting a version 1
+// manifest. BlobSize is only in version 3 manifests.
+//
+//easyjson:json
+type dataFileV2 struct {
+	Digest            digest.ForRestore `json:"digest"`
+	Size              *int64            `json:"size,omitempty"`
+	BlobSize          int64             `json:"blob_size,omitempty"`
+	MTime             unixtime.Seconds  `json:"mtime,omitempty"`
+	SSTableVersion    string            `json:"sstable_version,omitempty"`
+	SSTableGeneration string            `json:"sstable_generation,omitempty"`
+	SSTableFormat     string            `json:"sstable_format,omitempty"`
+	SSTableComponent  string            `json:"sstable_component,omitempty"`
+}
+
+// totalsV2 has no bytes unless every file has a size.
+//
+//easyjson:json
+type totalsV2 struct {
+	Files int    `json:"files"`
+	Bytes *int64 `json:"bytes,omitempty"`
+}
+
+func (m Manifest) toV2() manifestV2 {
+	v := manifestV2{
+		Version:      FormatVersion,
+		ToolVersion:  m.ToolVersion,
+		Time:         m.Time,
+		ManifestType: m.ManifestType,
+		HostID:       m.HostID,
+		Address:      m.Address,
+		Partitioner:  m.Partitioner,
+		Tokens:       m.Tokens,
+		DataFiles:    make(map[string]dataFileV2, len(m.DataFiles)),
+		Schema:       m.Schema,
+		DataCenter:   m.DataCenter,
+		Rack:         m.Rack,
+	}
+	for _, peer := range m.Peers {
+		v.Peers = append(v.Peers, peerV2(peer))
+	}
+	for name, file := range m.DataFiles {
+		entry := dataFileV2{Digest: file}
+		if info, ok := m.FileInfo[name]; ok {
+			size := info.Size
+			entry.Size = &size
+			entry.BlobSize = info.BlobSize
+			entry.MTime = info.MTime
+			entry.SSTableVersion = info.SSTable.Version
+			entry.SSTableGeneration = info.SSTable.Generation
+			entry.SSTableFormat = info.SSTable.Format
+			entry.SSTableComponent = info.SSTable.Component
+		}
+		v.DataFiles[name] = entry
+	}
+	files, bytes, known := m.Totals()
+	v.Totals.Files = files
+	if known {
+		v.Totals.Bytes = &bytes
+	}
+	return v
+}
+
+func (v manifestV1) manifest() Manifest {
+	return Manifest{
+		Time:         v.Time,
+		ManifestType: v.ManifestType,
+		HostID:       v.HostID,
+		Address:      v.Address,
+		Partitioner:  v.Partitioner,
+		Tokens:       v.Tokens,
+		DataFiles:    v.DataFiles,
+	}
+}
+
+func (v manifestV2) manifest() Manifest {
+	m := Manifest{
+		Time:         v.Time,
+		ManifestType: v.ManifestType,
+		HostID:       v.HostID,
+		Address:      v.Address,
+		Partitioner:  v.Partitioner,
+		Tokens:       v.Tokens,
+		DataFiles:    make(map[string]digest.ForRestore, len(v.DataFiles)),
+		ToolVersion:  v.ToolVersion,
+		Schema:       v.Schema,
+		DataCenter:   v.DataCenter,
+		Rack:         v.Rack,
+	}
+	for _, peer := range v.Peers {
+		m.Peers = append(m.Peers, Peer(peer))
+	}
+	for name, entry := range v.DataFiles {
+		m.DataFiles[name] = entry.Digest
+		if entry.Size == nil {
+			continue
+		}
+		if m.FileInfo == nil {
+			m.FileInfo = make(map[string]FileInfo, len(v.DataFiles))
+		}
+		m.FileInfo[name] = FileInfo{
+			Size:     *entry.Size,
+			BlobSize: entry.BlobSize,
+			MTime:    entry.MTime,
+			SSTable: SSTableName{
+				Version:    entry.SSTableVersion,
+				Generation: entry.SSTableGeneration,
+				Format:     entry.SSTableFormat,
+				Component:  entry.SSTableComponent,
+			},
+		}
+	}
+	return m
+}
+
+// MarshalEasyJSON writes the manifest in the current format version.
+func (m Manifest) MarshalEasyJSON(w *jwriter.Writer) {
+	m.toV2().MarshalEasyJSON(w)
+}
+
+func (m Manifest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	m.MarshalEasyJSON(&w)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// UnmarshalEasyJSON reads a manifest written in any supported format version.
+func (m *Manifest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	data := l.Raw()
+	if !l.Ok() {
+		return
+	}
+	version, err := formatVersion(data)
+	if err != nil {
+		l.AddError(err)
+		return
+	}
+	switch version {
+	case 0:
+		var v manifestV1
+		if err := v.UnmarshalJSON(data); err != nil {
+			l.AddError(err)
+			return
+		}
+		*m = v.manifest()
+	case 2, 3:
+		var v manifestV2
+		if err := v.UnmarshalJSON(data); err != nil {
+			l.AddError(err)
+			return
+		}
+		if version == 2 {
+			for name, entry := range v.DataFiles {
+				if entry.BlobSize != 0 {
+					l.AddError(fmt.Errorf("unexpected blob_size of %s in a version 2 manifest", name))
+					return
+				}
+			}
+		}
+		*m = v.manifest()
+	default:
+		l.AddError(fmt.Errorf("unsupported manifest format version %d", version))
+	}
+}
+
+func (m *Manifest) UnmarshalJSON(data []byte) error {
+	l := jlexer.Lexer{Data: data}
+	m.UnmarshalEasyJSON(&l)
+	return l.Error()
+}
+
+// formatVersion returns the "version" field of a manifest, which is 0 for version 1 manifests.
+func formatVersion(data []byte) (int, error) {
+	l := jlexer.Lexer{Data: data}
+	version := 0
+	l.Delim('{')
+	for !l.IsDelim('}') {
+		key := l.UnsafeFieldName(false)
+		l.WantColon()
+		if key == "version" {
+			version = l.Int()
+		} else {
+			l.SkipRecursive()
+		}
+		l.WantComma()
+	}
+	l.Delim('}')
+	return version, l.Error()
+}
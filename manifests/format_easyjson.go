@@ -0,0 +1,916 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package manifests
+
+import (
+	json "encoding/json"
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+	digest "github.com/retailnext/cassandrabackup/digest"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjson72863a49DecodeGithubComRetailnextCassandrabackupManifests(in *jlexer.Lexer, out *totalsV2) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "files":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Files = int(in.Int())
+			}
+		case "bytes":
+			if in.IsNull() {
+				in.Skip()
+				out.Bytes = nil
+			} else {
+				if out.Bytes == nil {
+					out.Bytes = new(int64)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					*out.Bytes = int64(in.Int64())
+				}
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson72863a49EncodeGithubComRetailnextCassandrabackupManifests(out *jwriter.Writer, in totalsV2) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"files\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Files))
+	}
+	if in.Bytes != nil {
+		const prefix string = ",\"bytes\":"
+		out.RawString(prefix)
+		out.Int64(int64(*in.Bytes))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v totalsV2) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson72863a49EncodeGithubComRetailnextCassandrabackupManifests(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v totalsV2) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson72863a49EncodeGithubComRetailnextCassandrabackupManifests(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *totalsV2) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson72863a49DecodeGithubComRetailnextCassandrabackupManifests(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *totalsV2) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson72863a49DecodeGithubComRetailnextCassandrabackupManifests(l, v)
+}
+func easyjson72863a49DecodeGithubComRetailnextCassandrabackupManifests1(in *jlexer.Lexer, out *peerV2) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "host_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.HostID = string(in.String())
+			}
+		case "address":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Address = string(in.String())
+			}
+		case "data_center":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.DataCenter = string(in.String())
+			}
+		case "rack":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Rack = string(in.String())
+			}
+		case "tokens":
+			if in.IsNull() {
+				in.Skip()
+				out.Tokens = nil
+			} else {
+				in.Delim('[')
+				if out.Tokens == nil {
+					if !in.IsDelim(']') {
+						out.Tokens = make([]string, 0, 4)
+					} else {
+						out.Tokens = []string{}
+					}
+				} else {
+					out.Tokens = (out.Tokens)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v1 = string(in.String())
+					}
+					out.Tokens = append(out.Tokens, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson72863a49EncodeGithubComRetailnextCassandrabackupManifests1(out *jwriter.Writer, in peerV2) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"host_id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.HostID))
+	}
+	{
+		const prefix string = ",\"address\":"
+		out.RawString(prefix)
+		out.String(string(in.Address))
+	}
+	{
+		const prefix string = ",\"data_center\":"
+		out.RawString(prefix)
+		out.String(string(in.DataCenter))
+	}
+	{
+		const prefix string = ",\"rack\":"
+		out.RawString(prefix)
+		out.String(string(in.Rack))
+	}
+	{
+		const prefix string = ",\"tokens\":"
+		out.RawString(prefix)
+		if in.Tokens == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v2, v3 := range in.Tokens {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v3))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v peerV2) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson72863a49EncodeGithubComRetailnextCassandrabackupManifests1(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v peerV2) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson72863a49EncodeGithubComRetailnextCassandrabackupManifests1(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *peerV2) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson72863a49DecodeGithubComRetailnextCassandrabackupManifests1(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *peerV2) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson72863a49DecodeGithubComRetailnextCassandrabackupManifests1(l, v)
+}
+func easyjson72863a49DecodeGithubComRetailnextCassandrabackupManifests2(in *jlexer.Lexer, out *manifestV2) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "version":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Version = int(in.Int())
+			}
+		case "tool_version":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ToolVersion = string(in.String())
+			}
+		case "time":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				(out.Time).UnmarshalEasyJSON(in)
+			}
+		case "manifest_type":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ManifestType = ManifestType(in.Int())
+			}
+		case "host_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.HostID = string(in.String())
+			}
+		case "address":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Address = string(in.String())
+			}
+		case "partitioner":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Partitioner = string(in.String())
+			}
+		case "tokens":
+			if in.IsNull() {
+				in.Skip()
+				out.Tokens = nil
+			} else {
+				in.Delim('[')
+				if out.Tokens == nil {
+					if !in.IsDelim(']') {
+						out.Tokens = make([]string, 0, 4)
+					} else {
+						out.Tokens = []string{}
+					}
+				} else {
+					out.Tokens = (out.Tokens)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v4 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v4 = string(in.String())
+					}
+					out.Tokens = append(out.Tokens, v4)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "data_files":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				out.DataFiles = make(map[string]dataFileV2)
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v5 dataFileV2
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v5).UnmarshalEasyJSON(in)
+					}
+					(out.DataFiles)[key] = v5
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		case "totals":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				(out.Totals).UnmarshalEasyJSON(in)
+			}
+		case "schema":
+			if in.IsNull() {
+				in.Skip()
+				out.Schema = nil
+			} else {
+				if out.Schema == nil {
+					out.Schema = new(digest.ForRestore)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					(*out.Schema).UnmarshalEasyJSON(in)
+				}
+			}
+		case "data_center":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.DataCenter = string(in.String())
+			}
+		case "rack":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Rack = string(in.String())
+			}
+		case "peers":
+			if in.IsNull() {
+				in.Skip()
+				out.Peers = nil
+			} else {
+				in.Delim('[')
+				if out.Peers == nil {
+					if !in.IsDelim(']') {
+						out.Peers = make([]peerV2, 0, 0)
+					} else {
+						out.Peers = []peerV2{}
+					}
+				} else {
+					out.Peers = (out.Peers)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v6 peerV2
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v6).UnmarshalEasyJSON(in)
+					}
+					out.Peers = append(out.Peers, v6)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson72863a49EncodeGithubComRetailnextCassandrabackupManifests2(out *jwriter.Writer, in manifestV2) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"version\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Version))
+	}
+	if in.ToolVersion != "" {
+		const prefix string = ",\"tool_version\":"
+		out.RawString(prefix)
+		out.String(string(in.ToolVersion))
+	}
+	{
+		const prefix string = ",\"time\":"
+		out.RawString(prefix)
+		(in.Time).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"manifest_type\":"
+		out.RawString(prefix)
+		out.Int(int(in.ManifestType))
+	}
+	{
+		const prefix string = ",\"host_id\":"
+		out.RawString(prefix)
+		out.String(string(in.HostID))
+	}
+	{
+		const prefix string = ",\"address\":"
+		out.RawString(prefix)
+		out.String(string(in.Address))
+	}
+	{
+		const prefix string = ",\"partitioner\":"
+		out.RawString(prefix)
+		out.String(string(in.Partitioner))
+	}
+	{
+		const prefix string = ",\"tokens\":"
+		out.RawString(prefix)
+		if in.Tokens == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v7, v8 := range in.Tokens {
+				if v7 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v8))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"data_files\":"
+		out.RawString(prefix)
+		if in.DataFiles == nil && (out.Flags&jwriter.NilMapAsEmpty) == 0 {
+			out.RawString(`null`)
+		} else {
+			out.RawByte('{')
+			v9First := true
+			for v9Name, v9Value := range in.DataFiles {
+				if v9First {
+					v9First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v9Name))
+				out.RawByte(':')
+				(v9Value).MarshalEasyJSON(out)
+			}
+			out.RawByte('}')
+		}
+	}
+	{
+		const prefix string = ",\"totals\":"
+		out.RawString(prefix)
+		(in.Totals).MarshalEasyJSON(out)
+	}
+	if in.Schema != nil {
+		const prefix string = ",\"schema\":"
+		out.RawString(prefix)
+		(*in.Schema).MarshalEasyJSON(out)
+	}
+	if in.DataCenter != "" {
+		const prefix string = ",\"data_center\":"
+		out.RawString(prefix)
+		out.String(string(in.DataCenter))
+	}
+	if in.Rack != "" {
+		const prefix string = ",\"rack\":"
+		out.RawString(prefix)
+		out.String(string(in.Rack))
+	}
+	if len(in.Peers) != 0 {
+		const prefix string = ",\"peers\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v10, v11 := range in.Peers {
+				if v10 > 0 {
+					out.RawByte(',')
+				}
+				(v11).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v manifestV2) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson72863a49EncodeGithubComRetailnextCassandrabackupManifests2(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v manifestV2) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson72863a49EncodeGithubComRetailnextCassandrabackupManifests2(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *manifestV2) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson72863a49DecodeGithubComRetailnextCassandrabackupManifests2(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *manifestV2) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson72863a49DecodeGithubComRetailnextCassandrabackupManifests2(l, v)
+}
+func easyjson72863a49DecodeGithubComRetailnextCassandrabackupManifests3(in *jlexer.Lexer, out *manifestV1) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "time":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				(out.Time).UnmarshalEasyJSON(in)
+			}
+		case "manifest_type":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ManifestType = ManifestType(in.Int())
+			}
+		case "host_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.HostID = string(in.String())
+			}
+		case "address":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Address = string(in.String())
+			}
+		case "partitioner":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Partitioner = string(in.String())
+			}
+		case "tokens":
+			if in.IsNull() {
+				in.Skip()
+				out.Tokens = nil
+			} else {
+				in.Delim('[')
+				if out.Tokens == nil {
+					if !in.IsDelim(']') {
+						out.Tokens = make([]string, 0, 4)
+					} else {
+						out.Tokens = []string{}
+					}
+				} else {
+					out.Tokens = (out.Tokens)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v12 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v12 = string(in.String())
+					}
+					out.Tokens = append(out.Tokens, v12)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "data_files":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				out.DataFiles = make(map[string]digest.ForRestore)
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v13 digest.ForRestore
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v13).UnmarshalEasyJSON(in)
+					}
+					(out.DataFiles)[key] = v13
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson72863a49EncodeGithubComRetailnextCassandrabackupManifests3(out *jwriter.Writer, in manifestV1) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"time\":"
+		out.RawString(prefix[1:])
+		(in.Time).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"manifest_type\":"
+		out.RawString(prefix)
+		out.Int(int(in.ManifestType))
+	}
+	{
+		const prefix string = ",\"host_id\":"
+		out.RawString(prefix)
+		out.String(string(in.HostID))
+	}
+	{
+		const prefix string = ",\"address\":"
+		out.RawString(prefix)
+		out.String(string(in.Address))
+	}
+	{
+		const prefix string = ",\"partitioner\":"
+		out.RawString(prefix)
+		out.String(string(in.Partitioner))
+	}
+	{
+		const prefix string = ",\"tokens\":"
+		out.RawString(prefix)
+		if in.Tokens == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v14, v15 := range in.Tokens {
+				if v14 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v15))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"data_files\":"
+		out.RawString(prefix)
+		if in.DataFiles == nil && (out.Flags&jwriter.NilMapAsEmpty) == 0 {
+			out.RawString(`null`)
+		} else {
+			out.RawByte('{')
+			v16First := true
+			for v16Name, v16Value := range in.DataFiles {
+				if v16First {
+					v16First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v16Name))
+				out.RawByte(':')
+				(v16Value).MarshalEasyJSON(out)
+			}
+			out.RawByte('}')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v manifestV1) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson72863a49EncodeGithubComRetailnextCassandrabackupManifests3(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v manifestV1) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson72863a49EncodeGithubComRetailnextCassandrabackupManifests3(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *manifestV1) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson72863a49DecodeGithubComRetailnextCassandrabackupManifests3(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *manifestV1) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson72863a49DecodeGithubComRetailnextCassandrabackupManifests3(l, v)
+}
+func easyjson72863a49DecodeGithubComRetailnextCassandrabackupManifests4(in *jlexer.Lexer, out *dataFileV2) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "digest":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				(out.Digest).UnmarshalEasyJSON(in)
+			}
+		case "size":
+			if in.IsNull() {
+				in.Skip()
+				out.Size = nil
+			} else {
+				if out.Size == nil {
+					out.Size = new(int64)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					*out.Size = int64(in.Int64())
+				}
+			}
+		case "blob_size":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.BlobSize = int64(in.Int64())
+			}
+		case "mtime":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				(out.MTime).UnmarshalEasyJSON(in)
+			}
+		case "sstable_version":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.SSTableVersion = string(in.String())
+			}
+		case "sstable_generation":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.SSTableGeneration = string(in.String())
+			}
+		case "sstable_format":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.SSTableFormat = string(in.String())
+			}
+		case "sstable_component":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.SSTableComponent = string(in.String())
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson72863a49EncodeGithubComRetailnextCassandrabackupManifests4(out *jwriter.Writer, in dataFileV2) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"digest\":"
+		out.RawString(prefix[1:])
+		(in.Digest).MarshalEasyJSON(out)
+	}
+	if in.Size != nil {
+		const prefix string = ",\"size\":"
+		out.RawString(prefix)
+		out.Int64(int64(*in.Size))
+	}
+	if in.BlobSize != 0 {
+		const prefix string = ",\"blob_size\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.BlobSize))
+	}
+	if in.MTime != 0 {
+		const prefix string = ",\"mtime\":"
+		out.RawString(prefix)
+		(in.MTime).MarshalEasyJSON(out)
+	}
+	if in.SSTableVersion != "" {
+		const prefix string = ",\"sstable_version\":"
+		out.RawString(prefix)
+		out.String(string(in.SSTableVersion))
+	}
+	if in.SSTableGeneration != "" {
+		const prefix string = ",\"sstable_generation\":"
+		out.RawString(prefix)
+		out.String(string(in.SSTableGeneration))
+	}
+	if in.SSTableFormat != "" {
+		const prefix string = ",\"sstable_format\":"
+		out.RawString(prefix)
+		out.String(string(in.SSTableFormat))
+	}
+	if in.SSTableComponent != "" {
+		const prefix string = ",\"sstable_component\":"
+		out.RawString(prefix)
+		out.String(string(in.SSTableComponent))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v dataFileV2) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson72863a49EncodeGithubComRetailnextCassandrabackupManifests4(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v dataFileV2) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson72863a49EncodeGithubComRetailnextCassandrabackupManifests4(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *dataFileV2) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson72863a49DecodeGithubComRetailnextCassandrabackupManifests4(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *dataFileV2) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson72863a49DecodeGithubComRetailnextCassandrabackupManifests4(l, v)
+}
@@ -34,6 +34,10 @@ func (k ManifestKey) FileName() string {
 	return fmt.Sprintf("%020d.%d.json", k.Time, k.ManifestType)
 }
 
+func (k ManifestKey) String() string {
+	return fmt.Sprintf("%s %s", k.Time, k.ManifestType)
+}
+
 func (k *ManifestKey) PopulateFromFileName(name string) error {
 	parts := strings.Split(name, ".")
 	if len(parts) != 3 {
@@ -80,3 +84,14 @@ func (s ManifestKeys) Less(i, j int) bool {
 func (s ManifestKeys) Swap(i, j int) {
 	s[i], s[j] = s[j], s[i]
 }
+
+// FromLatestSnapshot returns the latest snapshot in the sorted keys and every manifest after it,
+// which are what a restore to the time of the last key uses. Without a snapshot keys are returned as is.
+func (s ManifestKeys) FromLatestSnapshot() ManifestKeys {
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i].ManifestType == ManifestTypeSnapshot {
+			return s[i:]
+		}
+	}
+	return s
+}
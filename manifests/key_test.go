@@ -0,0 +1,40 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifests
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestFromLatestSnapshot(t *testing.T) {
+	keys := ManifestKeys{
+		{Time: 100, ManifestType: ManifestTypeSnapshot},
+		{Time: 200, ManifestType: ManifestTypeIncremental},
+		{Time: 300, ManifestType: ManifestTypeSnapshot},
+		{Time: 400, ManifestType: ManifestTypeIncomplete},
+		{Time: 500, ManifestType: ManifestTypeIncremental},
+	}
+	if diff := deep.Equal(keys.FromLatestSnapshot(), keys[2:]); diff != nil {
+		t.Error(diff)
+	}
+	if diff := deep.Equal(keys[:2].FromLatestSnapshot(), keys[:2]); diff != nil {
+		t.Error(diff)
+	}
+	if diff := deep.Equal(keys[3:].FromLatestSnapshot(), keys[3:]); diff != nil {
+		t.Error(diff)
+	}
+}
@@ -12,11 +12,11 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-//go:generate go run github.com/mailru/easyjson/easyjson -disallow_unknown_fields $GOFILE
-
 package manifests
 
 import (
+	"fmt"
+
 	"github.com/retailnext/cassandrabackup/digest"
 	"github.com/retailnext/cassandrabackup/unixtime"
 )
@@ -30,15 +30,83 @@ const (
 	ManifestTypeIncremental ManifestType = 3
 )
 
-//easyjson:json
+func (t ManifestType) String() string {
+	switch t {
+	case ManifestTypeSnapshot:
+		return "snapshot"
+	case ManifestTypeIncomplete:
+		return "incomplete"
+	case ManifestTypeIncremental:
+		return "incremental"
+	default:
+		return fmt.Sprintf("invalid(%d)", int(t))
+	}
+}
+
+// Manifest describes a backup of a host. It is stored in the format described in format.go.
 type Manifest struct {
-	Time         unixtime.Seconds             `json:"time"`
-	ManifestType ManifestType                 `json:"manifest_type"`
-	HostID       string                       `json:"host_id"`
-	Address      string                       `json:"address"`
-	Partitioner  string                       `json:"partitioner"`
-	Tokens       []string                     `json:"tokens"`
-	DataFiles    map[string]digest.ForRestore `json:"data_files"`
+	Time         unixtime.Seconds
+	ManifestType ManifestType
+	HostID       string
+	Address      string
+	Partitioner  string
+	Tokens       []string
+	DataFiles    map[string]digest.ForRestore
+	// FileInfo describes the files in DataFiles as they were when they were backed up.
+	// Manifests written before format version 2 have none.
+	FileInfo map[string]FileInfo
+	// ToolVersion is the version of cassandrabackup that wrote the manifest, if known.
+	ToolVersion string
+	// Schema is the blob of the CQL schema at the time of a snapshot, if it was backed up.
+	Schema *digest.ForRestore
+	// DataCenter and Rack place the host in its cluster. Manifests written before format version 2
+	// don't have them.
+	DataCenter string
+	Rack       string
+	// Peers are the other nodes of the ring as the host saw them when a snapshot was taken.
+	Peers []Peer
+}
+
+// Peer is a node of the ring other than the one a manifest is for.
+type Peer struct {
+	HostID     string
+	Address    string
+	DataCenter string
+	Rack       string
+	Tokens     []string
+}
+
+// FileInfo is what a manifest records about a file besides its digest.
+type FileInfo struct {
+	Size int64
+	// BlobSize is the length of the file's blob as uploaded, after any compression and before any
+	// sealing, or 0 if the manifest doesn't record it.
+	BlobSize int64
+	MTime    unixtime.Seconds
+	// SSTable is the parsed name of the file, if it is an SSTable component.
+	SSTable SSTableName
+}
+
+// Totals adds up the manifest's files. known is false if the manifest doesn't record the size of every file.
+func (m Manifest) Totals() (files int, bytes int64, known bool) {
+	known = true
+	for name := range m.DataFiles {
+		info, ok := m.FileInfo[name]
+		if !ok {
+			known = false
+		}
+		bytes += info.Size
+	}
+	return len(m.DataFiles), bytes, known
+}
+
+// Size returns the recorded size of the named file, or nil if the manifest doesn't record it.
+func (m Manifest) Size(name string) *int64 {
+	info, ok := m.FileInfo[name]
+	if !ok {
+		return nil
+	}
+	return &info.Size
 }
 
 func (m Manifest) Key() ManifestKey {
@@ -47,3 +115,18 @@ func (m Manifest) Key() ManifestKey {
 		ManifestType: m.ManifestType,
 	}
 }
+
+// Blobs returns the distinct blobs that the manifests reference.
+func Blobs(list []Manifest) []digest.ForRestore {
+	seen := make(map[digest.ForRestore]struct{})
+	var result []digest.ForRestore
+	for _, m := range list {
+		for _, file := range m.DataFiles {
+			if _, ok := seen[file]; !ok {
+				seen[file] = struct{}{}
+				result = append(result, file)
+			}
+		}
+	}
+	return result
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"crypto/rand"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/go-test/deep"
@@ -58,6 +59,7 @@ func TestManifest(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	schema := dgst.ForRestore()
 	m1 := Manifest{
 		Time:         unixtime.Now(),
 		ManifestType: ManifestTypeIncremental,
@@ -66,7 +68,23 @@ func TestManifest(t *testing.T) {
 		Partitioner:  "bazboo",
 		Tokens:       []string{"-1", "1"},
 		DataFiles: map[string]digest.ForRestore{
-			tempFileName: dgst.ForRestore(),
+			tempFileName:              dgst.ForRestore(),
+			"ks/t-1/nb-1-big-Data.db": dgst.ForRestore(),
+		},
+		FileInfo: map[string]FileInfo{
+			"ks/t-1/nb-1-big-Data.db": {
+				Size:     1024,
+				BlobSize: 512,
+				MTime:    1790856000,
+				SSTable:  SSTableName{Version: "nb", Generation: "1", Format: "big", Component: "Data.db"},
+			},
+		},
+		ToolVersion: "v1.2.3",
+		Schema:      &schema,
+		DataCenter:  "dc1",
+		Rack:        "rack1",
+		Peers: []Peer{
+			{HostID: "peer", Address: "127.0.0.3", DataCenter: "dc1", Rack: "rack2", Tokens: []string{"0"}},
 		},
 	}
 
@@ -85,3 +103,93 @@ func TestManifest(t *testing.T) {
 		t.Fatal(diff)
 	}
 }
+
+func TestManifestVersions(t *testing.T) {
+	var d digest.ForRestore
+	if err := d.UnmarshalText([]byte(strings.Repeat("C", 86) + "==")); err != nil {
+		t.Fatal(err)
+	}
+	text, err := d.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v1 := `{"time":"2026-10-01T12:00:00Z","manifest_type":1,"host_id":"h","address":"a","partitioner":"p","tokens":["1"],"data_files":{"f":"` + string(text) + `"}}`
+	var m Manifest
+	if err := easyjson.Unmarshal([]byte(v1), &m); err != nil {
+		t.Fatal(err)
+	}
+	expected := Manifest{
+		Time:         1790856000,
+		ManifestType: ManifestTypeSnapshot,
+		HostID:       "h",
+		Address:      "a",
+		Partitioner:  "p",
+		Tokens:       []string{"1"},
+		DataFiles:    map[string]digest.ForRestore{"f": d},
+	}
+	if diff := deep.Equal(m, expected); diff != nil {
+		t.Error(diff)
+	}
+	if files, _, known := m.Totals(); files != 1 || known {
+		t.Errorf("unexpected totals for a version 1 manifest: %d %v", files, known)
+	}
+
+	// Version 1 manifests are written back in the current version, without sizes.
+	v3, err := easyjson.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(v3), `"version":3`) || !strings.Contains(string(v3), `"totals":{"files":1}`) {
+		t.Errorf("unexpected version 3 manifest %s", v3)
+	}
+	var roundTripped Manifest
+	if err := easyjson.Unmarshal(v3, &roundTripped); err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal(roundTripped, expected); diff != nil {
+		t.Error(diff)
+	}
+
+	// Version 2 manifests are still read.
+	v2 := `{"version":2,"time":"2026-10-01T12:00:00Z","manifest_type":1,"data_files":{"f":{"digest":"` + string(text) + `","size":10}}}`
+	if err := easyjson.Unmarshal([]byte(v2), &m); err != nil {
+		t.Fatal(err)
+	}
+	if info := m.FileInfo["f"]; info.Size != 10 || info.BlobSize != 0 {
+		t.Errorf("unexpected file info from a version 2 manifest: %+v", info)
+	}
+
+	for _, invalid := range []string{
+		`{"version":4,"time":"2026-10-01T12:00:00Z"}`,
+		`{"version":2,"data_files":{"f":{"digest":"` + string(text) + `","size":10,"blob_size":5}}}`,
+		`{"version":2,"time":"2026-10-01T12:00:00Z","unknown":1}`,
+		`{"time":"2026-10-01T12:00:00Z","size":1}`,
+		`[]`,
+	} {
+		if err := easyjson.Unmarshal([]byte(invalid), &m); err == nil {
+			t.Errorf("expected an error decoding %s", invalid)
+		}
+	}
+}
+
+func TestParseSSTableName(t *testing.T) {
+	for name, expected := range map[string]SSTableName{
+		"ks/t-1/nb-1-big-Data.db": {Version: "nb", Generation: "1", Format: "big", Component: "Data.db"},
+		"ks/t-1/.idx/da-3gbg_0w5o_4uc1s2ek1cn1r39byd-bti-Partitions.db": {Version: "da", Generation: "3gbg_0w5o_4uc1s2ek1cn1r39byd", Format: "bti", Component: "Partitions.db"},
+		"ks/t/ks-t-ka-12-Index.db":                                      {Version: "ka", Generation: "12", Component: "Index.db"},
+	} {
+		got, ok := ParseSSTableName(name)
+		if !ok {
+			t.Errorf("expected %s to parse", name)
+		}
+		if diff := deep.Equal(got, expected); diff != nil {
+			t.Error(name, diff)
+		}
+	}
+	for _, name := range []string{"ks/t-1/manifest.json", "ks/t-1/schema.cql", "nb-1-big-", "a-b-c-d"} {
+		if _, ok := ParseSSTableName(name); ok {
+			t.Errorf("expected %s not to parse", name)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifests
+
+import (
+	"path"
+	"strings"
+)
+
+// SSTableName is the parsed name of an SSTable component file.
+type SSTableName struct {
+	// Version is the SSTable version, such as "nb".
+	Version string
+	// Generation is a number, or an identifier like "3gbg_0w5o_4uc1s2ek1cn1r39byd" since Cassandra 4.1.
+	Generation string
+	// Format is "big" or "bti", or empty for the names Cassandra 2.x used, which don't include it.
+	Format string
+	// Component is the kind of file, such as "Data.db".
+	Component string
+}
+
+var sstableFormats = map[string]bool{"big": true, "bti": true}
+
+// ParseSSTableName parses the base of name as "version-generation-format-Component" or, as written
+// by Cassandra 2.x, "keyspace-table-version-generation-Component".
+func ParseSSTableName(name string) (SSTableName, bool) {
+	parts := strings.Split(path.Base(name), "-")
+	n := len(parts)
+	switch {
+	case n == 4 && sstableFormats[parts[2]]:
+		return SSTableName{Version: parts[0], Generation: parts[1], Format: parts[2], Component: parts[3]}, validSSTableName(parts)
+	case n == 5:
+		return SSTableName{Version: parts[2], Generation: parts[3], Component: parts[4]}, validSSTableName(parts[2:])
+	}
+	return SSTableName{}, false
+}
+
+func validSSTableName(parts []string) bool {
+	for _, part := range parts {
+		if part == "" {
+			return false
+		}
+	}
+	return strings.ContainsRune(parts[len(parts)-1], '.')
+}
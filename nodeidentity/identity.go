@@ -66,6 +66,7 @@ func GetIdentityAndManifestTemplateOffline(overrideCluster, overrideHostname *st
 		Address:     cfg.IPForClients(),
 		Partitioner: cfg.Partitioner,
 		Tokens:      cfg.Tokens(),
+		ToolVersion: manifests.ToolVersion,
 	}
 	return identity, template, nil
 }
@@ -90,6 +91,8 @@ func GetIdentityAndManifestTemplate(overrideCluster, overrideHostname *string) (
 	}
 
 	template.HostID = info.HostID
+	template.DataCenter = info.DataCenter
+	template.Rack = info.Rack
 	if len(template.Tokens) == 0 {
 		template.Tokens = info.Tokens
 	} else {
@@ -106,6 +109,25 @@ func GetIdentityAndManifestTemplate(overrideCluster, overrideHostname *string) (
 	return identity, template, nil
 }
 
+// GetPeers returns the other nodes of the ring as the node at addr sees them.
+func GetPeers(addr string) ([]manifests.Peer, error) {
+	infos, err := systemlocal.GetPeers(addr)
+	if err != nil {
+		return nil, err
+	}
+	peers := make([]manifests.Peer, 0, len(infos))
+	for _, info := range infos {
+		peers = append(peers, manifests.Peer{
+			HostID:     info.HostID,
+			Address:    info.Address,
+			DataCenter: info.DataCenter,
+			Rack:       info.Rack,
+			Tokens:     info.Tokens,
+		})
+	}
+	return peers, nil
+}
+
 var getHostname = func() string {
 	name, err := os.Hostname()
 	if err != nil {
@@ -16,7 +16,9 @@ package nodeidentity
 
 import (
 	"context"
+	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/retailnext/cassandrabackup/bucket"
@@ -47,6 +49,18 @@ func ForRestore(ctx context.Context, overrideCluster, overrideHostname, override
 	lgr := zap.S()
 	expr := hostnameExprForThisHost(overridePattern)
 	filtered := ForRestoreMatchingRegexp(ctx, result.Cluster, expr)
+	if len(filtered) == 0 {
+		// A replacement node usually has a new hostname but is configured with the tokens of the
+		// node it replaces.
+		if tokens := getTokens(); len(tokens) > 0 {
+			lgr.Infow("no_host_matching_hostname", "pattern", expr.String(), "matching", "tokens")
+			identity, err := ForRestoreMatching(ctx, result.Cluster, Match{Tokens: tokens})
+			if err != nil {
+				lgr.Panicw("failed_to_find_host", "tokens", tokens, "err", err)
+			}
+			return identity
+		}
+	}
 	if len(filtered) != 1 {
 		lgr.Panicw("failed_to_find_host", "pattern", overridePattern, "found", filtered)
 	}
@@ -71,6 +85,94 @@ func ForRestoreMatchingRegexp(ctx context.Context, cluster string, expr *regexp.
 	return filtered
 }
 
+// Match selects the host to restore from by what its manifests record. Empty fields match any host.
+type Match struct {
+	HostID  string
+	Address string
+	// Tokens must be sorted, as they are in manifests.
+	Tokens []string
+}
+
+func (m Match) String() string {
+	var parts []string
+	if m.HostID != "" {
+		parts = append(parts, "host_id="+m.HostID)
+	}
+	if m.Address != "" {
+		parts = append(parts, "address="+m.Address)
+	}
+	if len(m.Tokens) > 0 {
+		parts = append(parts, fmt.Sprintf("tokens=%d", len(m.Tokens)))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Matches returns whether the manifest is for a host with the host ID, address and tokens of m.
+func (m Match) Matches(manifest manifests.Manifest) bool {
+	if m.HostID != "" && m.HostID != manifest.HostID {
+		return false
+	}
+	if m.Address != "" && m.Address != manifest.Address {
+		return false
+	}
+	if len(m.Tokens) > 0 && !slices.Equal(m.Tokens, manifest.Tokens) {
+		return false
+	}
+	return true
+}
+
+// ForRestoreMatching returns the one host in the cluster whose latest manifest matches. It reads a
+// manifest of every host in the cluster, so it is slower than selecting by hostname.
+func ForRestoreMatching(ctx context.Context, cluster string, match Match) (manifests.NodeIdentity, error) {
+	lgr := zap.S()
+	client := bucket.OpenShared()
+
+	nodes, err := client.ListHostNames(ctx, cluster)
+	if err != nil {
+		return manifests.NodeIdentity{}, err
+	}
+	var found []manifests.NodeIdentity
+	for _, node := range nodes {
+		keys, err := client.ListManifests(ctx, node, 0, 0)
+		if err != nil {
+			return manifests.NodeIdentity{}, err
+		}
+		if len(keys) == 0 {
+			continue
+		}
+		latest, err := client.GetManifests(ctx, node, keys[len(keys)-1:])
+		if err != nil {
+			return manifests.NodeIdentity{}, err
+		}
+		if match.Matches(latest[0]) {
+			found = append(found, node)
+		}
+	}
+	if len(found) != 1 {
+		return manifests.NodeIdentity{}, fmt.Errorf("found %d hosts in cluster %q matching %s: %v", len(found), cluster, match, found)
+	}
+	lgr.Infow("selected_host", "match", match.String(), "found", found[0])
+	return found[0], nil
+}
+
+// ConfiguredTokens returns the initial_token of cassandra.yaml, sorted, or nil if it isn't set.
+func ConfiguredTokens() []string {
+	return getTokens()
+}
+
+var getTokens = func() []string {
+	raw, err := cassandraconfig.Load()
+	if err != nil {
+		zap.S().Panicw("config_load_error", "err", err)
+	}
+	return raw.Tokens()
+}
+
+// ConfiguredCluster returns the cluster_name of cassandra.yaml.
+func ConfiguredCluster() string {
+	return getCluster()
+}
+
 func getCluster() string {
 	raw, err := cassandraconfig.Load()
 	if err != nil {
@@ -0,0 +1,42 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodeidentity
+
+import (
+	"testing"
+
+	"github.com/retailnext/cassandrabackup/manifests"
+)
+
+func TestMatch(t *testing.T) {
+	m := manifests.Manifest{HostID: "id1", Address: "10.0.0.1", Tokens: []string{"-10", "10"}}
+	for _, c := range []struct {
+		match    Match
+		expected bool
+	}{
+		{Match{HostID: "id1"}, true},
+		{Match{HostID: "id2"}, false},
+		{Match{Address: "10.0.0.1"}, true},
+		{Match{Address: "10.0.0.2"}, false},
+		{Match{Tokens: []string{"-10", "10"}}, true},
+		{Match{Tokens: []string{"-10"}}, false},
+		{Match{HostID: "id1", Address: "10.0.0.2"}, false},
+		{Match{HostID: "id1", Address: "10.0.0.1", Tokens: []string{"-10", "10"}}, true},
+	} {
+		if got := c.match.Matches(m); got != c.expected {
+			t.Errorf("%s: expected %v got %v", c.match, c.expected, got)
+		}
+	}
+}
@@ -14,7 +14,10 @@
 
 package paranoid
 
-import "os"
+import (
+	"os"
+	"time"
+)
 
 func NewFileFromInfo(name string, info os.FileInfo) File {
 	file := File{
@@ -45,6 +48,10 @@ func (f File) Len() int64 {
 	return f.fingerprint.size
 }
 
+func (f File) ModTime() time.Time {
+	return time.Unix(f.fingerprint.mtime.Unix())
+}
+
 // Remove a file only if it matches.
 // Returns a non-nil error if the file exits and doesn't match, or if os.Remove fails for a non-NotExist reason.
 func (f File) Delete() error {
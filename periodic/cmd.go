@@ -0,0 +1,28 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package periodic
+
+import "github.com/retailnext/cassandrabackup/backup"
+
+var (
+	snapshotSchedule     = backup.RunCmd.Flag("snapshot-schedule", "When to make snapshot backups, "+ScheduleHelp).Default("1h").String()
+	snapshotJitter       = backup.RunCmd.Flag("snapshot-jitter", "Delay each scheduled snapshot backup by a random duration up to this").Duration()
+	snapshotBlackouts    = backup.RunCmd.Flag("snapshot-blackout", "Do not start snapshot backups during this window, "+WindowHelp).Strings()
+	incrementalSchedule  = backup.RunCmd.Flag("incremental-schedule", "When to make incremental backups, "+ScheduleHelp).Default("5m").String()
+	incrementalJitter    = backup.RunCmd.Flag("incremental-jitter", "Delay each scheduled incremental backup by a random duration up to this").Duration()
+	incrementalBlackouts = backup.RunCmd.Flag("incremental-blackout", "Do not start incremental backups during this window, "+WindowHelp).Strings()
+	throttlePath         = backup.RunCmd.Flag("throttle-path", "Path on the metrics listener that shows the rate limits, and changes them with a POST such as upload=20MiB. The listener is unauthenticated, so only set this where it is trusted.").String()
+	triggerPath          = backup.RunCmd.Flag("trigger-path", "Path on the metrics listener where a POST with ?type=snapshot or ?type=incremental starts a backup, regardless of schedule and blackouts. The listener is unauthenticated, so only set this where it is trusted.").String()
+)
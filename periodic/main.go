@@ -16,74 +16,179 @@ package periodic
 
 import (
 	"context"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
 	"time"
 
 	"github.com/retailnext/cassandrabackup/backup"
+	"github.com/retailnext/cassandrabackup/throttle"
 	"go.uber.org/zap"
 )
 
 const (
-	snapshotEvery    = 1 * time.Hour
-	incrementalEvery = 5 * time.Minute
+	backupTypeSnapshot    = "snapshot"
+	backupTypeIncremental = "incremental"
 )
 
-func Main(ctx context.Context) error {
+// job is one type of backup on its schedule.
+type job struct {
+	backupType string
+	run        func(ctx context.Context) error
+	schedule   Schedule
+	jitter     time.Duration
+	blackouts  Blackouts
+
+	lastAt     time.Time
+	dueAt      time.Time
+	inBlackout bool
+}
+
+func newJob(backupType string, run func(ctx context.Context) error, schedule string, jitter time.Duration, blackouts []string) (*job, error) {
+	j := &job{
+		backupType: backupType,
+		run:        run,
+		jitter:     jitter,
+	}
+	var err error
+	if j.schedule, err = ParseSchedule(schedule); err != nil {
+		return nil, err
+	}
+	if j.blackouts, err = ParseBlackouts(blackouts); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+func (j *job) plan() {
+	j.dueAt = j.schedule.Next(j.lastAt)
+	if j.jitter > 0 && !j.dueAt.IsZero() {
+		j.dueAt = j.dueAt.Add(rand.N(j.jitter))
+	}
+	if j.dueAt.IsZero() {
+		nextAtGauges.WithLabelValues(j.backupType).Set(0)
+		zap.S().Infow("next_backup_due", "type", j.backupType, "due_at", "now")
+		return
+	}
+	nextAtGauges.WithLabelValues(j.backupType).Set(float64(j.dueAt.Unix()))
+	zap.S().Infow("next_backup_due", "type", j.backupType, "last_at", j.lastAt, "due_at", j.dueAt)
+}
+
+// ready reports whether the job is due and outside its blackout windows.
+func (j *job) ready(now time.Time) bool {
+	if now.Before(j.dueAt) {
+		return false
+	}
+	inBlackout := j.blackouts.Contains(now)
+	if inBlackout != j.inBlackout {
+		j.inBlackout = inBlackout
+		zap.S().Infow("backup_blackout", "type", j.backupType, "in_blackout", inBlackout)
+	}
+	return !inBlackout
+}
+
+// Main makes backups on their schedules until ctx is done. listenAddress and telemetryPath are those
+// of the metrics listener, which the paths to trigger backups and change rate limits are served on.
+func Main(ctx context.Context, listenAddress, telemetryPath string) error {
 	registerMetrics()
-	lgr := zap.S()
+	if err := checkHandlerPath("trigger-path", *triggerPath, listenAddress, telemetryPath); err != nil {
+		return err
+	}
+	if err := checkHandlerPath("throttle-path", *throttlePath, listenAddress, telemetryPath); err != nil {
+		return err
+	}
+	if *throttlePath != "" && *throttlePath == *triggerPath {
+		return fmt.Errorf("--throttle-path can't be the same as --trigger-path %s", *throttlePath)
+	}
+
+	incremental, err := newJob(backupTypeIncremental, backup.DoIncremental, *incrementalSchedule, *incrementalJitter, *incrementalBlackouts)
+	if err != nil {
+		return err
+	}
+	snapshot, err := newJob(backupTypeSnapshot, backup.DoSnapshotBackup, *snapshotSchedule, *snapshotJitter, *snapshotBlackouts)
+	if err != nil {
+		return err
+	}
+	// Incrementals take priority when both are due, since they're quick.
+	jobs := []*job{incremental, snapshot}
+	byType := map[string]*job{
+		backupTypeIncremental: incremental,
+		backupTypeSnapshot:    snapshot,
+	}
+
+	st := openState()
+	for _, j := range jobs {
+		if j.lastAt, err = st.lastAt(j.backupType); err != nil {
+			return err
+		}
+		if !j.lastAt.IsZero() {
+			lastBackupAtGauges.WithLabelValues(j.backupType).Set(float64(j.lastAt.Unix()))
+		}
+		keepState(st, j, time.Now())
+		j.plan()
+	}
+
+	t := newTriggers(backupTypeIncremental, backupTypeSnapshot)
+	if *triggerPath != "" {
+		http.Handle(*triggerPath, t)
+	}
+	if *throttlePath != "" {
+		http.Handle(*throttlePath, throttle.Handler)
+	}
 
-	var lastSnapshotAt time.Time
-	var lastIncrementalAt time.Time
 	everyMinute := time.NewTicker(time.Minute)
 	defer everyMinute.Stop()
 	doneCh := ctx.Done()
 
-	var err error
-DONE:
 	for {
 		select {
 		case <-doneCh:
-			err = ctx.Err()
-			break DONE
+			return ctx.Err()
+		case backupType := <-t.received():
+			t.done(backupType)
+			runJob(ctx, st, byType[backupType])
 		case <-everyMinute.C:
-		}
-
-		now := time.Now()
-		if lastIncrementalAt.Before(now.Add(-incrementalEvery)) {
-			backupInProgressGauges.WithLabelValues("incremental").Set(1)
-			lgr.Infow("starting_backup", "type", "incremental")
-			err = backup.DoIncremental(ctx)
-			backupInProgressGauges.WithLabelValues("incremental").Set(0)
-			now = time.Now()
-			if err == nil {
-				lastIncrementalAt = now
-				lastBackupAtGauges.WithLabelValues("incremental").Set(float64(now.Unix()))
-				lastBackupOkGauges.WithLabelValues("incremental").Set(1)
-				backupCompletedCounters.WithLabelValues("incremental").Inc()
-				lgr.Infow("backup_complete", "type", "incremental")
-
-			} else {
-				lastBackupOkGauges.WithLabelValues("incremental").Set(0)
-				lgr.Errorw("backup_error", "type", "incremental", "err", err)
-				backupErrorCounters.WithLabelValues("incremental").Inc()
+			now := time.Now()
+			for _, j := range jobs {
+				keepState(st, j, now)
 			}
-		} else if lastSnapshotAt.Before(now.Add(-snapshotEvery)) {
-			backupInProgressGauges.WithLabelValues("snapshot").Set(1)
-			lgr.Infow("starting_backup", "type", "snapshot")
-			err = backup.DoSnapshotBackup(ctx)
-			backupInProgressGauges.WithLabelValues("snapshot").Set(0)
-			now = time.Now()
-			if err == nil {
-				lastSnapshotAt = now
-				lastBackupAtGauges.WithLabelValues("snapshot").Set(float64(now.Unix()))
-				lastBackupOkGauges.WithLabelValues("snapshot").Set(1)
-				backupCompletedCounters.WithLabelValues("snapshot").Inc()
-				lgr.Infow("backup_complete", "type", "snapshot")
-			} else {
-				lastBackupOkGauges.WithLabelValues("snapshot").Set(0)
-				backupErrorCounters.WithLabelValues("snapshot").Inc()
-				lgr.Errorw("backup_error", "type", "snapshot", "err", err)
+			for _, j := range jobs {
+				if j.ready(now) {
+					runJob(ctx, st, j)
+					break
+				}
 			}
 		}
 	}
-	return err
+}
+
+func keepState(st *state, j *job, now time.Time) {
+	if err := st.keep(j.backupType, j.lastAt, now); err != nil {
+		zap.S().Errorw("save_schedule_state_error", "type", j.backupType, "err", err)
+	}
+}
+
+func runJob(ctx context.Context, st *state, j *job) {
+	lgr := zap.S()
+	backupInProgressGauges.WithLabelValues(j.backupType).Set(1)
+	lgr.Infow("starting_backup", "type", j.backupType)
+	err := j.run(ctx)
+	backupInProgressGauges.WithLabelValues(j.backupType).Set(0)
+	if err != nil {
+		lastBackupOkGauges.WithLabelValues(j.backupType).Set(0)
+		backupErrorCounters.WithLabelValues(j.backupType).Inc()
+		lgr.Errorw("backup_error", "type", j.backupType, "err", err)
+		return
+	}
+
+	now := time.Now()
+	j.lastAt = now
+	lastBackupAtGauges.WithLabelValues(j.backupType).Set(float64(now.Unix()))
+	lastBackupOkGauges.WithLabelValues(j.backupType).Set(1)
+	backupCompletedCounters.WithLabelValues(j.backupType).Inc()
+	lgr.Infow("backup_complete", "type", j.backupType)
+	if err := st.setLastAt(j.backupType, now); err != nil {
+		lgr.Errorw("save_schedule_state_error", "type", j.backupType, "err", err)
+	}
+	j.plan()
 }
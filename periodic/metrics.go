@@ -39,6 +39,18 @@ var (
 		Name:      "in_progress",
 		Help:      "1 if a backup is in progress.",
 	}, []string{"type"})
+	nextAtGauges = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "cassandrabackup",
+		Subsystem: "periodic",
+		Name:      "next_at_seconds",
+		Help:      "Time the next scheduled backup is due.",
+	}, []string{"type"})
+	triggeredCounters = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "cassandrabackup",
+		Subsystem: "periodic",
+		Name:      "triggered_total",
+		Help:      "Number of backups triggered over HTTP.",
+	}, []string{"type"})
 	backupErrorCounters = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "cassandrabackup",
 		Subsystem: "periodic",
@@ -62,6 +74,8 @@ func registerMetrics() {
 		prometheus.MustRegister(backupInProgressGauges)
 		prometheus.MustRegister(lastBackupAtGauges)
 		prometheus.MustRegister(lastBackupOkGauges)
+		prometheus.MustRegister(nextAtGauges)
+		prometheus.MustRegister(triggeredCounters)
 
 		// reify everything
 		backupErrorCounters.WithLabelValues("incremental")
@@ -74,5 +88,9 @@ func registerMetrics() {
 		lastBackupAtGauges.WithLabelValues("snapshot").Set(0)
 		lastBackupOkGauges.WithLabelValues("incremental").Set(0)
 		lastBackupOkGauges.WithLabelValues("snapshot").Set(0)
+		nextAtGauges.WithLabelValues("incremental").Set(0)
+		nextAtGauges.WithLabelValues("snapshot").Set(0)
+		triggeredCounters.WithLabelValues("incremental")
+		triggeredCounters.WithLabelValues("snapshot")
 	})
 }
@@ -0,0 +1,294 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package periodic
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule decides when a backup is next due.
+type Schedule interface {
+	// Next returns the time the first backup after one made at last is due.
+	// If last is zero, no backup has been made and one is due now.
+	Next(last time.Time) time.Time
+}
+
+const ScheduleHelp = "as an interval such as 1h, or a cron expression such as \"30 2 * * *\" or @daily, in local time"
+
+// ParseSchedule parses an interval like "1h" or a five field cron expression.
+func ParseSchedule(value string) (Schedule, error) {
+	value = strings.TrimSpace(value)
+	if every, err := time.ParseDuration(value); err == nil {
+		if every <= 0 {
+			return nil, fmt.Errorf("invalid schedule %q: interval must be positive", value)
+		}
+		return interval(every), nil
+	}
+	if alias, ok := cronAliases[value]; ok {
+		value = alias
+	}
+	fields := strings.Fields(value)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid schedule %q: expected an interval or 5 cron fields", value)
+	}
+	var c cron
+	for i, spec := range cronFields {
+		set, err := spec.parse(fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %w", value, err)
+		}
+		c.fields[i] = set
+	}
+	c.anyDay = fields[2] == "*"
+	c.anyWeekday = fields[4] == "*"
+	if c.Next(time.Now()).IsZero() {
+		return nil, fmt.Errorf("invalid schedule %q: never matches", value)
+	}
+	return c, nil
+}
+
+type interval time.Duration
+
+func (i interval) Next(last time.Time) time.Time {
+	if last.IsZero() {
+		return last
+	}
+	return last.Add(time.Duration(i))
+}
+
+var cronAliases = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+}
+
+const (
+	cronMinute = iota
+	cronHour
+	cronDay
+	cronMonth
+	cronWeekday
+)
+
+type cronField struct {
+	name     string
+	min, max int
+	names    []string
+}
+
+var weekdayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+var cronFields = [5]cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"", "jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+	{name: "day of week", min: 0, max: 7, names: weekdayNames},
+}
+
+// bits is a set of the values of a cron field.
+type bits uint64
+
+func (b bits) has(v int) bool {
+	return b&(1<<uint(v)) != 0
+}
+
+// parse parses a comma separated list of *, values and ranges, each with an optional /step.
+func (f cronField) parse(value string) (bits, error) {
+	var set bits
+	for _, part := range strings.Split(value, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepPart); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q in %s", stepPart, f.name)
+			}
+		}
+		low, high := f.min, f.max
+		if rangePart != "*" {
+			lowPart, highPart, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if low, err = f.value(lowPart); err != nil {
+				return 0, err
+			}
+			high = low
+			if isRange {
+				if high, err = f.value(highPart); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				high = f.max
+			}
+			if high < low {
+				return 0, fmt.Errorf("invalid range %q in %s", rangePart, f.name)
+			}
+		}
+		for v := low; v <= high; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	if f.max == 7 && set.has(7) {
+		// Both 0 and 7 are Sunday.
+		set |= 1
+	}
+	return set, nil
+}
+
+func (f cronField) value(s string) (int, error) {
+	for i, name := range f.names {
+		if name != "" && strings.EqualFold(s, name) {
+			return i, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid %s %q", f.name, s)
+	}
+	return v, nil
+}
+
+type cron struct {
+	fields             [5]bits
+	anyDay, anyWeekday bool
+}
+
+func (c cron) dayMatches(t time.Time) bool {
+	day := c.fields[cronDay].has(t.Day())
+	weekday := c.fields[cronWeekday].has(int(t.Weekday()))
+	// Like crond, a day matches either field when both are restricted.
+	switch {
+	case c.anyDay && c.anyWeekday:
+		return true
+	case c.anyDay:
+		return weekday
+	case c.anyWeekday:
+		return day
+	default:
+		return day || weekday
+	}
+}
+
+// Next returns the first matching minute after last, or the zero time if there is none within five years.
+func (c cron) Next(last time.Time) time.Time {
+	if last.IsZero() {
+		return last
+	}
+	t := last.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case !c.fields[cronMonth].has(int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !c.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case !c.fields[cronHour].has(t.Hour()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case !c.fields[cronMinute].has(t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// Window is a daily period, optionally only on some days of the week, during which a type of backup
+// should not start.
+type Window struct {
+	weekdays   bits
+	start, end int // minutes since midnight
+}
+
+const WindowHelp = "as HH:MM-HH:MM in local time, optionally preceded by days of the week such as \"mon-fri 09:00-17:00\""
+
+// ParseWindow parses "HH:MM-HH:MM" or "days HH:MM-HH:MM", where days is a day of week cron field.
+// A window that ends before it starts spans midnight, and belongs to the day it starts on.
+func ParseWindow(value string) (Window, error) {
+	fields := strings.Fields(value)
+	w := Window{weekdays: 1<<7 - 1}
+	switch len(fields) {
+	case 1:
+	case 2:
+		weekdays, err := cronFields[cronWeekday].parse(fields[0])
+		if err != nil {
+			return Window{}, fmt.Errorf("invalid blackout window %q: %w", value, err)
+		}
+		w.weekdays = weekdays
+	default:
+		return Window{}, fmt.Errorf("invalid blackout window %q", value)
+	}
+	startPart, endPart, ok := strings.Cut(fields[len(fields)-1], "-")
+	if !ok {
+		return Window{}, fmt.Errorf("invalid blackout window %q: expected HH:MM-HH:MM", value)
+	}
+	var err error
+	if w.start, err = parseClock(startPart); err != nil {
+		return Window{}, fmt.Errorf("invalid blackout window %q: %w", value, err)
+	}
+	if w.end, err = parseClock(endPart); err != nil {
+		return Window{}, fmt.Errorf("invalid blackout window %q: %w", value, err)
+	}
+	return w, nil
+}
+
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func (w Window) Contains(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	weekday := int(t.Weekday())
+	if w.start <= w.end {
+		return w.weekdays.has(weekday) && minute >= w.start && minute < w.end
+	}
+	if minute >= w.start {
+		return w.weekdays.has(weekday)
+	}
+	return minute < w.end && w.weekdays.has((weekday+6)%7)
+}
+
+// Blackouts is the set of windows during which a type of backup should not start.
+type Blackouts []Window
+
+func (b Blackouts) Contains(t time.Time) bool {
+	for _, w := range b {
+		if w.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func ParseBlackouts(values []string) (Blackouts, error) {
+	result := make(Blackouts, 0, len(values))
+	for _, value := range values {
+		w, err := ParseWindow(value)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, w)
+	}
+	return result, nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package periodic
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSchedule(t *testing.T) {
+	// A Wednesday.
+	last := time.Date(2026, time.January, 7, 10, 17, 42, 0, time.UTC)
+	cases := []struct {
+		schedule string
+		next     time.Time
+	}{
+		{"1h", last.Add(time.Hour)},
+		{"*/15 * * * *", time.Date(2026, time.January, 7, 10, 30, 0, 0, time.UTC)},
+		{"30 2 * * *", time.Date(2026, time.January, 8, 2, 30, 0, 0, time.UTC)},
+		{"@daily", time.Date(2026, time.January, 8, 0, 0, 0, 0, time.UTC)},
+		{"0 3 * * sun", time.Date(2026, time.January, 11, 3, 0, 0, 0, time.UTC)},
+		{"0 3 * * 7", time.Date(2026, time.January, 11, 3, 0, 0, 0, time.UTC)},
+		{"0 0 1 feb,mar *", time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 31 * fri", time.Date(2026, time.January, 9, 0, 0, 0, 0, time.UTC)},
+		{"5-10/5 10-11 * * *", time.Date(2026, time.January, 7, 11, 5, 0, 0, time.UTC)},
+	}
+	for _, c := range cases {
+		schedule, err := ParseSchedule(c.schedule)
+		if err != nil {
+			t.Errorf("%q: %v", c.schedule, err)
+			continue
+		}
+		if next := schedule.Next(last); !next.Equal(c.next) {
+			t.Errorf("%q: expected %v, got %v", c.schedule, c.next, next)
+		}
+		if next := schedule.Next(time.Time{}); !next.IsZero() {
+			t.Errorf("%q: expected a first backup to be due now, got %v", c.schedule, next)
+		}
+	}
+
+	for _, invalid := range []string{"", "-1h", "* * * *", "60 * * * *", "0 0 30 feb *", "*/0 * * * *", "5-1 * * * *"} {
+		if _, err := ParseSchedule(invalid); err == nil {
+			t.Errorf("%q: expected an error", invalid)
+		}
+	}
+}
+
+func TestBlackouts(t *testing.T) {
+	blackouts, err := ParseBlackouts([]string{"mon-fri 09:00-17:00", "sat 22:00-02:00"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		at       time.Time
+		contains bool
+	}{
+		{time.Date(2026, time.January, 7, 9, 0, 0, 0, time.UTC), true},
+		{time.Date(2026, time.January, 7, 16, 59, 0, 0, time.UTC), true},
+		{time.Date(2026, time.January, 7, 17, 0, 0, 0, time.UTC), false},
+		{time.Date(2026, time.January, 10, 12, 0, 0, 0, time.UTC), false},
+		{time.Date(2026, time.January, 10, 23, 0, 0, 0, time.UTC), true},
+		{time.Date(2026, time.January, 11, 1, 30, 0, 0, time.UTC), true},
+		{time.Date(2026, time.January, 12, 1, 30, 0, 0, time.UTC), false},
+	}
+	for _, c := range cases {
+		if contains := blackouts.Contains(c.at); contains != c.contains {
+			t.Errorf("%v: expected %v, got %v", c.at, c.contains, contains)
+		}
+	}
+
+	for _, invalid := range []string{"09:00", "9-17", "mon-fri", "xyz 09:00-17:00", "mon fri 09:00-17:00"} {
+		if _, err := ParseWindow(invalid); err == nil {
+			t.Errorf("%q: expected an error", invalid)
+		}
+	}
+}
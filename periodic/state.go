@@ -0,0 +1,92 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package periodic
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/retailnext/cassandrabackup/cache"
+)
+
+const (
+	stateCacheName = "periodic"
+	// stateRefreshInterval is how often the time of the last backup is written again. It is well
+	// within the period of the cache, which is about 12 days.
+	stateRefreshInterval = 24 * time.Hour
+)
+
+// stateStore is where state is kept; the shared cache, except in tests.
+type stateStore interface {
+	Get(key []byte, f cache.WithValueFunc) error
+	Put(key, value []byte) error
+}
+
+// state remembers when each type of backup last completed, so that a restart doesn't immediately
+// start a backup that isn't due. Entries live in the shared cache, which forgets entries that haven't
+// been written for one or two cache periods of about 12 days each. That's shorter than a monthly
+// schedule, so each entry is written again every day by keep, not only when a backup completes.
+type state struct {
+	c           stateStore
+	refreshedAt map[string]time.Time
+}
+
+func openState() *state {
+	cache.OpenShared()
+	return newState(cache.Shared.Cache(stateCacheName))
+}
+
+func newState(c stateStore) *state {
+	return &state{c: c, refreshedAt: make(map[string]time.Time)}
+}
+
+func (s *state) lastAt(backupType string) (time.Time, error) {
+	var result time.Time
+	err := s.c.Get([]byte(backupType), func(value []byte) error {
+		if len(value) != 8 {
+			return cache.DoNotPromote
+		}
+		result = time.Unix(int64(binary.BigEndian.Uint64(value)), 0)
+		return nil
+	})
+	if errors.Is(err, cache.NotFound) || errors.Is(err, cache.DoNotPromote) {
+		return time.Time{}, nil
+	}
+	return result, err
+}
+
+func (s *state) setLastAt(backupType string, t time.Time) error {
+	return s.put(backupType, t, t)
+}
+
+// keep writes the time of the last backup again if it hasn't been written for a day, so that the
+// cache doesn't forget it while waiting for a backup on a long schedule.
+func (s *state) keep(backupType string, lastAt, now time.Time) error {
+	if lastAt.IsZero() || now.Sub(s.refreshedAt[backupType]) < stateRefreshInterval {
+		return nil
+	}
+	return s.put(backupType, lastAt, now)
+}
+
+func (s *state) put(backupType string, t, now time.Time) error {
+	value := make([]byte, 8)
+	binary.BigEndian.PutUint64(value, uint64(t.Unix()))
+	if err := s.c.Put([]byte(backupType), value); err != nil {
+		return err
+	}
+	s.refreshedAt[backupType] = now
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package periodic
+
+import (
+	"testing"
+	"time"
+
+	"github.com/retailnext/cassandrabackup/cache"
+)
+
+// fakeStateStore forgets entries like the cache does: each is kept in the cache period it was last
+// written in and the next, and moved to the current period when read from the previous one.
+type fakeStateStore struct {
+	now     time.Time
+	period  int64
+	entries map[string]fakeStateEntry
+}
+
+type fakeStateEntry struct {
+	value  []byte
+	period int64
+}
+
+func (f *fakeStateStore) currentPeriod() int64 {
+	return f.now.Unix() / f.period
+}
+
+func (f *fakeStateStore) Get(key []byte, fn cache.WithValueFunc) error {
+	entry, ok := f.entries[string(key)]
+	current := f.currentPeriod()
+	if !ok || entry.period < current-1 {
+		return cache.NotFound
+	}
+	if entry.period < current {
+		f.entries[string(key)] = fakeStateEntry{value: entry.value, period: current}
+	}
+	return fn(entry.value)
+}
+
+func (f *fakeStateStore) Put(key, value []byte) error {
+	f.entries[string(key)] = fakeStateEntry{value: value, period: f.currentPeriod()}
+	return nil
+}
+
+func TestStateOutlivesCachePeriod(t *testing.T) {
+	schedule, err := ParseSchedule("0 0 1 * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lastAt := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	restartAt := time.Date(2026, time.January, 31, 12, 0, 0, 0, time.UTC)
+
+	for _, keep := range []bool{false, true} {
+		store := &fakeStateStore{now: lastAt, period: 1 << 20, entries: make(map[string]fakeStateEntry)}
+		st := newState(store)
+		if err := st.setLastAt(backupTypeSnapshot, lastAt); err != nil {
+			t.Fatal(err)
+		}
+		// The scheduler waits for the next backup, passing over its jobs every minute.
+		for store.now = lastAt; store.now.Before(restartAt); store.now = store.now.Add(time.Minute) {
+			if keep {
+				if err := st.keep(backupTypeSnapshot, lastAt, store.now); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+
+		got, err := newState(store).lastAt(backupTypeSnapshot)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !keep {
+			if !got.IsZero() {
+				t.Fatalf("expected the cache to forget a last backup that isn't kept, got %v", got)
+			}
+			continue
+		}
+		if !got.Equal(lastAt) {
+			t.Fatalf("expected the last backup at %v to be remembered, got %v", lastAt, got)
+		}
+		if next := schedule.Next(got); !next.Equal(time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("unexpected next backup %v", next)
+		}
+	}
+}
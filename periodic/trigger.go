@@ -0,0 +1,90 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package periodic
+
+import (
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// checkHandlerPath checks that a path such as --trigger-path or --throttle-path can be served on the
+// metrics listener: that there is a listener, and that the path isn't that of the metrics, which
+// net/http would panic on.
+func checkHandlerPath(flag, path, listenAddress, telemetryPath string) error {
+	if path == "" {
+		return nil
+	}
+	if listenAddress == "" {
+		return fmt.Errorf("--%s needs --web.listen-address", flag)
+	}
+	if path == telemetryPath {
+		return fmt.Errorf("--%s can't be the same as --web.telemetry-path %s", flag, path)
+	}
+	return nil
+}
+
+// triggers queues on-demand backups requested over HTTP. At most one backup of each type can be
+// pending; it starts once the backup in progress, if any, is done.
+type triggers struct {
+	pending map[string]chan struct{}
+	ch      chan string
+}
+
+func newTriggers(backupTypes ...string) *triggers {
+	t := &triggers{
+		pending: make(map[string]chan struct{}, len(backupTypes)),
+		ch:      make(chan string, len(backupTypes)),
+	}
+	for _, backupType := range backupTypes {
+		t.pending[backupType] = make(chan struct{}, 1)
+	}
+	return t
+}
+
+// received returns the type of a triggered backup; call done with it once the backup has started.
+func (t *triggers) received() <-chan string {
+	return t.ch
+}
+
+func (t *triggers) done(backupType string) {
+	<-t.pending[backupType]
+}
+
+func (t *triggers) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	backupType := r.URL.Query().Get("type")
+	pending, ok := t.pending[backupType]
+	if !ok {
+		http.Error(w, "type must be snapshot or incremental", http.StatusBadRequest)
+		return
+	}
+	select {
+	case pending <- struct{}{}:
+	default:
+		http.Error(w, fmt.Sprintf("a triggered %s backup is already pending", backupType), http.StatusConflict)
+		return
+	}
+	t.ch <- backupType
+	triggeredCounters.WithLabelValues(backupType).Inc()
+	zap.S().Infow("backup_triggered", "type", backupType, "remote", r.RemoteAddr)
+	w.WriteHeader(http.StatusAccepted)
+	_, _ = fmt.Fprintf(w, "%s backup queued\n", backupType)
+}
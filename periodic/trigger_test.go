@@ -0,0 +1,34 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package periodic
+
+import "testing"
+
+func TestCheckHandlerPath(t *testing.T) {
+	cases := []struct {
+		path, listenAddress string
+		ok                  bool
+	}{
+		{"", "", true},
+		{"/backup", ":9090", true},
+		{"/backup", "", false},
+		{"/metrics", ":9090", false},
+	}
+	for _, c := range cases {
+		if err := checkHandlerPath("trigger-path", c.path, c.listenAddress, "/metrics"); (err == nil) != c.ok {
+			t.Errorf("%q on %q: expected ok=%v, got %v", c.path, c.listenAddress, c.ok, err)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prune
+
+import "github.com/alecthomas/kingpin/v2"
+
+var (
+	Cmd = kingpin.Command("prune", "Delete manifests outside the retention policy, and blobs no longer referenced by any manifest.")
+
+	cmdCluster     = Cmd.Flag("cluster", "Only apply the retention policy to hosts in this cluster. Blobs are always checked against every cluster's manifests.").String()
+	cmdKeepLast    = Cmd.Flag("keep-last", "Keep the last N snapshots of each host.").Int()
+	cmdKeepWithin  = Cmd.Flag("keep-within", "Keep every manifest newer than this, along with the snapshot they build on.").Duration()
+	cmdKeepDaily   = Cmd.Flag("keep-daily", "Keep the last snapshot of each of the last N days with snapshots.").Int()
+	cmdKeepWeekly  = Cmd.Flag("keep-weekly", "Keep the last snapshot of each of the last N weeks with snapshots.").Int()
+	cmdKeepMonthly = Cmd.Flag("keep-monthly", "Keep the last snapshot of each of the last N months with snapshots.").Int()
+	cmdGrace       = Cmd.Flag("grace", "Wait this long between marking unreferenced blobs and deleting them. Must exceed the longest backup by at least an hour.").Default("48h").Duration()
+	cmdDryRun      = Cmd.Flag("dry-run", "Report what would be deleted without deleting anything.").Bool()
+	cmdJSON        = Cmd.Flag("json", "Write the report to stdout as JSON.").Bool()
+)
@@ -0,0 +1,115 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prune
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/retailnext/cassandrabackup/manifests"
+)
+
+// Policy decides which of a host's manifests to keep. A restore uses the latest snapshot before the
+// chosen time and every manifest after it, so incremental and incomplete manifests are only kept
+// together with the snapshot they build on. The latest snapshot and everything after it are always kept.
+type Policy struct {
+	KeepLast    int
+	KeepWithin  time.Duration
+	KeepDaily   int
+	KeepWeekly  int
+	KeepMonthly int
+}
+
+func (p Policy) IsEmpty() bool {
+	return p.KeepLast <= 0 && p.KeepWithin <= 0 && p.KeepDaily <= 0 && p.KeepWeekly <= 0 && p.KeepMonthly <= 0
+}
+
+// Apply splits keys into the manifests to keep and the ones to remove, both in time order.
+func (p Policy) Apply(keys manifests.ManifestKeys, now time.Time) (keep, remove manifests.ManifestKeys) {
+	sorted := make(manifests.ManifestKeys, len(keys))
+	copy(sorted, keys)
+	sort.Sort(sorted)
+
+	var snapshots []int
+	for i, key := range sorted {
+		if key.ManifestType == manifests.ManifestTypeSnapshot {
+			snapshots = append(snapshots, i)
+		}
+	}
+	if len(snapshots) == 0 {
+		return sorted, nil
+	}
+
+	kept := make([]bool, len(sorted))
+	keepFrom := func(start int) {
+		for i := start; i < len(sorted); i++ {
+			kept[i] = true
+		}
+	}
+	keepFrom(snapshots[len(snapshots)-1])
+
+	for i := len(snapshots) - 1; i >= 0 && i >= len(snapshots)-p.KeepLast; i-- {
+		kept[snapshots[i]] = true
+	}
+
+	if p.KeepWithin > 0 {
+		cutoff := now.Add(-p.KeepWithin).Unix()
+		start := sort.Search(len(sorted), func(i int) bool {
+			return int64(sorted[i].Time) > cutoff
+		})
+		// Manifests from within the window build on the last snapshot at or before its start.
+		base := start
+		for _, i := range snapshots {
+			if i <= start {
+				base = i
+			}
+		}
+		keepFrom(base)
+	}
+
+	keepPeriodic(sorted, snapshots, kept, p.KeepDaily, func(t time.Time) string {
+		return t.Format("2006-01-02")
+	})
+	keepPeriodic(sorted, snapshots, kept, p.KeepWeekly, func(t time.Time) string {
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-%02d", year, week)
+	})
+	keepPeriodic(sorted, snapshots, kept, p.KeepMonthly, func(t time.Time) string {
+		return t.Format("2006-01")
+	})
+
+	for i, key := range sorted {
+		if kept[i] {
+			keep = append(keep, key)
+		} else {
+			remove = append(remove, key)
+		}
+	}
+	return keep, remove
+}
+
+// keepPeriodic keeps the newest snapshot in each of the last n periods, in UTC, that have one.
+func keepPeriodic(sorted manifests.ManifestKeys, snapshots []int, kept []bool, n int, period func(time.Time) string) {
+	var last string
+	for i := len(snapshots) - 1; i >= 0 && n > 0; i-- {
+		key := sorted[snapshots[i]]
+		if current := period(key.Time.Time().UTC()); current != last {
+			kept[snapshots[i]] = true
+			last = current
+			n--
+		}
+	}
+}
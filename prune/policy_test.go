@@ -0,0 +1,97 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prune
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/retailnext/cassandrabackup/manifests"
+	"github.com/retailnext/cassandrabackup/unixtime"
+)
+
+func key(t time.Time, manifestType manifests.ManifestType) manifests.ManifestKey {
+	return manifests.ManifestKey{Time: unixtime.Seconds(t.Unix()), ManifestType: manifestType}
+}
+
+func TestPolicyApply(t *testing.T) {
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+	var keys manifests.ManifestKeys
+	// A snapshot every 12 hours for 60 days, each followed by an incremental.
+	for at := now.Add(-60 * 24 * time.Hour); at.Before(now); at = at.Add(12 * time.Hour) {
+		keys = append(keys, key(at, manifests.ManifestTypeSnapshot), key(at.Add(time.Hour), manifests.ManifestTypeIncremental))
+	}
+	snapshot := func(daysAgo, hour int) manifests.ManifestKey {
+		day := now.Add(-time.Duration(daysAgo) * 24 * time.Hour)
+		return key(time.Date(day.Year(), day.Month(), day.Day(), hour, 0, 0, 0, time.UTC), manifests.ManifestTypeSnapshot)
+	}
+	incremental := func(daysAgo, hour int) manifests.ManifestKey {
+		k := snapshot(daysAgo, hour)
+		k.Time += 3600
+		k.ManifestType = manifests.ManifestTypeIncremental
+		return k
+	}
+
+	cases := []struct {
+		name     string
+		policy   Policy
+		expected manifests.ManifestKeys
+	}{
+		{
+			name:     "latest chain only",
+			policy:   Policy{KeepLast: 1},
+			expected: manifests.ManifestKeys{snapshot(0, 0), incremental(0, 0)},
+		},
+		{
+			name:     "keep last",
+			policy:   Policy{KeepLast: 3},
+			expected: manifests.ManifestKeys{snapshot(1, 0), snapshot(1, 12), snapshot(0, 0), incremental(0, 0)},
+		},
+		{
+			name:     "keep within",
+			policy:   Policy{KeepWithin: 23*time.Hour + 30*time.Minute},
+			expected: manifests.ManifestKeys{snapshot(1, 12), incremental(1, 12), snapshot(0, 0), incremental(0, 0)},
+		},
+		{
+			name:     "daily",
+			policy:   Policy{KeepDaily: 3},
+			expected: manifests.ManifestKeys{snapshot(2, 12), snapshot(1, 12), snapshot(0, 0), incremental(0, 0)},
+		},
+		{
+			name:   "weekly and monthly",
+			policy: Policy{KeepWeekly: 2, KeepMonthly: 3},
+			expected: manifests.ManifestKeys{
+				snapshot(59, 12), snapshot(31, 12), snapshot(2, 12), snapshot(0, 0), incremental(0, 0),
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			keep, remove := c.policy.Apply(keys, now)
+			if diff := deep.Equal(keep, c.expected); diff != nil {
+				t.Fatal(diff)
+			}
+			if len(keep)+len(remove) != len(keys) {
+				t.Fatalf("kept %d and removed %d of %d", len(keep), len(remove), len(keys))
+			}
+		})
+	}
+
+	onlyIncrementals := manifests.ManifestKeys{key(now, manifests.ManifestTypeIncremental)}
+	if keep, remove := (Policy{KeepLast: 1}).Apply(onlyIncrementals, now); len(keep) != 1 || len(remove) != 0 {
+		t.Fatalf("expected manifests without a snapshot to be kept, got %v %v", keep, remove)
+	}
+}
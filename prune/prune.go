@@ -0,0 +1,159 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prune
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/retailnext/cassandrabackup/bucket"
+	"github.com/retailnext/cassandrabackup/digest"
+	"github.com/retailnext/cassandrabackup/manifests"
+	"go.uber.org/zap"
+)
+
+type HostReport struct {
+	Cluster  string   `json:"cluster"`
+	Hostname string   `json:"hostname"`
+	Kept     int      `json:"kept_manifests"`
+	Removed  []string `json:"removed_manifests"`
+	Locked   []string `json:"locked_manifests,omitempty"`
+}
+
+type Report struct {
+	DryRun bool                 `json:"dry_run"`
+	Hosts  []HostReport         `json:"hosts"`
+	Blobs  bucket.GarbageReport `json:"blobs"`
+}
+
+func Main(ctx context.Context) error {
+	policy := Policy{
+		KeepLast:    *cmdKeepLast,
+		KeepWithin:  *cmdKeepWithin,
+		KeepDaily:   *cmdKeepDaily,
+		KeepWeekly:  *cmdKeepWeekly,
+		KeepMonthly: *cmdKeepMonthly,
+	}
+	if policy.IsEmpty() {
+		return errors.New("no retention policy given: use --keep-last, --keep-within, --keep-daily, --keep-weekly or --keep-monthly")
+	}
+	if *cmdGrace < 2*bucket.PendingDeletesRefresh {
+		return fmt.Errorf("--grace must be at least %v", 2*bucket.PendingDeletesRefresh)
+	}
+
+	report, err := Prune(ctx, bucket.OpenShared(), policy, *cmdCluster, *cmdGrace, *cmdDryRun)
+	if err == context.Canceled {
+		return err
+	}
+	if *cmdJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if encodeErr := encoder.Encode(report); encodeErr != nil {
+			return encodeErr
+		}
+	} else {
+		lgr := zap.S()
+		for _, host := range report.Hosts {
+			lgr.Infow("prune_host", "cluster", host.Cluster, "hostname", host.Hostname, "dry_run", report.DryRun,
+				"kept", host.Kept, "removed", len(host.Removed), "locked", len(host.Locked))
+		}
+		lgr.Infow("prune_blobs", "dry_run", report.DryRun, "result", report.Blobs)
+	}
+	return err
+}
+
+// Prune applies policy to the hosts in cluster, or in every cluster if it is empty, and then collects
+// garbage against the surviving manifests of every cluster.
+func Prune(ctx context.Context, client bucket.Client, policy Policy, cluster string, grace time.Duration, dryRun bool) (Report, error) {
+	report := Report{DryRun: dryRun}
+	listedAt := time.Now()
+	referenced := make(map[digest.ForRestore]struct{})
+
+	clusters, err := client.ListClusters(ctx)
+	if err != nil {
+		return report, err
+	}
+	for _, c := range clusters {
+		hosts, err := client.ListHostNames(ctx, c)
+		if err != nil {
+			return report, err
+		}
+		for _, identity := range hosts {
+			keys, err := client.ListManifests(ctx, identity, 0, 0)
+			if err != nil {
+				return report, err
+			}
+			if cluster == "" || cluster == c {
+				var host HostReport
+				if host, keys, err = applyPolicy(ctx, client, identity, policy, keys, listedAt, dryRun); err != nil {
+					return report, err
+				}
+				report.Hosts = append(report.Hosts, host)
+			}
+			if len(keys) == 0 {
+				continue
+			}
+			hostManifests, err := client.GetManifests(ctx, identity, keys)
+			if err != nil {
+				return report, err
+			}
+			if err := ctx.Err(); err != nil {
+				return report, err
+			}
+			for _, m := range hostManifests {
+				for _, file := range m.DataFiles {
+					referenced[file] = struct{}{}
+				}
+				if m.Schema != nil {
+					referenced[*m.Schema] = struct{}{}
+				}
+			}
+		}
+	}
+
+	report.Blobs, err = bucket.CollectGarbage(ctx, client, referenced, listedAt, grace, dryRun)
+	return report, err
+}
+
+// applyPolicy deletes the host's manifests that policy does not keep and returns the ones that remain.
+func applyPolicy(ctx context.Context, client bucket.Client, identity manifests.NodeIdentity, policy Policy, keys manifests.ManifestKeys, now time.Time, dryRun bool) (HostReport, manifests.ManifestKeys, error) {
+	lgr := zap.S()
+	host := HostReport{
+		Cluster:  identity.Cluster,
+		Hostname: identity.Hostname,
+		Removed:  []string{},
+	}
+	keep, remove := policy.Apply(keys, now)
+	for _, key := range remove {
+		err := bucket.DeleteManifest(ctx, client, identity, key, dryRun)
+		switch {
+		case err == nil:
+			host.Removed = append(host.Removed, key.FileName())
+		case errors.Is(err, bucket.ErrObjectLocked):
+			host.Locked = append(host.Locked, key.FileName())
+			keep = append(keep, key)
+		case bucket.IsNoSuchKey(err):
+		default:
+			lgr.Errorw("prune_delete_manifest_error", "identity", identity, "manifest", key, "err", err)
+			return host, nil, err
+		}
+	}
+	host.Kept = len(keep)
+	return host, keep, nil
+}
@@ -0,0 +1,40 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replicate
+
+import (
+	"os"
+
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/retailnext/cassandrabackup/unixtime"
+)
+
+var (
+	Cmd = kingpin.Command("copy", "Copy backup manifests and every blob they reference to another bucket, prefix or storage backend")
+
+	cmdCluster     = Cmd.Flag("cluster", "Cluster whose backups to copy").Required().String()
+	cmdHostname    = Cmd.Flag("hostname", "Only copy the backups of this host").String()
+	cmdNotBefore   = unixtime.Flag(Cmd.Flag("not-before", "Ignore manifests before this time "+unixtime.TimeHelp))
+	cmdNotAfter    = unixtime.Flag(Cmd.Flag("not-after", "Ignore manifests after this time "+unixtime.TimeHelp))
+	cmdAt          = unixtime.Flag(Cmd.Flag("at", "Only copy the manifests a restore at this time would use "+unixtime.TimeHelp))
+	cmdToStorage   = Cmd.Flag("to-storage", "Storage backend to copy to.").Default("s3").Enum("s3", "file")
+	cmdToBucket    = Cmd.Flag("to-s3-bucket", "S3 bucket to copy to.").String()
+	cmdToRegion    = Cmd.Flag("to-s3-region", "Region of --to-s3-bucket (default: --s3-region).").String()
+	cmdToKeyPrefix = Cmd.Flag("to-s3-key-prefix", "Prefix for files in --to-s3-bucket.").Default("/").String()
+	cmdToFileRoot  = Cmd.Flag("to-file-root", "Root directory to copy to with --to-storage=file.").String()
+	cmdConcurrency = Cmd.Flag("concurrency", "Number of blobs to copy at once").Default("4").Int()
+	cmdTempDir     = Cmd.Flag("temp-dir", "Directory to download blobs into when they can't be copied server side").Default(os.TempDir()).ExistingDir()
+	cmdDryRun      = Cmd.Flag("dry-run", "Only count the manifests and blobs that would be copied").Bool()
+)
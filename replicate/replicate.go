@@ -0,0 +1,87 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replicate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/retailnext/cassandrabackup/bucket"
+	"github.com/retailnext/cassandrabackup/manifests"
+	"github.com/retailnext/cassandrabackup/restore/plan"
+	"go.uber.org/zap"
+)
+
+// Main copies the selected manifests of the cluster from the configured store to the one the --to
+// flags describe, such as a bucket in another region or a directory.
+func Main(ctx context.Context) error {
+	lgr := zap.S()
+	notAfter, err := plan.NotAfter(*cmdAt, *cmdNotAfter)
+	if err != nil {
+		return err
+	}
+	src := bucket.OpenShared()
+	dst, err := bucket.OpenDestination(bucket.Destination{
+		Storage:     *cmdToStorage,
+		S3Bucket:    *cmdToBucket,
+		S3Region:    *cmdToRegion,
+		S3KeyPrefix: *cmdToKeyPrefix,
+		FileRoot:    *cmdToFileRoot,
+	})
+	if err != nil {
+		return err
+	}
+	copier, err := bucket.NewCopier(src, dst, *cmdConcurrency, *cmdTempDir, *cmdDryRun)
+	if err != nil {
+		return err
+	}
+
+	identities := []manifests.NodeIdentity{{Cluster: *cmdCluster, Hostname: *cmdHostname}}
+	if *cmdHostname == "" {
+		if identities, err = src.ListHostNames(ctx, *cmdCluster); err != nil {
+			return err
+		}
+	}
+	selected := 0
+	for _, identity := range identities {
+		keys, err := src.ListManifests(ctx, identity, *cmdNotBefore, notAfter)
+		if err != nil {
+			return err
+		}
+		if *cmdAt != 0 {
+			keys = keys.FromLatestSnapshot()
+		}
+		if len(keys) == 0 {
+			lgr.Warnw("copy_no_manifests", "identity", identity)
+			continue
+		}
+		selected += len(keys)
+		if err := copier.Copy(ctx, identity, keys); err != nil {
+			return err
+		}
+		lgr.Infow("copy_host_done", "identity", identity, "manifests", len(keys))
+	}
+
+	result := copier.Result
+	lgr.Infow("copy_result", "dry_run", *cmdDryRun, "result", result)
+	if result.FailedManifests > 0 {
+		return fmt.Errorf("%d manifests failed to copy, with %d blobs", result.FailedManifests, result.FailedBlobs)
+	}
+	if selected == 0 {
+		return errors.New("no manifests found")
+	}
+	return nil
+}
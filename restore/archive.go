@@ -0,0 +1,75 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/retailnext/cassandrabackup/bucket"
+	"github.com/retailnext/cassandrabackup/digest"
+	"go.uber.org/zap"
+)
+
+// thawArchived makes the blobs of files that are in an archive storage class readable, requesting
+// restores of them in bulk and waiting until they are done, so that downloading doesn't fail on
+// them. A dry run only logs an estimate of what restoring them would cost and take.
+func thawArchived(ctx context.Context, client bucket.Client, files map[string]digest.ForRestore, dryRun bool) error {
+	lgr := zap.S()
+	seen := make(map[digest.ForRestore]struct{}, len(files))
+	blobs := make([]digest.ForRestore, 0, len(files))
+	for _, file := range files {
+		if _, ok := seen[file]; !ok {
+			seen[file] = struct{}{}
+			blobs = append(blobs, file)
+		}
+	}
+	archived, err := bucket.FindArchived(ctx, client, blobs)
+	if err != nil {
+		return err
+	}
+	if len(archived) == 0 {
+		return nil
+	}
+	restoring := 0
+	for _, blob := range archived {
+		if blob.Restoring {
+			restoring++
+		}
+	}
+	lgr.Infow("archived_blobs_found", "archived", len(archived), "restoring_or_restored", restoring)
+	for _, estimate := range bucket.EstimateRetrieval(archived, *archiveTier, *archiveDays) {
+		estimateLgr := lgr.With("storage_class", estimate.StorageClass, "tier", estimate.Tier, "objects", estimate.Objects, "bytes", estimate.Bytes)
+		if estimate.Cost < 0 {
+			estimateLgr.Warnw("archive_tier_not_available")
+			continue
+		}
+		estimateLgr.Infow("archive_restore_estimate", "approximate_cost_usd", fmt.Sprintf("%.2f", estimate.Cost), "duration", estimate.Duration, "days", *archiveDays)
+	}
+	if dryRun {
+		return nil
+	}
+
+	requested, err := bucket.RequestRestores(ctx, client, archived, *archiveTier, *archiveDays)
+	lgr.Infow("archive_restores_requested", "requested", requested, "tier", *archiveTier, "days", *archiveDays)
+	if err != nil {
+		return err
+	}
+	if err := bucket.WaitRestored(ctx, client, archived, *archivePollInterval); err != nil {
+		return err
+	}
+	lgr.Infow("archived_blobs_restored", "archived", len(archived))
+	return nil
+}
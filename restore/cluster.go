@@ -18,31 +18,81 @@ import (
 	"context"
 	"regexp"
 
+	"github.com/retailnext/cassandrabackup/bucket"
 	"github.com/retailnext/cassandrabackup/manifests"
 	"github.com/retailnext/cassandrabackup/nodeidentity"
 	"github.com/retailnext/cassandrabackup/restore/plan"
-	"github.com/retailnext/cassandrabackup/unixtime"
 	"go.uber.org/zap"
 )
 
 func RestoreCluster(ctx context.Context) error {
 	lgr := zap.S()
+	journalFile := journalPath(*clusterCmdJournal, *clusterCmdTargetDirectory)
+	j, err := openJournal(journalFile, "cluster", *clusterCmdTargetDirectory, *clusterCmdFresh)
+	if err != nil {
+		return err
+	}
+	resumed := j != nil
+	if resumed {
+		j.logResume(lgr, journalFile)
+	} else if j, err = planCluster(ctx); err != nil {
+		return err
+	}
+
+	if *clusterCmdDryRun {
+		for name, file := range j.Files {
+			lgr.Infow("would_download", "name", name, "digest", file.Digest)
+		}
+		return thawArchived(ctx, bucket.OpenShared(), j.files(), true)
+	}
+
+	w := newWorker(*clusterCmdTargetDirectory, false)
+	if !resumed {
+		if err := j.statFiles(ctx, w.client); err != nil {
+			return err
+		}
+		if err := j.write(journalFile); err != nil {
+			return err
+		}
+	}
+	if err := thawArchived(ctx, w.client, j.files(), false); err != nil {
+		return err
+	}
+	w.sizes = j.sizes()
+	if err := w.restoreFiles(ctx, j.files()); err != nil {
+		return err
+	}
+	if err := writeTopology(*clusterCmdTargetDirectory, j.Hosts); err != nil {
+		return err
+	}
+	return removeJournal(journalFile)
+}
+
+func planCluster(ctx context.Context) (*journal, error) {
+	lgr := zap.S()
 
 	filter := plan.Filter{
 		IncludeIndexes: !*clusterCmdSkipIndexes,
 	}
 	filter.Build(*clusterCmdTables)
 
+	notAfter, err := plan.NotAfter(*clusterCmdAt, *clusterCmdNotAfter)
+	if err != nil {
+		return nil, err
+	}
+
 	identities := nodeIdentitiesForCluster(ctx, clusterCmdCluster, clusterCmdHostnamePattern)
 	lgr.Infow("selected_hosts", "identities", identities)
 
+	j := newJournal("cluster", *clusterCmdTargetDirectory)
 	var dp downloadPlan
+	var bases []manifests.Manifest
 	for _, hostIdentity := range identities {
 		hostLgr := lgr.With("identity", hostIdentity)
 
-		nodePlan, err := plan.Create(ctx, hostIdentity, unixtime.Seconds(*clusterCmdNotBefore), unixtime.Seconds(*clusterCmdNotAfter))
+		nodePlan, err := plan.Create(ctx, hostIdentity, *clusterCmdNotBefore, notAfter)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if len(nodePlan.SelectedManifests) == 0 {
 			hostLgr.Warnw("no_backups_found")
@@ -52,24 +102,21 @@ func RestoreCluster(ctx context.Context) error {
 			hostLgr.Warnw("no_snapshots_found")
 			continue
 		}
-		hostLgr.Infow("selected_manifests", "base", nodePlan.SelectedManifests[0], "additional", nodePlan.SelectedManifests[1:])
+		nodePlan.LogSelected(hostLgr)
 
 		nodePlan.Filter(filter)
 
 		dp.addHost(hostIdentity.Hostname, nodePlan)
+		j.addHost(hostIdentity, nodePlan)
+		bases = append(bases, nodePlan.Base)
 	}
 
-	files := dp.includeChanged("PREVIOUS_VERSIONS")
-
-	if *clusterCmdDryRun {
-		for name, file := range files {
-			lgr.Infow("would_download", "name", name, "digest", file)
-		}
-		return nil
+	for _, peer := range missingPeers(bases) {
+		lgr.Warnw("ring_host_not_restored", "address", peer.Address, "host_id", peer.HostID, "data_center", peer.DataCenter, "rack", peer.Rack)
 	}
 
-	w := newWorker(*clusterCmdTargetDirectory, false)
-	return w.restoreFiles(ctx, files)
+	j.addFiles(dp.includeChanged("PREVIOUS_VERSIONS"))
+	return j, nil
 }
 
 func nodeIdentitiesForCluster(ctx context.Context, cluster, prefix *string) []manifests.NodeIdentity {
@@ -14,28 +14,75 @@
 
 package restore
 
-import "github.com/alecthomas/kingpin/v2"
+import (
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/retailnext/cassandrabackup/unixtime"
+)
 
 var (
 	Cmd = kingpin.Command("restore", "")
 
+	downloadConcurrency = Cmd.Flag("download-concurrency", "Number of files to download at once.").Default("4").Int()
+	archiveTier         = Cmd.Flag("archive-tier", "Retrieval tier to restore archived blobs with: Standard, Bulk or Expedited.").Default(s3.TierStandard).Enum(s3.TierStandard, s3.TierBulk, s3.TierExpedited)
+	archiveDays         = Cmd.Flag("archive-days", "Days to keep the restored copies of archived blobs, which must cover waiting for the rest and downloading them.").Default("7").Int64()
+	archivePollInterval = Cmd.Flag("archive-poll-interval", "How often to check whether archived blobs have been restored.").Default("15m").Duration()
+
 	HostCmd    = Cmd.Command("host", "Restore this host from backup")
-	ClusterCmd = Cmd.Command("cluster", "Download from multiple hosts' backups")
+	ClusterCmd = Cmd.Command("cluster", "Download from multiple hosts' backups, with the cassandra.yaml tokens and rack of each host")
+	LoadCmd    = Cmd.Command("load", "Stream tables from multiple hosts' backups into a live cluster with sstableloader")
+	SchemaCmd  = Cmd.Command("schema", "Print or apply the CQL schema backed up with a host's snapshot")
 
 	hostCmdDryRun            = HostCmd.Flag("dry-run", "Don't actually download files").Bool()
 	hostCmdAllowChangedFiles = HostCmd.Flag("allow-changed", "Allow restoration of files that changed between manifests").Bool()
-	hostCmdNotBefore         = HostCmd.Flag("not-before", "Ignore manifests before this time (unix seconds)").Int64()
-	hostCmdNotAfter          = HostCmd.Flag("not-after", "Ignore manifests after this time (unix seconds)").Int64()
+	hostCmdNotBefore         = unixtime.Flag(HostCmd.Flag("not-before", "Ignore manifests before this time "+unixtime.TimeHelp))
+	hostCmdNotAfter          = unixtime.Flag(HostCmd.Flag("not-after", "Ignore manifests after this time "+unixtime.TimeHelp))
+	hostCmdAt                = unixtime.Flag(HostCmd.Flag("at", "Restore the latest snapshot at or before this time and the manifests after it up to this time "+unixtime.TimeHelp))
 	hostCmdCluster           = HostCmd.Flag("cluster", "Use a different cluster name when selecting a backup to restore.").String()
 	hostCmdHostname          = HostCmd.Flag("hostname", "Use a specific hostname when selecting a backup to restore.").String()
 	hostCmdHostnamePattern   = HostCmd.Flag("hostname-pattern", "Use a prefix pattern when selecting a backup to restore.").String()
+	hostCmdHostID            = HostCmd.Flag("host-id", "Restore the backup of the host with this host ID, such as the node this one replaces.").String()
+	hostCmdAddress           = HostCmd.Flag("address", "Restore the backup of the host with this address, such as the node this one replaces.").String()
+	hostCmdMatchTokens       = HostCmd.Flag("match-tokens", "Restore the backup of the host with the tokens in this node's cassandra.yaml initial_token.").Bool()
+	hostCmdJournal           = HostCmd.Flag("journal", "Record the restore plan here, to be continued if the restore is interrupted (default: next to the data directory)").String()
+	hostCmdFresh             = HostCmd.Flag("fresh", "Discard the journal of an interrupted restore and plan again").Bool()
 
 	clusterCmdDryRun          = ClusterCmd.Flag("dry-run", "Don't actually download files").Bool()
 	clusterCmdTargetDirectory = ClusterCmd.Flag("target", "A subdirectory will be created under this for each host.").Required().String()
-	clusterCmdNotBefore       = ClusterCmd.Flag("not-before", "Ignore manifests before this time (unix seconds)").Int64()
-	clusterCmdNotAfter        = ClusterCmd.Flag("not-after", "Ignore manifests after this time (unix seconds)").Int64()
+	clusterCmdNotBefore       = unixtime.Flag(ClusterCmd.Flag("not-before", "Ignore manifests before this time "+unixtime.TimeHelp))
+	clusterCmdNotAfter        = unixtime.Flag(ClusterCmd.Flag("not-after", "Ignore manifests after this time "+unixtime.TimeHelp))
+	clusterCmdAt              = unixtime.Flag(ClusterCmd.Flag("at", "Download the latest snapshot at or before this time and the manifests after it up to this time "+unixtime.TimeHelp))
 	clusterCmdCluster         = ClusterCmd.Flag("cluster", "Download files for hosts in this cluster").Required().String()
 	clusterCmdHostnamePattern = ClusterCmd.Flag("hostname-pattern", "Download for hosts matching this prefix.").Required().String()
 	clusterCmdTables          = ClusterCmd.Flag("table", "Download files for these tables (keyspace.table)").Required().Strings()
 	clusterCmdSkipIndexes     = ClusterCmd.Flag("skip-indexes", "Skip downloading indexes").Default("True").Bool()
+	clusterCmdJournal         = ClusterCmd.Flag("journal", "Record the download plan here, to be continued if the download is interrupted (default: next to the target directory)").String()
+	clusterCmdFresh           = ClusterCmd.Flag("fresh", "Discard the journal of an interrupted download and plan again").Bool()
+
+	loadCmdDryRun          = LoadCmd.Flag("dry-run", "Don't actually download or load files").Bool()
+	loadCmdTargetDirectory = LoadCmd.Flag("target", "Working directory to download into. A subdirectory will be created under this for each host.").Required().String()
+	loadCmdNotBefore       = unixtime.Flag(LoadCmd.Flag("not-before", "Ignore manifests before this time "+unixtime.TimeHelp))
+	loadCmdNotAfter        = unixtime.Flag(LoadCmd.Flag("not-after", "Ignore manifests after this time "+unixtime.TimeHelp))
+	loadCmdAt              = unixtime.Flag(LoadCmd.Flag("at", "Load the latest snapshot at or before this time and the manifests after it up to this time "+unixtime.TimeHelp))
+	loadCmdCluster         = LoadCmd.Flag("cluster", "Load tables from hosts in this cluster").Required().String()
+	loadCmdHostnamePattern = LoadCmd.Flag("hostname-pattern", "Load from hosts matching this prefix.").Required().String()
+	loadCmdTables          = LoadCmd.Flag("table", "Load these tables (keyspace.table)").Required().Strings()
+	loadCmdKeepFiles       = LoadCmd.Flag("keep-files", "Keep the downloaded files of each table after loading it").Bool()
+	loadCmdLoaderHosts     = LoadCmd.Flag("loader-host", "Contact point in the target cluster").Required().Strings()
+	loadCmdLoaderPort      = LoadCmd.Flag("loader-port", "Native transport port of the target cluster").Int()
+	loadCmdLoaderUsername  = LoadCmd.Flag("loader-username", "Username for the target cluster").String()
+	loadCmdLoaderPassword  = LoadCmd.Flag("loader-password", "Password for the target cluster").Envar("SSTABLELOADER_PASSWORD").String()
+	loadCmdLoaderThrottle  = LoadCmd.Flag("loader-throttle", "Limit streaming to this many megabits per second").Int()
+	loadCmdLoaderArgs      = LoadCmd.Flag("loader-arg", "Extra argument to pass to sstableloader").Strings()
+
+	schemaCmdNotAfter        = unixtime.Flag(SchemaCmd.Flag("not-after", "Ignore manifests after this time "+unixtime.TimeHelp))
+	schemaCmdAt              = unixtime.Flag(SchemaCmd.Flag("at", "Use the schema of the latest snapshot at or before this time "+unixtime.TimeHelp))
+	schemaCmdCluster         = SchemaCmd.Flag("cluster", "Use a different cluster name when selecting a backup.").String()
+	schemaCmdHostname        = SchemaCmd.Flag("hostname", "Use a specific hostname when selecting a backup.").String()
+	schemaCmdHostnamePattern = SchemaCmd.Flag("hostname-pattern", "Use a prefix pattern when selecting a backup.").String()
+	schemaCmdApply           = SchemaCmd.Flag("apply", "Run the schema against a cluster instead of printing it").Bool()
+	schemaCmdHosts           = SchemaCmd.Flag("host", "Contact point in the target cluster").Strings()
+	schemaCmdPort            = SchemaCmd.Flag("port", "Native transport port of the target cluster").Int()
+	schemaCmdUsername        = SchemaCmd.Flag("username", "Username for the target cluster").String()
+	schemaCmdPassword        = SchemaCmd.Flag("password", "Password for the target cluster").Envar("CASSANDRA_PASSWORD").String()
 )
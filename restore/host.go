@@ -17,11 +17,12 @@ package restore
 import (
 	"context"
 	"errors"
+	"slices"
 
+	"github.com/retailnext/cassandrabackup/bucket"
 	"github.com/retailnext/cassandrabackup/manifests"
 	"github.com/retailnext/cassandrabackup/nodeidentity"
 	"github.com/retailnext/cassandrabackup/restore/plan"
-	"github.com/retailnext/cassandrabackup/unixtime"
 	"go.uber.org/zap"
 )
 
@@ -31,23 +32,74 @@ var (
 	ChangesDetected  = errors.New("file changes detected")
 )
 
+// hostDataDirectory is where RestoreHost restores files to.
+const hostDataDirectory = "/var/lib/cassandra/data"
+
 func RestoreHost(ctx context.Context) error {
-	identity := nodeidentity.ForRestore(ctx, hostCmdCluster, hostCmdHostname, hostCmdHostnamePattern)
+	lgr := zap.S()
+	journalFile := journalPath(*hostCmdJournal, hostDataDirectory)
+	j, err := openJournal(journalFile, "host", hostDataDirectory, *hostCmdFresh)
+	if err != nil {
+		return err
+	}
+	resumed := j != nil
+	if resumed {
+		j.logResume(lgr, journalFile)
+	} else if j, err = planHost(ctx); err != nil {
+		return err
+	}
+
+	if *hostCmdDryRun {
+		for name, file := range j.Files {
+			lgr.Infow("would_download", "name", name, "digest", file.Digest)
+		}
+		return thawArchived(ctx, bucket.OpenShared(), j.files(), true)
+	}
+
+	w := newWorker(hostDataDirectory, true)
+	if !resumed {
+		if err := j.statFiles(ctx, w.client); err != nil {
+			return err
+		}
+		if err := j.write(journalFile); err != nil {
+			return err
+		}
+	}
+	if err := thawArchived(ctx, w.client, j.files(), false); err != nil {
+		return err
+	}
+	w.sizes = j.sizes()
+	if err := w.restoreFiles(ctx, j.files()); err != nil {
+		return err
+	}
+	return removeJournal(journalFile)
+}
+
+func planHost(ctx context.Context) (*journal, error) {
+	identity, err := hostIdentity(ctx)
+	if err != nil {
+		return nil, err
+	}
 	lgr := zap.S().With("identity", identity)
 
-	nodePlan, err := plan.Create(ctx, identity, unixtime.Seconds(*hostCmdNotBefore), unixtime.Seconds(*hostCmdNotAfter))
+	notAfter, err := plan.NotAfter(*hostCmdAt, *hostCmdNotAfter)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	nodePlan, err := plan.Create(ctx, identity, *hostCmdNotBefore, notAfter)
+	if err != nil {
+		return nil, err
 	}
 
 	if len(nodePlan.SelectedManifests) == 0 {
-		return NoBackupsFound
+		return nil, NoBackupsFound
 	}
 	if nodePlan.SelectedManifests[0].ManifestType != manifests.ManifestTypeSnapshot {
-		return NoSnapshotsFound
+		return nil, NoSnapshotsFound
 	}
 
-	lgr.Infow("selected_manifests", "base", nodePlan.SelectedManifests[0], "additional", nodePlan.SelectedManifests[1:])
+	nodePlan.LogSelected(lgr)
+	checkTokens(lgr, nodePlan.Base.Tokens, nodeidentity.ConfiguredTokens())
 
 	if len(nodePlan.ChangedFiles) > 0 {
 		for name, history := range nodePlan.ChangedFiles {
@@ -56,17 +108,46 @@ func RestoreHost(ctx context.Context) error {
 			}
 		}
 		if !*hostCmdAllowChangedFiles {
-			return ChangesDetected
+			return nil, ChangesDetected
 		}
 	}
 
-	if *hostCmdDryRun {
-		for name, file := range nodePlan.Files {
-			lgr.Infow("would_download", "name", name, "digest", file)
+	j := newJournal("host", hostDataDirectory)
+	j.addHost(identity, nodePlan)
+	j.addFiles(nodePlan.Files)
+	return j, nil
+}
+
+// hostIdentity selects the host to restore from. Matching by host ID, address or tokens finds the
+// backup of a node this one replaces without knowing its hostname.
+func hostIdentity(ctx context.Context) (manifests.NodeIdentity, error) {
+	var match nodeidentity.Match
+	match.HostID = *hostCmdHostID
+	match.Address = *hostCmdAddress
+	if *hostCmdMatchTokens {
+		if match.Tokens = nodeidentity.ConfiguredTokens(); len(match.Tokens) == 0 {
+			return manifests.NodeIdentity{}, errors.New("--match-tokens requires initial_token in cassandra.yaml")
 		}
-		return nil
 	}
+	if match.HostID == "" && match.Address == "" && len(match.Tokens) == 0 {
+		return nodeidentity.ForRestore(ctx, hostCmdCluster, hostCmdHostname, hostCmdHostnamePattern), nil
+	}
+	cluster := *hostCmdCluster
+	if cluster == "" {
+		cluster = nodeidentity.ConfiguredCluster()
+	}
+	return nodeidentity.ForRestoreMatching(ctx, cluster, match)
+}
 
-	w := newWorker("/var/lib/cassandra/data", true)
-	return w.restoreFiles(ctx, nodePlan.Files)
+// checkTokens warns if this node won't own the tokens of the backup being restored, in which case
+// it would serve the restored data for the wrong ranges.
+func checkTokens(lgr *zap.SugaredLogger, restored, configured []string) {
+	switch {
+	case len(restored) == 0:
+	case len(configured) == 0:
+		lgr.Warnw("restore_without_initial_token", "restored_tokens", len(restored),
+			"hint", "set initial_token in cassandra.yaml to the restored tokens before starting cassandra")
+	case !slices.Equal(restored, configured):
+		lgr.Warnw("restore_tokens_mismatch", "restored_tokens", len(restored), "configured_tokens", len(configured))
+	}
 }
@@ -0,0 +1,204 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:generate go run github.com/mailru/easyjson/easyjson -disallow_unknown_fields $GOFILE
+
+package restore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/retailnext/cassandrabackup/bucket"
+	"github.com/retailnext/cassandrabackup/digest"
+	"github.com/retailnext/cassandrabackup/manifests"
+	"github.com/retailnext/cassandrabackup/restore/plan"
+	"github.com/retailnext/cassandrabackup/unixtime"
+	"github.com/retailnext/writefile"
+	"go.uber.org/zap"
+)
+
+// journalSuffix names the default journal file, which is kept next to the target directory
+// rather than in it so that it doesn't end up among the restored files.
+const journalSuffix = ".restore-journal.json"
+
+// journal records what a restore selected before it starts downloading, so that rerunning an
+// interrupted restore continues the same plan even if newer backups have been made since.
+// It is removed once the restore completes.
+//
+//easyjson:json
+type journal struct {
+	Command   string                 `json:"command"`
+	Target    string                 `json:"target"`
+	CreatedAt unixtime.Seconds       `json:"created_at"`
+	Hosts     []journalHost          `json:"hosts"`
+	Files     map[string]journalFile `json:"files"`
+}
+
+//easyjson:json
+type journalHost struct {
+	Cluster     string   `json:"cluster"`
+	Hostname    string   `json:"hostname"`
+	Manifests   []string `json:"manifests"`
+	HostID      string   `json:"host_id,omitempty"`
+	DataCenter  string   `json:"data_center,omitempty"`
+	Rack        string   `json:"rack,omitempty"`
+	Partitioner string   `json:"partitioner,omitempty"`
+	Tokens      []string `json:"tokens,omitempty"`
+}
+
+// journalFile is a file to restore and the stored size of its blob, which progress is measured in.
+//
+//easyjson:json
+type journalFile struct {
+	Digest digest.ForRestore `json:"digest"`
+	Size   int64             `json:"size"`
+}
+
+func newJournal(command, target string) *journal {
+	return &journal{
+		Command:   command,
+		Target:    target,
+		CreatedAt: unixtime.Now(),
+		Files:     make(map[string]journalFile),
+	}
+}
+
+// journalPath returns the journal file to use for a restore into target.
+func journalPath(flagValue, target string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	return filepath.Clean(target) + journalSuffix
+}
+
+// openJournal reads the journal left by an earlier run of the same restore, if there is one.
+// It returns nil if there is no journal, or if fresh is set, in which case any journal is discarded.
+func openJournal(path, command, target string, fresh bool) (*journal, error) {
+	if fresh {
+		if err := removeJournal(path); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var j journal
+	if err := j.UnmarshalJSON(data); err != nil {
+		return nil, fmt.Errorf("invalid restore journal %s: %w", path, err)
+	}
+	if j.Command != command || j.Target != target {
+		return nil, fmt.Errorf("restore journal %s is for restore %s into %s; use --fresh to discard it", path, j.Command, j.Target)
+	}
+	return &j, nil
+}
+
+func removeJournal(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// write replaces the journal file atomically.
+func (j *journal) write(path string) error {
+	data, err := j.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	config := writefile.Config{
+		Directory: filepath.Dir(abs),
+		FileMode:  0o600,
+	}
+	return config.WriteFile(filepath.Base(abs), func(file *os.File) error {
+		_, writeErr := file.Write(data)
+		return writeErr
+	})
+}
+
+func (j *journal) addHost(identity manifests.NodeIdentity, nodePlan plan.NodePlan) {
+	host := journalHost{
+		Cluster:     identity.Cluster,
+		Hostname:    identity.Hostname,
+		HostID:      nodePlan.Base.HostID,
+		DataCenter:  nodePlan.Base.DataCenter,
+		Rack:        nodePlan.Base.Rack,
+		Partitioner: nodePlan.Base.Partitioner,
+		Tokens:      nodePlan.Base.Tokens,
+	}
+	for _, key := range nodePlan.SelectedManifests {
+		host.Manifests = append(host.Manifests, key.FileName())
+	}
+	j.Hosts = append(j.Hosts, host)
+}
+
+func (j *journal) addFiles(files map[string]digest.ForRestore) {
+	for name, file := range files {
+		j.Files[name] = journalFile{Digest: file}
+	}
+}
+
+// statFiles records the stored size of each file's blob.
+func (j *journal) statFiles(ctx context.Context, client bucket.Client) error {
+	seen := make(map[digest.ForRestore]struct{})
+	var blobs []digest.ForRestore
+	for _, file := range j.Files {
+		if _, ok := seen[file.Digest]; !ok {
+			seen[file.Digest] = struct{}{}
+			blobs = append(blobs, file.Digest)
+		}
+	}
+	sizes, err := bucket.StatBlobs(ctx, client, blobs)
+	if err != nil {
+		return err
+	}
+	for name, file := range j.Files {
+		file.Size = sizes[file.Digest]
+		j.Files[name] = file
+	}
+	return nil
+}
+
+func (j *journal) files() map[string]digest.ForRestore {
+	result := make(map[string]digest.ForRestore, len(j.Files))
+	for name, file := range j.Files {
+		result[name] = file.Digest
+	}
+	return result
+}
+
+func (j *journal) sizes() map[string]int64 {
+	result := make(map[string]int64, len(j.Files))
+	for name, file := range j.Files {
+		result[name] = file.Size
+	}
+	return result
+}
+
+func (j *journal) logResume(lgr *zap.SugaredLogger, path string) {
+	for _, host := range j.Hosts {
+		lgr.Infow("journal_host", "cluster", host.Cluster, "hostname", host.Hostname, "manifests", host.Manifests)
+	}
+	lgr.Infow("resume_restore_journal", "journal", path, "created_at", j.CreatedAt, "hosts", len(j.Hosts), "files", len(j.Files))
+}
@@ -0,0 +1,482 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package restore
+
+import (
+	json "encoding/json"
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjson8c30ca37DecodeGithubComRetailnextCassandrabackupRestore(in *jlexer.Lexer, out *journalHost) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "cluster":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Cluster = string(in.String())
+			}
+		case "hostname":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Hostname = string(in.String())
+			}
+		case "manifests":
+			if in.IsNull() {
+				in.Skip()
+				out.Manifests = nil
+			} else {
+				in.Delim('[')
+				if out.Manifests == nil {
+					if !in.IsDelim(']') {
+						out.Manifests = make([]string, 0, 4)
+					} else {
+						out.Manifests = []string{}
+					}
+				} else {
+					out.Manifests = (out.Manifests)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v1 = string(in.String())
+					}
+					out.Manifests = append(out.Manifests, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "host_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.HostID = string(in.String())
+			}
+		case "data_center":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.DataCenter = string(in.String())
+			}
+		case "rack":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Rack = string(in.String())
+			}
+		case "partitioner":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Partitioner = string(in.String())
+			}
+		case "tokens":
+			if in.IsNull() {
+				in.Skip()
+				out.Tokens = nil
+			} else {
+				in.Delim('[')
+				if out.Tokens == nil {
+					if !in.IsDelim(']') {
+						out.Tokens = make([]string, 0, 4)
+					} else {
+						out.Tokens = []string{}
+					}
+				} else {
+					out.Tokens = (out.Tokens)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v2 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v2 = string(in.String())
+					}
+					out.Tokens = append(out.Tokens, v2)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson8c30ca37EncodeGithubComRetailnextCassandrabackupRestore(out *jwriter.Writer, in journalHost) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"cluster\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Cluster))
+	}
+	{
+		const prefix string = ",\"hostname\":"
+		out.RawString(prefix)
+		out.String(string(in.Hostname))
+	}
+	{
+		const prefix string = ",\"manifests\":"
+		out.RawString(prefix)
+		if in.Manifests == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v3, v4 := range in.Manifests {
+				if v3 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v4))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.HostID != "" {
+		const prefix string = ",\"host_id\":"
+		out.RawString(prefix)
+		out.String(string(in.HostID))
+	}
+	if in.DataCenter != "" {
+		const prefix string = ",\"data_center\":"
+		out.RawString(prefix)
+		out.String(string(in.DataCenter))
+	}
+	if in.Rack != "" {
+		const prefix string = ",\"rack\":"
+		out.RawString(prefix)
+		out.String(string(in.Rack))
+	}
+	if in.Partitioner != "" {
+		const prefix string = ",\"partitioner\":"
+		out.RawString(prefix)
+		out.String(string(in.Partitioner))
+	}
+	if len(in.Tokens) != 0 {
+		const prefix string = ",\"tokens\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v5, v6 := range in.Tokens {
+				if v5 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v6))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v journalHost) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson8c30ca37EncodeGithubComRetailnextCassandrabackupRestore(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v journalHost) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson8c30ca37EncodeGithubComRetailnextCassandrabackupRestore(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *journalHost) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson8c30ca37DecodeGithubComRetailnextCassandrabackupRestore(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *journalHost) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson8c30ca37DecodeGithubComRetailnextCassandrabackupRestore(l, v)
+}
+func easyjson8c30ca37DecodeGithubComRetailnextCassandrabackupRestore1(in *jlexer.Lexer, out *journalFile) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "digest":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				(out.Digest).UnmarshalEasyJSON(in)
+			}
+		case "size":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Size = int64(in.Int64())
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson8c30ca37EncodeGithubComRetailnextCassandrabackupRestore1(out *jwriter.Writer, in journalFile) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"digest\":"
+		out.RawString(prefix[1:])
+		(in.Digest).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"size\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Size))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v journalFile) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson8c30ca37EncodeGithubComRetailnextCassandrabackupRestore1(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v journalFile) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson8c30ca37EncodeGithubComRetailnextCassandrabackupRestore1(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *journalFile) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson8c30ca37DecodeGithubComRetailnextCassandrabackupRestore1(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *journalFile) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson8c30ca37DecodeGithubComRetailnextCassandrabackupRestore1(l, v)
+}
+func easyjson8c30ca37DecodeGithubComRetailnextCassandrabackupRestore2(in *jlexer.Lexer, out *journal) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "command":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Command = string(in.String())
+			}
+		case "target":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Target = string(in.String())
+			}
+		case "created_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				(out.CreatedAt).UnmarshalEasyJSON(in)
+			}
+		case "hosts":
+			if in.IsNull() {
+				in.Skip()
+				out.Hosts = nil
+			} else {
+				in.Delim('[')
+				if out.Hosts == nil {
+					if !in.IsDelim(']') {
+						out.Hosts = make([]journalHost, 0, 0)
+					} else {
+						out.Hosts = []journalHost{}
+					}
+				} else {
+					out.Hosts = (out.Hosts)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v7 journalHost
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v7).UnmarshalEasyJSON(in)
+					}
+					out.Hosts = append(out.Hosts, v7)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "files":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				out.Files = make(map[string]journalFile)
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v8 journalFile
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v8).UnmarshalEasyJSON(in)
+					}
+					(out.Files)[key] = v8
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson8c30ca37EncodeGithubComRetailnextCassandrabackupRestore2(out *jwriter.Writer, in journal) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"command\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Command))
+	}
+	{
+		const prefix string = ",\"target\":"
+		out.RawString(prefix)
+		out.String(string(in.Target))
+	}
+	{
+		const prefix string = ",\"created_at\":"
+		out.RawString(prefix)
+		(in.CreatedAt).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"hosts\":"
+		out.RawString(prefix)
+		if in.Hosts == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v9, v10 := range in.Hosts {
+				if v9 > 0 {
+					out.RawByte(',')
+				}
+				(v10).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"files\":"
+		out.RawString(prefix)
+		if in.Files == nil && (out.Flags&jwriter.NilMapAsEmpty) == 0 {
+			out.RawString(`null`)
+		} else {
+			out.RawByte('{')
+			v11First := true
+			for v11Name, v11Value := range in.Files {
+				if v11First {
+					v11First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v11Name))
+				out.RawByte(':')
+				(v11Value).MarshalEasyJSON(out)
+			}
+			out.RawByte('}')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v journal) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson8c30ca37EncodeGithubComRetailnextCassandrabackupRestore2(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v journal) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson8c30ca37EncodeGithubComRetailnextCassandrabackupRestore2(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *journal) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson8c30ca37DecodeGithubComRetailnextCassandrabackupRestore2(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *journal) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson8c30ca37DecodeGithubComRetailnextCassandrabackupRestore2(l, v)
+}
@@ -0,0 +1,93 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/retailnext/cassandrabackup/digest"
+	"github.com/retailnext/cassandrabackup/manifests"
+	"github.com/retailnext/cassandrabackup/restore/plan"
+)
+
+func TestJournal(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target")
+	path := journalPath("", target+"/")
+	if path != target+journalSuffix {
+		t.Fatalf("unexpected journal path %q", path)
+	}
+
+	if j, err := openJournal(path, "cluster", target, false); err != nil || j != nil {
+		t.Fatalf("expected no journal, got %v %v", j, err)
+	}
+
+	a, b := testDigest(t, "B"), testDigest(t, "C")
+	j := newJournal("cluster", target)
+	j.addHost(manifests.NodeIdentity{Cluster: "c", Hostname: "h1"}, plan.NodePlan{
+		SelectedManifests: manifests.ManifestKeys{
+			{Time: 100, ManifestType: manifests.ManifestTypeSnapshot},
+			{Time: 200, ManifestType: manifests.ManifestTypeIncremental},
+		},
+	})
+	j.addFiles(map[string]digest.ForRestore{
+		"h1/ks/t-1/nb-1-big-Data.db":  a,
+		"h1/ks/t-1/nb-1-big-Index.db": b,
+	})
+	j.Files["h1/ks/t-1/nb-1-big-Data.db"] = journalFile{Digest: a, Size: 1000}
+	if err := j.write(path); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := openJournal(path, "cluster", target, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal(got, j); diff != nil {
+		t.Error(diff)
+	}
+	if diff := deep.Equal(got.Hosts[0].Manifests, []string{"00000000000000000100.1.json", "00000000000000000200.3.json"}); diff != nil {
+		t.Error(diff)
+	}
+	if diff := deep.Equal(got.files(), map[string]digest.ForRestore{
+		"h1/ks/t-1/nb-1-big-Data.db":  a,
+		"h1/ks/t-1/nb-1-big-Index.db": b,
+	}); diff != nil {
+		t.Error(diff)
+	}
+	if diff := deep.Equal(got.sizes(), map[string]int64{
+		"h1/ks/t-1/nb-1-big-Data.db":  1000,
+		"h1/ks/t-1/nb-1-big-Index.db": 0,
+	}); diff != nil {
+		t.Error(diff)
+	}
+
+	if _, err := openJournal(path, "host", target, false); err == nil {
+		t.Error("expected an error opening a journal for a different command")
+	}
+	if _, err := openJournal(path, "cluster", dir, false); err == nil {
+		t.Error("expected an error opening a journal for a different target")
+	}
+
+	if j, err := openJournal(path, "host", dir, true); err != nil || j != nil {
+		t.Fatalf("expected fresh to discard the journal, got %v %v", j, err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected the journal to be removed, got %v", err)
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/retailnext/cassandrabackup/bucket"
+	"github.com/retailnext/cassandrabackup/digest"
+	"github.com/retailnext/cassandrabackup/manifests"
+	"github.com/retailnext/cassandrabackup/restore/plan"
+	"github.com/retailnext/cassandrabackup/sstableloader"
+	"github.com/retailnext/cassandrabackup/unixtime"
+	"go.uber.org/zap"
+)
+
+// loadedSuffix names the marker file written next to a table's directory once it has been loaded,
+// so that rerunning a load after a failure skips the tables that made it.
+const loadedSuffix = ".loaded"
+
+// hostLoad tracks the progress of loading one source host's tables.
+type hostLoad struct {
+	hostname string
+	tables   int
+	files    int
+	loaded   int
+	skipped  int
+	err      error
+}
+
+func (h hostLoad) log(lgr *zap.SugaredLogger) {
+	if h.err != nil {
+		lgr.Errorw("load_host_result", "tables", h.tables, "files", h.files, "loaded", h.loaded, "skipped", h.skipped, "err", h.err)
+		return
+	}
+	lgr.Infow("load_host_result", "tables", h.tables, "files", h.files, "loaded", h.loaded, "skipped", h.skipped)
+}
+
+// RestoreLoad downloads the selected tables of each source host and streams them into a live cluster,
+// which may have a different number of nodes or token assignments, with sstableloader.
+func RestoreLoad(ctx context.Context) error {
+	lgr := zap.S()
+	registerMetrics()
+
+	filter := plan.Filter{}
+	filter.Build(*loadCmdTables)
+
+	notAfter, err := plan.NotAfter(*loadCmdAt, *loadCmdNotAfter)
+	if err != nil {
+		return err
+	}
+
+	options := sstableloader.Options{
+		Hosts:         *loadCmdLoaderHosts,
+		Port:          *loadCmdLoaderPort,
+		Username:      *loadCmdLoaderUsername,
+		Password:      *loadCmdLoaderPassword,
+		ThrottleMbits: *loadCmdLoaderThrottle,
+		ExtraArgs:     *loadCmdLoaderArgs,
+	}
+
+	identities := nodeIdentitiesForCluster(ctx, loadCmdCluster, loadCmdHostnamePattern)
+	lgr.Infow("selected_hosts", "identities", identities)
+
+	var failed []string
+	for _, hostIdentity := range identities {
+		hostLgr := lgr.With("identity", hostIdentity)
+		result := loadHost(ctx, hostLgr, hostIdentity, filter, notAfter, options)
+		result.log(hostLgr)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if result.err != nil {
+			loadHostErrors.Inc()
+			failed = append(failed, result.hostname)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d hosts failed to load: %s", len(failed), len(identities), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func loadHost(ctx context.Context, lgr *zap.SugaredLogger, identity manifests.NodeIdentity, filter plan.Filter, notAfter unixtime.Seconds, options sstableloader.Options) hostLoad {
+	result := hostLoad{hostname: identity.Hostname}
+
+	nodePlan, err := plan.Create(ctx, identity, *loadCmdNotBefore, notAfter)
+	if err != nil {
+		result.err = err
+		return result
+	}
+	if len(nodePlan.SelectedManifests) == 0 || nodePlan.SelectedManifests[0].ManifestType != manifests.ManifestTypeSnapshot {
+		result.err = fmt.Errorf("no snapshots found")
+		return result
+	}
+	nodePlan.LogSelected(lgr)
+	nodePlan.Filter(filter)
+
+	tables, err := loadTables(nodePlan.Files)
+	if err != nil {
+		result.err = err
+		return result
+	}
+	result.tables = len(tables)
+
+	hostDirectory := filepath.Join(*loadCmdTargetDirectory, identity.Hostname)
+	toLoad := make(map[string]digest.ForRestore)
+	for table, files := range tables {
+		if _, statErr := os.Stat(filepath.Join(hostDirectory, filepath.FromSlash(table)) + loadedSuffix); statErr == nil {
+			continue
+		}
+		for name, file := range files {
+			toLoad[table+"/"+name] = file
+		}
+	}
+	if err := thawArchived(ctx, bucket.OpenShared(), toLoad, *loadCmdDryRun); err != nil {
+		result.err = err
+		return result
+	}
+	for _, table := range sortedTables(tables) {
+		files := tables[table]
+		result.files += len(files)
+		tableLgr := lgr.With("table", table)
+		tableDirectory := filepath.Join(hostDirectory, filepath.FromSlash(table))
+
+		if _, statErr := os.Stat(tableDirectory + loadedSuffix); statErr == nil {
+			tableLgr.Infow("table_already_loaded")
+			loadTablesSkipped.Inc()
+			result.skipped++
+			continue
+		}
+		if *loadCmdDryRun {
+			for name, file := range files {
+				tableLgr.Infow("would_download", "name", name, "digest", file)
+			}
+			tableLgr.Infow("would_load", "directory", tableDirectory)
+			continue
+		}
+
+		if err := newWorker(hostDirectory, false).restoreFiles(ctx, files); err != nil {
+			result.err = fmt.Errorf("download %s: %w", table, err)
+			return result
+		}
+		if err := sstableloader.Load(ctx, options, tableDirectory); err != nil {
+			loadTablesFailed.Inc()
+			result.err = fmt.Errorf("load %s: %w", table, err)
+			return result
+		}
+		loadTablesLoaded.Inc()
+		result.loaded++
+
+		if err := os.WriteFile(tableDirectory+loadedSuffix, nil, 0o644); err != nil {
+			result.err = err
+			return result
+		}
+		if !*loadCmdKeepFiles {
+			if err := os.RemoveAll(tableDirectory); err != nil {
+				tableLgr.Warnw("remove_loaded_files_error", "err", err)
+			}
+		}
+	}
+	return result
+}
+
+// loadTables groups a node plan's files by the "keyspace/table" directory sstableloader needs them in,
+// which drops the table id from the directory name. Files of secondary indexes must already be filtered
+// out, since sstableloader rebuilds indexes on the target cluster.
+func loadTables(files map[string]digest.ForRestore) (map[string]map[string]digest.ForRestore, error) {
+	tables := make(map[string]map[string]digest.ForRestore)
+	tableIDs := make(map[string]string)
+	for name, file := range files {
+		keyspace, table, ok := plan.Table(name)
+		parts := strings.Split(name, "/")
+		if !ok || len(parts) != 3 {
+			return nil, fmt.Errorf("unexpected file name %q", name)
+		}
+		directory := path.Join(keyspace, table)
+		if tableID, seen := tableIDs[directory]; seen && tableID != parts[1] {
+			return nil, fmt.Errorf("files from more than one table id for %s: %s and %s", directory, tableID, parts[1])
+		}
+		tableIDs[directory] = parts[1]
+
+		if tables[directory] == nil {
+			tables[directory] = make(map[string]digest.ForRestore)
+		}
+		tables[directory][path.Join(directory, parts[2])] = file
+	}
+	return tables, nil
+}
+
+func sortedTables(tables map[string]map[string]digest.ForRestore) []string {
+	result := make([]string, 0, len(tables))
+	for table := range tables {
+		result = append(result, table)
+	}
+	sort.Strings(result)
+	return result
+}
+
+var (
+	loadTablesLoaded = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "cassandrabackup",
+		Subsystem: "restore",
+		Name:      "load_tables_loaded_total",
+		Help:      "Number of tables streamed into the target cluster with sstableloader.",
+	})
+	loadTablesSkipped = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "cassandrabackup",
+		Subsystem: "restore",
+		Name:      "load_tables_skipped_total",
+		Help:      "Number of tables skipped during a load due to having been loaded already.",
+	})
+	loadTablesFailed = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "cassandrabackup",
+		Subsystem: "restore",
+		Name:      "load_tables_failed_total",
+		Help:      "Number of tables that sstableloader failed to stream into the target cluster.",
+	})
+	loadHostErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "cassandrabackup",
+		Subsystem: "restore",
+		Name:      "load_host_errors_total",
+		Help:      "Number of source hosts whose tables failed to load.",
+	})
+)
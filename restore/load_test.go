@@ -0,0 +1,70 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/retailnext/cassandrabackup/digest"
+)
+
+func testDigest(t *testing.T, c string) digest.ForRestore {
+	var d digest.ForRestore
+	if err := d.UnmarshalText([]byte(strings.Repeat(c, 86) + "==")); err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestLoadTables(t *testing.T) {
+	a, b, c := testDigest(t, "B"), testDigest(t, "C"), testDigest(t, "D")
+	tables, err := loadTables(map[string]digest.ForRestore{
+		"ks/users-0123/nb-1-big-Data.db":  a,
+		"ks/users-0123/nb-1-big-Index.db": b,
+		"ks/events-4567/nb-2-big-Data.db": c,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]map[string]digest.ForRestore{
+		"ks/users": {
+			"ks/users/nb-1-big-Data.db":  a,
+			"ks/users/nb-1-big-Index.db": b,
+		},
+		"ks/events": {
+			"ks/events/nb-2-big-Data.db": c,
+		},
+	}
+	if diff := deep.Equal(tables, expected); diff != nil {
+		t.Error(diff)
+	}
+	if diff := deep.Equal(sortedTables(tables), []string{"ks/events", "ks/users"}); diff != nil {
+		t.Error(diff)
+	}
+
+	if _, err := loadTables(map[string]digest.ForRestore{
+		"ks/users-0123/nb-1-big-Data.db": a,
+		"ks/users-89ab/nb-1-big-Data.db": b,
+	}); err == nil {
+		t.Error("expected an error for a table with files from two table ids")
+	}
+	if _, err := loadTables(map[string]digest.ForRestore{
+		"ks/users-0123/.users_email/nb-1-big-Data.db": a,
+	}); err == nil {
+		t.Error("expected an error for index files")
+	}
+}
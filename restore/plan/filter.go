@@ -37,6 +37,20 @@ func (f *Filter) Build(tables []string) {
 	}
 }
 
+// Table returns the keyspace and table that a manifest file name, such as
+// "keyspace/table-id/nb-1-big-Data.db" or "keyspace/table-id/.index/nb-1-big-Data.db", belongs to.
+func Table(name string) (keyspace, table string, ok bool) {
+	parts := strings.Split(name, "/")
+	if len(parts) < 3 {
+		return "", "", false
+	}
+	suffixIndex := strings.LastIndex(parts[1], "-")
+	if suffixIndex < 0 {
+		return "", "", false
+	}
+	return parts[0], parts[1][:suffixIndex], true
+}
+
 func (f Filter) match(name string) bool {
 	parts := strings.Split(name, "/")
 	if len(parts) < 3 {
@@ -52,13 +66,11 @@ func (f Filter) match(name string) bool {
 			return false
 		}
 	}
-	suffixIndex := strings.LastIndex(parts[1], "-")
-	if suffixIndex < 0 {
+	keyspace, table, ok := Table(name)
+	if !ok {
 		zap.S().Panicw("unexpected_suffix_index", "name", name)
 	}
-	keyspace := parts[0]
-	table := parts[1][:suffixIndex]
-	_, ok := f.Tables[keyspace+"."+table]
+	_, ok = f.Tables[keyspace+"."+table]
 	return ok
 }
 
@@ -16,6 +16,8 @@ package plan
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/retailnext/cassandrabackup/bucket"
 	"github.com/retailnext/cassandrabackup/digest"
@@ -33,6 +35,22 @@ type NodePlan struct {
 	Files             map[string]digest.ForRestore
 	ChangedFiles      map[string][]HistoryEntry
 	SelectedManifests manifests.ManifestKeys
+	// Base is the first selected manifest, which records where the host was in the ring.
+	Base manifests.Manifest
+}
+
+var AtAndNotAfter = errors.New("--at and --not-after cannot be used together")
+
+// NotAfter returns the notAfter to list manifests with so that a restore at at, if given, includes
+// manifests taken at exactly that time.
+func NotAfter(at, notAfter unixtime.Seconds) (unixtime.Seconds, error) {
+	if at == 0 {
+		return notAfter, nil
+	}
+	if notAfter != 0 {
+		return 0, AtAndNotAfter
+	}
+	return at + 1, nil
 }
 
 func Create(ctx context.Context, identity manifests.NodeIdentity, startAfter, notAfter unixtime.Seconds) (NodePlan, error) {
@@ -47,6 +65,21 @@ func Create(ctx context.Context, identity manifests.NodeIdentity, startAfter, no
 	return assemble(nodeManifests), nil
 }
 
+// DataTime returns the time of the newest selected manifest, which is how current the restored data is.
+func (p NodePlan) DataTime() unixtime.Seconds {
+	if len(p.SelectedManifests) == 0 {
+		return 0
+	}
+	return p.SelectedManifests[len(p.SelectedManifests)-1].Time
+}
+
+// LogSelected logs the manifests selected for restore and the age of the data they hold.
+func (p NodePlan) LogSelected(lgr *zap.SugaredLogger) {
+	dataTime := p.DataTime()
+	lgr.Infow("selected_manifests", "base", p.SelectedManifests[0], "additional", p.SelectedManifests[1:],
+		"data_time", dataTime, "data_age", time.Since(dataTime.Time()).Round(time.Second))
+}
+
 func getManifests(ctx context.Context, identity manifests.NodeIdentity, startAfter, notAfter unixtime.Seconds) ([]manifests.Manifest, error) {
 	client := bucket.OpenShared()
 
@@ -55,16 +88,7 @@ func getManifests(ctx context.Context, identity manifests.NodeIdentity, startAft
 		return nil, err
 	}
 
-	snapshotIndex := -1
-	for i := len(keys) - 1; i >= 0; i-- {
-		if keys[i].ManifestType == manifests.ManifestTypeSnapshot {
-			snapshotIndex = i
-			break
-		}
-	}
-	if snapshotIndex >= 0 {
-		keys = keys[snapshotIndex:]
-	}
+	keys = keys.FromLatestSnapshot()
 
 	if len(keys) == 0 {
 		return nil, nil
@@ -72,11 +96,43 @@ func getManifests(ctx context.Context, identity manifests.NodeIdentity, startAft
 	return client.GetManifests(ctx, identity, keys)
 }
 
+// FileDiff is how the data files of one manifest differ from those of another.
+type FileDiff struct {
+	Added   map[string]digest.ForRestore
+	Removed map[string]digest.ForRestore
+	Changed map[string][]HistoryEntry
+}
+
+// Diff compares the data files of two manifests. Files in both with different digests are found the
+// same way as the changed files of a restore plan.
+func Diff(from, to manifests.Manifest) FileDiff {
+	diff := FileDiff{
+		Added:   make(map[string]digest.ForRestore),
+		Removed: make(map[string]digest.ForRestore),
+		Changed: assemble([]manifests.Manifest{from, to}).ChangedFiles,
+	}
+	for name, file := range from.DataFiles {
+		if _, ok := to.DataFiles[name]; !ok {
+			diff.Removed[name] = file
+		}
+	}
+	for name, file := range to.DataFiles {
+		if _, ok := from.DataFiles[name]; !ok {
+			diff.Added[name] = file
+		}
+	}
+	return diff
+}
+
 func assemble(nodeManifests []manifests.Manifest) NodePlan {
 	nodePlan := NodePlan{
 		SelectedManifests: make(manifests.ManifestKeys, 0, len(nodeManifests)),
 	}
 
+	if len(nodeManifests) > 0 {
+		nodePlan.Base = nodeManifests[0]
+	}
+
 	fileHistories := make(map[string][]HistoryEntry)
 	for _, manifest := range nodeManifests {
 		nodePlan.SelectedManifests = append(nodePlan.SelectedManifests, manifest.Key())
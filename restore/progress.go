@@ -0,0 +1,248 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/retailnext/cassandrabackup/digest"
+	"go.uber.org/zap"
+	"golang.org/x/term"
+)
+
+var (
+	progressLogInterval = 30 * time.Second
+	progressBarInterval = time.Second
+)
+
+// progress tracks how many of the files to restore are on disk, by count and by the stored size
+// of their blobs when that is known. Files that were already on disk count as done, but not
+// towards the throughput the ETA is estimated from.
+type progress struct {
+	sizes      map[string]int64
+	files      int64
+	bytes      int64
+	start      time.Time
+	filesDone  atomic.Int64
+	bytesDone  atomic.Int64
+	downloaded atomic.Int64
+}
+
+func newProgress(files map[string]digest.ForRestore, sizes map[string]int64) *progress {
+	p := &progress{
+		sizes: sizes,
+		files: int64(len(files)),
+		start: time.Now(),
+	}
+	for name := range files {
+		p.bytes += sizes[name]
+	}
+	progressFiles.Set(float64(p.files))
+	progressBytes.Set(float64(p.bytes))
+	progressFilesDone.Set(0)
+	progressBytesDone.Set(0)
+	remainingFiles.Set(float64(p.files))
+	remainingBytes.Set(float64(p.bytes))
+	etaSeconds.Set(-1)
+	return p
+}
+
+// done records that a file is on disk, having been downloaded rather than found already there if downloaded is set.
+func (p *progress) done(name string, downloaded bool) {
+	size := p.sizes[name]
+	if downloaded {
+		p.downloaded.Add(size)
+	}
+	filesDone := p.filesDone.Add(1)
+	bytesDone := p.bytesDone.Add(size)
+	progressFilesDone.Set(float64(filesDone))
+	progressBytesDone.Set(float64(bytesDone))
+	remainingFiles.Set(float64(p.files - filesDone))
+	remainingBytes.Set(float64(p.bytes - bytesDone))
+}
+
+type progressStatus struct {
+	filesDone, files int64
+	bytesDone, bytes int64
+	// percent is by bytes when the sizes are known, by files otherwise.
+	percent float64
+	// rate is the bytes downloaded per second so far.
+	rate float64
+	// eta is how long the rest is expected to take at that rate, or -1 if there's no estimate yet.
+	eta time.Duration
+}
+
+func (p *progress) status(now time.Time) progressStatus {
+	s := progressStatus{
+		filesDone: p.filesDone.Load(),
+		files:     p.files,
+		bytesDone: p.bytesDone.Load(),
+		bytes:     p.bytes,
+		percent:   100,
+		eta:       -1,
+	}
+	switch {
+	case s.bytes > 0:
+		s.percent = 100 * float64(s.bytesDone) / float64(s.bytes)
+	case s.files > 0:
+		s.percent = 100 * float64(s.filesDone) / float64(s.files)
+	}
+	if elapsed := now.Sub(p.start).Seconds(); elapsed > 0 {
+		s.rate = float64(p.downloaded.Load()) / elapsed
+	}
+	if remaining := s.bytes - s.bytesDone; remaining <= 0 {
+		s.eta = 0
+	} else if s.rate > 0 {
+		s.eta = time.Duration(float64(remaining) / s.rate * float64(time.Second)).Round(time.Second)
+	}
+	return s
+}
+
+func (p *progress) log(lgr *zap.SugaredLogger) {
+	s := p.status(time.Now())
+	etaSeconds.Set(s.eta.Seconds())
+	lgr.Infow("restore_progress",
+		"files_done", s.filesDone, "files", s.files,
+		"bytes_done", s.bytesDone, "bytes", s.bytes,
+		"percent", fmt.Sprintf("%.1f", s.percent),
+		"bytes_per_second", int64(s.rate),
+		"eta", s.eta.String(),
+	)
+}
+
+// report shows progress until ctx is done: as a progress bar if stdout is a terminal,
+// and as periodic log lines otherwise.
+func (p *progress) report(ctx context.Context) {
+	if term.IsTerminal(int(os.Stdout.Fd())) {
+		p.drawEvery(ctx, os.Stdout, progressBarInterval)
+		return
+	}
+	lgr := zap.S()
+	ticker := time.NewTicker(progressLogInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			p.log(lgr)
+			return
+		case <-ticker.C:
+			p.log(lgr)
+		}
+	}
+}
+
+func (p *progress) drawEvery(ctx context.Context, w io.Writer, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			_, _ = fmt.Fprintf(w, "\r%s\n", p.bar(p.status(time.Now()), 30))
+			return
+		case <-ticker.C:
+			s := p.status(time.Now())
+			etaSeconds.Set(s.eta.Seconds())
+			_, _ = fmt.Fprintf(w, "\r%s", p.bar(s, 30))
+		}
+	}
+}
+
+// bar renders a line like "[=========>          ]  45.2% 1.2GiB/2.7GiB 40.0MiB/s ETA 38s 120/300 files".
+func (p *progress) bar(s progressStatus, width int) string {
+	filled := int(s.percent / 100 * float64(width))
+	filled = min(max(filled, 0), width)
+	var b strings.Builder
+	b.WriteByte('[')
+	b.WriteString(strings.Repeat("=", filled))
+	if filled < width {
+		b.WriteByte('>')
+		b.WriteString(strings.Repeat(" ", width-filled-1))
+	}
+	b.WriteByte(']')
+	eta := "--"
+	if s.eta >= 0 {
+		eta = s.eta.String()
+	}
+	_, _ = fmt.Fprintf(&b, " %5.1f%% %s/%s %s/s ETA %s %d/%d files",
+		s.percent, formatBytes(s.bytesDone), formatBytes(s.bytes), formatBytes(int64(s.rate)), eta, s.filesDone, s.files)
+	return b.String()
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	value := float64(n)
+	for _, suffix := range []string{"KiB", "MiB", "GiB", "TiB"} {
+		value /= unit
+		if value < unit || suffix == "TiB" {
+			return fmt.Sprintf("%.1f%s", value, suffix)
+		}
+	}
+	panic("unreachable")
+}
+
+var (
+	progressFiles = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "cassandrabackup",
+		Subsystem: "restore",
+		Name:      "progress_files",
+		Help:      "Number of files the current restore will restore.",
+	})
+	progressFilesDone = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "cassandrabackup",
+		Subsystem: "restore",
+		Name:      "progress_files_done",
+		Help:      "Number of files the current restore has restored or found already on disk.",
+	})
+	progressBytes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "cassandrabackup",
+		Subsystem: "restore",
+		Name:      "progress_bytes",
+		Help:      "Stored size of the blobs the current restore will restore.",
+	})
+	progressBytesDone = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "cassandrabackup",
+		Subsystem: "restore",
+		Name:      "progress_bytes_done",
+		Help:      "Stored size of the blobs the current restore has restored or found already on disk.",
+	})
+	remainingFiles = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "cassandrabackup",
+		Subsystem: "restore",
+		Name:      "remaining_files",
+		Help:      "Number of files the current restore has yet to restore.",
+	})
+	remainingBytes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "cassandrabackup",
+		Subsystem: "restore",
+		Name:      "remaining_bytes",
+		Help:      "Stored size of the blobs the current restore has yet to restore.",
+	})
+	etaSeconds = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "cassandrabackup",
+		Subsystem: "restore",
+		Name:      "eta_seconds",
+		Help:      "Estimated time until the current restore is done, or -1 if there is no estimate yet.",
+	})
+)
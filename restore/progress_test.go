@@ -0,0 +1,62 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/retailnext/cassandrabackup/digest"
+)
+
+func TestProgress(t *testing.T) {
+	registerMetrics()
+	a, b := testDigest(t, "B"), testDigest(t, "C")
+	p := newProgress(map[string]digest.ForRestore{"w": a, "x": a, "y": b, "z": b}, map[string]int64{"w": 100, "x": 1000, "y": 2000})
+	if p.files != 4 || p.bytes != 3100 {
+		t.Fatalf("unexpected totals %d files %d bytes", p.files, p.bytes)
+	}
+	s := p.status(p.start)
+	if s.percent != 0 || s.eta != -1 {
+		t.Errorf("unexpected initial status %+v", s)
+	}
+
+	p.done("w", false)
+	p.done("y", true)
+	p.done("z", true)
+	s = p.status(p.start.Add(20 * time.Second))
+	if s.filesDone != 3 || s.bytesDone != 2100 {
+		t.Fatalf("unexpected progress %d files %d bytes", s.filesDone, s.bytesDone)
+	}
+	if s.rate != 100 {
+		t.Errorf("expected skipped files not to count towards the rate, got %v", s.rate)
+	}
+	if s.eta != 10*time.Second {
+		t.Errorf("unexpected eta %v", s.eta)
+	}
+	if bar := p.bar(s, 10); bar != "[======>   ]  67.7% 2.1KiB/3.0KiB 100B/s ETA 10s 3/4 files" {
+		t.Errorf("unexpected bar %q", bar)
+	}
+
+	p.done("x", true)
+	s = p.status(p.start.Add(30 * time.Second))
+	if s.percent != 100 || s.eta != 0 {
+		t.Errorf("unexpected final status %+v", s)
+	}
+	if bar := p.bar(s, 10); !strings.HasPrefix(bar, "[==========] 100.0% ") {
+		t.Errorf("unexpected bar %q", bar)
+	}
+}
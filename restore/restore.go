@@ -22,6 +22,7 @@ import (
 	"path"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -39,6 +40,9 @@ type worker struct {
 	cache  *digest.Cache
 	client bucket.Client
 	target writefile.Config
+	// sizes are the stored sizes of the files' blobs, if known, for reporting progress.
+	sizes    map[string]int64
+	progress *progress
 
 	limiter    chan struct{}
 	wg         sync.WaitGroup
@@ -89,7 +93,15 @@ func newWorker(directory string, ensureOwnership bool) *worker {
 func (w *worker) restoreFiles(ctx context.Context, files map[string]digest.ForRestore) error {
 	registerMetrics()
 	w.ctx = ctx
-	w.limiter = make(chan struct{}, 4)
+	w.limiter = make(chan struct{}, max(*downloadConcurrency, 1))
+	w.progress = newProgress(files, w.sizes)
+
+	progressCtx, stopProgress := context.WithCancel(ctx)
+	reported := make(chan struct{})
+	go func() {
+		w.progress.report(progressCtx)
+		close(reported)
+	}()
 
 	doneCh := ctx.Done()
 	for name, forRestore := range files {
@@ -102,6 +114,8 @@ func (w *worker) restoreFiles(ctx context.Context, files map[string]digest.ForRe
 		}
 	}
 	w.wg.Wait()
+	stopProgress()
+	<-reported
 	err := ctx.Err()
 	if err == nil {
 		if w.fileErrors != nil {
@@ -134,6 +148,7 @@ func (w *worker) restoreFile(name string, forRestore digest.ForRestore) {
 			if forUpload.ForRestore() == forRestore {
 				skippedBytes.Add(float64(maybeFile.Len()))
 				skippedFiles.Inc()
+				w.progress.done(name, false)
 				return
 			} else {
 				lgr.Infow("existing_file_digest_mismatch", "path", path)
@@ -143,31 +158,76 @@ func (w *worker) restoreFile(name string, forRestore digest.ForRestore) {
 		}
 	}
 
-	err = w.target.WriteFile(name, func(file *os.File) error {
-		start := time.Now()
-		downloadErr := w.client.DownloadBlob(w.ctx, forRestore, file)
-		if downloadErr != nil {
-			downloadErrors.Inc()
-			return downloadErr
-		}
+	err = w.downloadFile(name, forRestore)
+	if err == nil {
+		w.progress.done(name, true)
+		lgr.Infow("restored_file", "path", name)
+	}
+}
 
-		d := time.Since(start)
-		downloadFiles.Inc()
-		downloadSeconds.Add(d.Seconds())
-		if info, infoErr := file.Stat(); infoErr != nil {
-			lgr.Warnw("stat_error", "err", err)
-		} else {
-			downloadBytes.Add(float64(info.Size()))
-			// Prime the cache with this file since it's still in the kernel block cache
-			pfile := paranoid.NewFileFromInfo(file.Name(), info)
-			_, _ = w.cache.Get(w.ctx, pfile)
+// downloadFile downloads a file into a partial file next to it, named after the blob, and renames
+// it into place once it has been verified. A partial file is left behind if the download fails, so
+// that rerunning the restore continues the download from where it stopped.
+func (w *worker) downloadFile(name string, forRestore digest.ForRestore) error {
+	lgr := zap.S()
+	fullPath := filepath.Join(w.target.Directory, name)
+	if !strings.HasPrefix(fullPath, w.target.Directory+"/") {
+		return writefile.InvalidName(name)
+	}
+	directory := w.target
+	directory.Directory = filepath.Dir(fullPath)
+	if err := directory.EnsureDirectoryIfNotExist(); err != nil {
+		return err
+	}
+
+	partialPath := partialFilePath(fullPath, forRestore)
+	file, err := os.OpenFile(partialPath, os.O_RDWR|os.O_CREATE, w.target.FileMode)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if file != nil {
+			_ = file.Close()
 		}
-		return nil
-	})
+	}()
+	if err := os.Chmod(partialPath, w.target.FileMode); err != nil {
+		return err
+	}
+	if w.target.EnsureFileOwnership {
+		if err := os.Chown(partialPath, w.target.FileUID, w.target.FileGID); err != nil {
+			return err
+		}
+	}
 
-	if err == nil {
-		lgr.Infow("restored_file", "path", name)
+	start := time.Now()
+	if err := w.client.ResumeDownloadBlob(w.ctx, forRestore, file); err != nil {
+		downloadErrors.Inc()
+		return err
+	}
+	d := time.Since(start)
+	downloadFiles.Inc()
+	downloadSeconds.Add(d.Seconds())
+	if info, infoErr := file.Stat(); infoErr != nil {
+		lgr.Warnw("stat_error", "err", infoErr)
+	} else {
+		downloadBytes.Add(float64(info.Size()))
+		// Prime the cache with this file since it's still in the kernel block cache
+		pfile := paranoid.NewFileFromInfo(file.Name(), info)
+		_, _ = w.cache.Get(w.ctx, pfile)
+	}
+
+	closeErr := file.Close()
+	file = nil
+	if closeErr != nil {
+		return closeErr
 	}
+	return os.Rename(partialPath, fullPath)
+}
+
+// partialFilePath names the partial download of a blob to path. Including part of the digest keeps a
+// partial download of a different version of the file from being continued.
+func partialFilePath(path string, forRestore digest.ForRestore) string {
+	return filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+"."+forRestore.URLSafe()[:16]+".partial~")
 }
 
 var (
@@ -219,6 +279,17 @@ func registerMetrics() {
 		prometheus.MustRegister(downloadFiles)
 		prometheus.MustRegister(downloadBytes)
 		prometheus.MustRegister(downloadErrors)
+		prometheus.MustRegister(loadTablesLoaded)
+		prometheus.MustRegister(loadTablesSkipped)
+		prometheus.MustRegister(loadTablesFailed)
+		prometheus.MustRegister(loadHostErrors)
+		prometheus.MustRegister(progressFiles)
+		prometheus.MustRegister(progressFilesDone)
+		prometheus.MustRegister(progressBytes)
+		prometheus.MustRegister(progressBytesDone)
+		prometheus.MustRegister(remainingFiles)
+		prometheus.MustRegister(remainingBytes)
+		prometheus.MustRegister(etaSeconds)
 	})
 }
 
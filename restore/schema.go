@@ -0,0 +1,106 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/retailnext/cassandrabackup/bucket"
+	"github.com/retailnext/cassandrabackup/digest"
+	"github.com/retailnext/cassandrabackup/manifests"
+	"github.com/retailnext/cassandrabackup/nodeidentity"
+	"github.com/retailnext/cassandrabackup/restore/plan"
+	"github.com/retailnext/cassandrabackup/systemlocal"
+	"go.uber.org/zap"
+)
+
+var NoSchemaFound = errors.New("no schema was backed up with the snapshot")
+
+// RestoreSchema prints the schema backed up with the selected snapshot, or applies it to a cluster.
+func RestoreSchema(ctx context.Context) error {
+	identity := nodeidentity.ForRestore(ctx, schemaCmdCluster, schemaCmdHostname, schemaCmdHostnamePattern)
+	lgr := zap.S().With("identity", identity)
+
+	notAfter, err := plan.NotAfter(*schemaCmdAt, *schemaCmdNotAfter)
+	if err != nil {
+		return err
+	}
+	client := bucket.OpenShared()
+	keys, err := client.ListManifests(ctx, identity, 0, notAfter)
+	if err != nil {
+		return err
+	}
+	keys = keys.FromLatestSnapshot()
+	if len(keys) == 0 {
+		return NoBackupsFound
+	}
+	if keys[0].ManifestType != manifests.ManifestTypeSnapshot {
+		return NoSnapshotsFound
+	}
+	got, err := client.GetManifests(ctx, identity, keys[:1])
+	if err != nil {
+		return err
+	}
+	snapshot := got[0]
+	if snapshot.Schema == nil {
+		return NoSchemaFound
+	}
+	lgr.Infow("selected_schema", "manifest", keys[0], "blob", snapshot.Schema.URLSafe())
+
+	// Tiering may have archived a large schema along with the data files of its snapshot.
+	if err := thawArchived(ctx, client, map[string]digest.ForRestore{"schema": *snapshot.Schema}, false); err != nil {
+		return err
+	}
+	schema, err := downloadSchema(ctx, client, *snapshot.Schema)
+	if err != nil {
+		return err
+	}
+	if !*schemaCmdApply {
+		_, err = fmt.Print(schema)
+		return err
+	}
+	if len(*schemaCmdHosts) == 0 {
+		return errors.New("--host is required with --apply")
+	}
+	return systemlocal.ApplySchema(ctx, systemlocal.ApplyOptions{
+		Hosts:    *schemaCmdHosts,
+		Port:     *schemaCmdPort,
+		Username: *schemaCmdUsername,
+		Password: *schemaCmdPassword,
+	}, schema)
+}
+
+func downloadSchema(ctx context.Context, client bucket.Client, digests digest.ForRestore) (string, error) {
+	tmp, err := os.CreateTemp("", "cassandrabackup-schema-*")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if closeErr := tmp.Close(); closeErr != nil {
+			zap.S().Errorw("schema_temp_close_error", "name", tmp.Name(), "err", closeErr)
+		}
+		if removeErr := os.Remove(tmp.Name()); removeErr != nil {
+			zap.S().Errorw("schema_temp_remove_error", "name", tmp.Name(), "err", removeErr)
+		}
+	}()
+	if err := client.DownloadBlob(ctx, digests, tmp); err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(tmp.Name())
+	return string(data), err
+}
@@ -0,0 +1,106 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/retailnext/cassandrabackup/manifests"
+	"github.com/retailnext/writefile"
+	"go.uber.org/zap"
+)
+
+const (
+	// topologyYAMLName and topologyRackDCName are written into each host's directory by restore
+	// cluster, for the node that takes the host's place in a new cluster.
+	topologyYAMLName   = "topology-cassandra.yaml"
+	topologyRackDCName = "topology-cassandra-rackdc.properties"
+)
+
+// topologyYAML renders the cassandra.yaml settings that give a new node the host's tokens.
+func topologyYAML(host journalHost) string {
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, "# Settings for the node replacing %s of cluster %s, from manifest %s.\n",
+		host.Hostname, host.Cluster, host.Manifests[0])
+	b.WriteString("# Add them to its cassandra.yaml before it first starts.\n")
+	if host.Partitioner != "" {
+		_, _ = fmt.Fprintf(&b, "partitioner: %s\n", host.Partitioner)
+	}
+	_, _ = fmt.Fprintf(&b, "num_tokens: %d\n", len(host.Tokens))
+	_, _ = fmt.Fprintf(&b, "initial_token: %s\n", strings.Join(host.Tokens, ","))
+	return b.String()
+}
+
+// topologyRackDC renders the cassandra-rackdc.properties that place a new node where the host was.
+func topologyRackDC(host journalHost) string {
+	return fmt.Sprintf("dc=%s\nrack=%s\n", host.DataCenter, host.Rack)
+}
+
+// writeTopology writes the topology snippets of each host whose manifest recorded its tokens.
+func writeTopology(target string, hosts []journalHost) error {
+	lgr := zap.S()
+	for _, host := range hosts {
+		if len(host.Tokens) == 0 || len(host.Manifests) == 0 {
+			continue
+		}
+		files := map[string]string{topologyYAMLName: topologyYAML(host)}
+		if host.DataCenter != "" {
+			files[topologyRackDCName] = topologyRackDC(host)
+		}
+		config := writefile.Config{
+			Directory: filepath.Join(target, host.Hostname),
+			FileMode:  0o644,
+		}
+		if err := os.MkdirAll(config.Directory, 0o755); err != nil {
+			return err
+		}
+		for name, content := range files {
+			if err := config.WriteFile(name, func(file *os.File) error {
+				_, writeErr := file.WriteString(content)
+				return writeErr
+			}); err != nil {
+				return err
+			}
+		}
+		lgr.Infow("wrote_topology", "hostname", host.Hostname, "data_center", host.DataCenter, "rack", host.Rack, "tokens", len(host.Tokens))
+	}
+	return nil
+}
+
+// missingPeers returns the peers recorded by the base manifests that aren't among them, so that a
+// cluster rebuilt from them would be missing part of the ring.
+func missingPeers(bases []manifests.Manifest) []manifests.Peer {
+	restored := make(map[string]bool, len(bases))
+	for _, m := range bases {
+		restored[m.HostID] = true
+	}
+	seen := make(map[string]bool)
+	var missing []manifests.Peer
+	for _, m := range bases {
+		for _, peer := range m.Peers {
+			if restored[peer.HostID] || seen[peer.HostID] {
+				continue
+			}
+			seen[peer.HostID] = true
+			missing = append(missing, peer)
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool { return missing[i].Address < missing[j].Address })
+	return missing
+}
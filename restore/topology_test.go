@@ -0,0 +1,78 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/retailnext/cassandrabackup/manifests"
+	"github.com/retailnext/cassandrabackup/restore/plan"
+)
+
+func TestWriteTopology(t *testing.T) {
+	target := t.TempDir()
+	j := newJournal("cluster", target)
+	j.addHost(manifests.NodeIdentity{Cluster: "c", Hostname: "h1"}, plan.NodePlan{
+		SelectedManifests: manifests.ManifestKeys{{Time: 100, ManifestType: manifests.ManifestTypeSnapshot}},
+		Base: manifests.Manifest{
+			HostID:      "id1",
+			DataCenter:  "dc1",
+			Rack:        "rack1",
+			Partitioner: "org.apache.cassandra.dht.Murmur3Partitioner",
+			Tokens:      []string{"-10", "10"},
+		},
+	})
+	// Hosts backed up before manifests recorded tokens get no snippets.
+	j.addHost(manifests.NodeIdentity{Cluster: "c", Hostname: "h2"}, plan.NodePlan{
+		SelectedManifests: manifests.ManifestKeys{{Time: 100, ManifestType: manifests.ManifestTypeSnapshot}},
+	})
+	if err := writeTopology(target, j.Hosts); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, expected := range map[string]string{
+		topologyYAMLName: "# Settings for the node replacing h1 of cluster c, from manifest 00000000000000000100.1.json.\n" +
+			"# Add them to its cassandra.yaml before it first starts.\n" +
+			"partitioner: org.apache.cassandra.dht.Murmur3Partitioner\n" +
+			"num_tokens: 2\n" +
+			"initial_token: -10,10\n",
+		topologyRackDCName: "dc=dc1\nrack=rack1\n",
+	} {
+		got, err := os.ReadFile(filepath.Join(target, "h1", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := deep.Equal(string(got), expected); diff != nil {
+			t.Error(name, diff)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(target, "h2")); !os.IsNotExist(err) {
+		t.Errorf("expected no snippets for h2, got %v", err)
+	}
+}
+
+func TestMissingPeers(t *testing.T) {
+	bases := []manifests.Manifest{
+		{HostID: "a", Peers: []manifests.Peer{{HostID: "b", Address: "10.0.0.2"}, {HostID: "c", Address: "10.0.0.3"}}},
+		{HostID: "b", Peers: []manifests.Peer{{HostID: "a", Address: "10.0.0.1"}, {HostID: "c", Address: "10.0.0.3"}, {HostID: "d", Address: "10.0.0.0"}}},
+	}
+	expected := []manifests.Peer{{HostID: "d", Address: "10.0.0.0"}, {HostID: "c", Address: "10.0.0.3"}}
+	if diff := deep.Equal(missingPeers(bases), expected); diff != nil {
+		t.Error(diff)
+	}
+}
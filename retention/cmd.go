@@ -0,0 +1,36 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retention
+
+import (
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/retailnext/cassandrabackup/unixtime"
+)
+
+var (
+	Cmd       = kingpin.Command("retention", "")
+	ExtendCmd = Cmd.Command("extend", "Extend the S3 Object Lock retention of backup manifests and every blob they reference")
+
+	extendCmdCluster     = ExtendCmd.Flag("cluster", "Cluster whose backups to retain").Required().String()
+	extendCmdHostname    = ExtendCmd.Flag("hostname", "Only retain the backups of this host").String()
+	extendCmdNotBefore   = unixtime.Flag(ExtendCmd.Flag("not-before", "Ignore manifests before this time "+unixtime.TimeHelp))
+	extendCmdNotAfter    = unixtime.Flag(ExtendCmd.Flag("not-after", "Ignore manifests after this time "+unixtime.TimeHelp))
+	extendCmdAt          = unixtime.Flag(ExtendCmd.Flag("at", "Only retain the manifests a restore at this time would use "+unixtime.TimeHelp))
+	extendCmdUntil       = unixtime.Flag(ExtendCmd.Flag("until", "Retain the objects until at least this time, such as +365d "+unixtime.TimeHelp).Required())
+	extendCmdMode        = ExtendCmd.Flag("mode", "Object Lock mode for objects that aren't locked yet: GOVERNANCE or COMPLIANCE").Default(s3.ObjectLockModeGovernance).Enum(s3.ObjectLockModeGovernance, s3.ObjectLockModeCompliance)
+	extendCmdConcurrency = ExtendCmd.Flag("concurrency", "Number of blobs to extend at once").Default("8").Int()
+	extendCmdDryRun      = ExtendCmd.Flag("dry-run", "Only count the objects whose retention would be extended").Bool()
+)
@@ -0,0 +1,83 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/retailnext/cassandrabackup/bucket"
+	"github.com/retailnext/cassandrabackup/manifests"
+	"github.com/retailnext/cassandrabackup/restore/plan"
+	"go.uber.org/zap"
+)
+
+// Main extends the retention of the selected manifests, so that the backups from before an
+// incident can be kept beyond the retention they were written with.
+func Main(ctx context.Context) error {
+	lgr := zap.S()
+	until := extendCmdUntil.Time()
+	if !until.After(time.Now()) {
+		return fmt.Errorf("--until %s is not in the future", until.UTC().Format(time.RFC3339))
+	}
+	notAfter, err := plan.NotAfter(*extendCmdAt, *extendCmdNotAfter)
+	if err != nil {
+		return err
+	}
+	client := bucket.OpenShared()
+	retainer, err := bucket.NewRetainer(client, *extendCmdMode, until, *extendCmdConcurrency, *extendCmdDryRun)
+	if err != nil {
+		return err
+	}
+
+	identities := []manifests.NodeIdentity{{Cluster: *extendCmdCluster, Hostname: *extendCmdHostname}}
+	if *extendCmdHostname == "" {
+		if identities, err = client.ListHostNames(ctx, *extendCmdCluster); err != nil {
+			return err
+		}
+	}
+	for _, identity := range identities {
+		keys, err := client.ListManifests(ctx, identity, *extendCmdNotBefore, notAfter)
+		if err != nil {
+			return err
+		}
+		if *extendCmdAt != 0 {
+			keys = keys.FromLatestSnapshot()
+		}
+		if len(keys) == 0 {
+			lgr.Warnw("retention_no_manifests", "identity", identity)
+			continue
+		}
+		if err := retainer.Retain(ctx, identity, keys); err != nil {
+			return err
+		}
+		lgr.Infow("retention_host_done", "identity", identity, "manifests", len(keys))
+	}
+
+	result := retainer.Result
+	lgr.Infow("retention_extend_result", "dry_run", *extendCmdDryRun, "until", until.UTC(), "result", result)
+	if result.Missing > 0 {
+		lgr.Warnw("retention_missing_blobs", "missing", result.Missing)
+	}
+	if result.Failed > 0 {
+		return fmt.Errorf("%d blobs or manifests failed to have their retention extended", result.Failed)
+	}
+	if result.Manifests == 0 {
+		return errors.New("no manifests found")
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sstableloader
+
+import (
+	"context"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+var Tool = "/usr/bin/sstableloader"
+
+type Options struct {
+	// Hosts are the initial contact points in the target cluster.
+	Hosts []string
+	// Port is the native transport port of the target cluster, if not the default.
+	Port     int
+	Username string
+	Password string
+	// ThrottleMbits limits the streaming throughput, in megabits per second, if positive.
+	ThrottleMbits int
+	// ExtraArgs are passed to sstableloader before the directory.
+	ExtraArgs []string
+}
+
+func (o Options) args(directory string) []string {
+	args := []string{"-d", strings.Join(o.Hosts, ",")}
+	if o.Port > 0 {
+		args = append(args, "-p", strconv.Itoa(o.Port))
+	}
+	if o.Username != "" {
+		args = append(args, "-u", o.Username)
+	}
+	if o.Password != "" {
+		args = append(args, "-pw", o.Password)
+	}
+	if o.ThrottleMbits > 0 {
+		args = append(args, "-t", strconv.Itoa(o.ThrottleMbits))
+	}
+	args = append(args, o.ExtraArgs...)
+	return append(args, directory)
+}
+
+// Load streams the SSTables in directory, which must be named <keyspace>/<table>, into the target cluster.
+func Load(ctx context.Context, options Options, directory string) error {
+	lgr := zap.S()
+	cmd := exec.CommandContext(ctx, Tool, options.args(directory)...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		lgr.Errorw("sstableloader_fail", "directory", directory, "err", err, "output", string(output))
+		return err
+	}
+	lgr.Infow("loaded_sstables", "directory", directory)
+	return nil
+}
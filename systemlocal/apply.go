@@ -0,0 +1,102 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package systemlocal
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	gocql "github.com/apache/cassandra-gocql-driver/v2"
+	"go.uber.org/zap"
+)
+
+type ApplyOptions struct {
+	// Hosts are the initial contact points in the target cluster.
+	Hosts []string
+	// Port is the native transport port of the target cluster, if not the default.
+	Port     int
+	Username string
+	Password string
+}
+
+// ApplySchema runs the statements of a schema rendered by GetSchema against a cluster, in order,
+// stopping at the first that fails.
+func ApplySchema(ctx context.Context, options ApplyOptions, schema string) error {
+	lgr := zap.S()
+	cluster := gocql.NewCluster(options.Hosts...)
+	if options.Port > 0 {
+		cluster.Port = options.Port
+	}
+	if options.Username != "" {
+		cluster.Authenticator = gocql.PasswordAuthenticator{Username: options.Username, Password: options.Password}
+	}
+	cluster.Consistency = gocql.All
+	session, err := cluster.CreateSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	statements := SplitStatements(schema)
+	for i, statement := range statements {
+		if err := session.Query(statement).ExecContext(ctx); err != nil {
+			return fmt.Errorf("statement %d of %d: %w\n%s", i+1, len(statements), err, statement)
+		}
+	}
+	lgr.Infow("applied_schema", "statements", len(statements))
+	return nil
+}
+
+// SplitStatements splits CQL into statements at the semicolons that aren't in strings, quoted
+// identifiers, $$ function bodies or comments. Empty statements are left out.
+func SplitStatements(cql string) []string {
+	var statements []string
+	start := 0
+	for i := 0; i < len(cql); i++ {
+		switch {
+		case cql[i] == '\'':
+			i = skipPast(cql, i+1, "'")
+		case cql[i] == '"':
+			i = skipPast(cql, i+1, `"`)
+		case strings.HasPrefix(cql[i:], "$$"):
+			i = skipPast(cql, i+2, "$$")
+		case strings.HasPrefix(cql[i:], "--"), strings.HasPrefix(cql[i:], "//"):
+			i = skipPast(cql, i+2, "\n")
+		case strings.HasPrefix(cql[i:], "/*"):
+			i = skipPast(cql, i+2, "*/")
+		case cql[i] == ';':
+			if statement := strings.TrimSpace(cql[start:i]); statement != "" {
+				statements = append(statements, statement)
+			}
+			start = i + 1
+		}
+	}
+	if statement := strings.TrimSpace(cql[start:]); statement != "" {
+		statements = append(statements, statement)
+	}
+	return statements
+}
+
+// skipPast returns the index of the last byte of the first end at or after i, or of the last byte
+// of cql if there is none. A doubled quote inside a string is treated as the end of one string and
+// the start of another, which splits the same way.
+func skipPast(cql string, i int, end string) int {
+	index := strings.Index(cql[i:], end)
+	if index < 0 {
+		return len(cql) - 1
+	}
+	return i + index + len(end) - 1
+}
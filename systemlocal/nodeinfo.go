@@ -33,12 +33,7 @@ type NodeInfo struct {
 func GetNodeInfo(addr string) (NodeInfo, error) {
 	var result NodeInfo
 
-	cluster := gocql.NewCluster(addr)
-	cluster.NumConns = 1
-	cluster.DisableInitialHostLookup = true
-	cluster.Consistency = gocql.LocalOne
-
-	session, err := cluster.CreateSession()
+	session, err := newSession(addr)
 	if err != nil {
 		return result, err
 	}
@@ -50,3 +45,40 @@ func GetNodeInfo(addr string) (NodeInfo, error) {
 
 	return result, err
 }
+
+type PeerInfo struct {
+	Address    string
+	DataCenter string
+	HostID     string
+	Rack       string
+	Tokens     []string
+}
+
+// GetPeers returns the other nodes of the ring as the node at addr sees them, sorted by address.
+func GetPeers(addr string) ([]PeerInfo, error) {
+	session, err := newSession(addr)
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	var result []PeerInfo
+	var peer PeerInfo
+	iter := session.Query(`SELECT peer, data_center, host_id, rack, tokens FROM system.peers`).Iter()
+	for iter.Scan(&peer.Address, &peer.DataCenter, &peer.HostID, &peer.Rack, &peer.Tokens) {
+		sort.Strings(peer.Tokens)
+		result = append(result, peer)
+		peer = PeerInfo{}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Address < result[j].Address })
+	return result, iter.Close()
+}
+
+// newSession connects to the local node only.
+func newSession(addr string) (*gocql.Session, error) {
+	cluster := gocql.NewCluster(addr)
+	cluster.NumConns = 1
+	cluster.DisableInitialHostLookup = true
+	cluster.Consistency = gocql.LocalOne
+	return cluster.CreateSession()
+}
@@ -0,0 +1,520 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package systemlocal
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SystemKeyspaces are left out of the schema, since every node creates them itself.
+var SystemKeyspaces = map[string]bool{
+	"system":                true,
+	"system_auth":           true,
+	"system_distributed":    true,
+	"system_schema":         true,
+	"system_traces":         true,
+	"system_views":          true,
+	"system_virtual_schema": true,
+}
+
+// GetSchema reads the schema of the non-system keyspaces from system_schema and renders it as CQL
+// statements like those DESCRIBE SCHEMA prints. Tables are created with their existing ids, so that
+// SSTables restored into a cluster created from the schema belong in directories with the same names,
+// and with their dropped columns, so that data of dropped columns in restored SSTables stays dropped.
+func GetSchema(addr string) (string, error) {
+	session, err := newSession(addr)
+	if err != nil {
+		return "", err
+	}
+	defer session.Close()
+
+	var rows schemaRows
+	for _, query := range []struct {
+		table string
+		rows  *[]row
+	}{
+		{"keyspaces", &rows.keyspaces},
+		{"types", &rows.types},
+		{"functions", &rows.functions},
+		{"aggregates", &rows.aggregates},
+		{"tables", &rows.tables},
+		{"columns", &rows.columns},
+		{"dropped_columns", &rows.droppedColumns},
+		{"indexes", &rows.indexes},
+		{"views", &rows.views},
+	} {
+		result, err := session.Query(`SELECT * FROM system_schema.` + query.table).Iter().SliceMap()
+		if err != nil {
+			return "", fmt.Errorf("read system_schema.%s: %w", query.table, err)
+		}
+		for _, r := range result {
+			if !SystemKeyspaces[row(r).str("keyspace_name")] {
+				*query.rows = append(*query.rows, r)
+			}
+		}
+	}
+	return rows.render(), nil
+}
+
+// row is a row of a system_schema table, as gocql decodes it.
+type row map[string]interface{}
+
+func (r row) str(column string) string {
+	s, _ := r[column].(string)
+	return s
+}
+
+func (r row) strings(column string) []string {
+	s, _ := r[column].([]string)
+	return s
+}
+
+func (r row) bool(column string) bool {
+	b, _ := r[column].(bool)
+	return b
+}
+
+func (r row) int(column string) int {
+	switch v := r[column].(type) {
+	case int:
+		return v
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	}
+	return 0
+}
+
+type schemaRows struct {
+	keyspaces      []row
+	types          []row
+	functions      []row
+	aggregates     []row
+	tables         []row
+	columns        []row
+	droppedColumns []row
+	indexes        []row
+	views          []row
+}
+
+// byKeyspace groups rows by keyspace, sorted by the named column.
+func byKeyspace(rows []row, nameColumn string) map[string][]row {
+	result := make(map[string][]row)
+	for _, r := range rows {
+		keyspace := r.str("keyspace_name")
+		result[keyspace] = append(result[keyspace], r)
+	}
+	for _, list := range result {
+		sort.SliceStable(list, func(i, j int) bool {
+			return list[i].str(nameColumn) < list[j].str(nameColumn)
+		})
+	}
+	return result
+}
+
+// render writes each keyspace's types, functions, aggregates, tables with their dropped columns and
+// indexes, and views, in an order in which they can be created.
+func (s schemaRows) render() string {
+	types := byKeyspace(s.types, "type_name")
+	functions := byKeyspace(s.functions, "function_name")
+	aggregates := byKeyspace(s.aggregates, "aggregate_name")
+	tables := byKeyspace(s.tables, "table_name")
+	indexes := byKeyspace(s.indexes, "index_name")
+	views := byKeyspace(s.views, "view_name")
+	columns := make(map[[2]string][]row)
+	for _, c := range s.columns {
+		key := [2]string{c.str("keyspace_name"), c.str("table_name")}
+		columns[key] = append(columns[key], c)
+	}
+	dropped := make(map[[2]string][]row)
+	for _, c := range s.droppedColumns {
+		key := [2]string{c.str("keyspace_name"), c.str("table_name")}
+		dropped[key] = append(dropped[key], c)
+	}
+
+	keyspaces := append([]row(nil), s.keyspaces...)
+	sort.Slice(keyspaces, func(i, j int) bool {
+		return keyspaces[i].str("keyspace_name") < keyspaces[j].str("keyspace_name")
+	})
+
+	var b strings.Builder
+	for _, keyspace := range keyspaces {
+		name := keyspace.str("keyspace_name")
+		renderKeyspace(&b, keyspace)
+		for _, t := range sortTypes(types[name]) {
+			renderType(&b, t)
+		}
+		for _, f := range functions[name] {
+			renderFunction(&b, f)
+		}
+		for _, a := range aggregates[name] {
+			renderAggregate(&b, a)
+		}
+		for _, t := range tables[name] {
+			key := [2]string{name, t.str("table_name")}
+			renderTable(&b, t, columns[key])
+			renderDroppedColumns(&b, t, columns[key], dropped[key])
+			for _, index := range indexes[name] {
+				if index.str("table_name") == t.str("table_name") {
+					renderIndex(&b, index)
+				}
+			}
+		}
+		for _, v := range views[name] {
+			renderView(&b, v, columns[[2]string{name, v.str("view_name")}])
+		}
+	}
+	return b.String()
+}
+
+var unquotedIdentifier = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+func quoteIdentifier(name string) string {
+	if unquotedIdentifier.MatchString(name) {
+		return name
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func qualified(keyspace, name string) string {
+	return quoteIdentifier(keyspace) + "." + quoteIdentifier(name)
+}
+
+func quoteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func renderMap(m map[string]string) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		// Put the class first, as DESCRIBE does.
+		if (keys[i] == "class") != (keys[j] == "class") {
+			return keys[i] == "class"
+		}
+		return keys[i] < keys[j]
+	})
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, quoteString(k)+": "+quoteString(m[k]))
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// renderValue renders an option value, or returns false for values of types options don't have.
+func renderValue(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return quoteString(v), true
+	case map[string]string:
+		return renderMap(v), true
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), true
+	case int:
+		return strconv.Itoa(v), true
+	case int32:
+		return strconv.FormatInt(int64(v), 10), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case bool:
+		return strconv.FormatBool(v), true
+	}
+	return "", false
+}
+
+func renderKeyspace(b *strings.Builder, r row) {
+	replication, _ := r["replication"].(map[string]string)
+	_, _ = fmt.Fprintf(b, "CREATE KEYSPACE %s WITH replication = %s AND durable_writes = %t;\n\n",
+		quoteIdentifier(r.str("keyspace_name")), renderMap(replication), r.bool("durable_writes"))
+}
+
+// sortTypes orders a keyspace's types so that types come after the types their fields use.
+func sortTypes(types []row) []row {
+	pending := append([]row(nil), types...)
+	var result []row
+	for len(pending) > 0 {
+		remaining := pending[:0:0]
+		for _, t := range pending {
+			if usesAny(t.strings("field_types"), pending, t) {
+				remaining = append(remaining, t)
+			} else {
+				result = append(result, t)
+			}
+		}
+		if len(remaining) == len(pending) {
+			// A cycle isn't possible, but don't loop forever on unexpected input.
+			return append(result, remaining...)
+		}
+		pending = remaining
+	}
+	return result
+}
+
+func usesAny(fieldTypes []string, types []row, self row) bool {
+	for _, fieldType := range fieldTypes {
+		for _, word := range strings.FieldsFunc(fieldType, func(r rune) bool {
+			return r == '<' || r == '>' || r == ',' || r == ' ' || r == '"'
+		}) {
+			for _, t := range types {
+				if t.str("type_name") != self.str("type_name") && t.str("type_name") == word {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func renderType(b *strings.Builder, r row) {
+	names, types := r.strings("field_names"), r.strings("field_types")
+	_, _ = fmt.Fprintf(b, "CREATE TYPE %s (\n", qualified(r.str("keyspace_name"), r.str("type_name")))
+	for i, name := range names {
+		separator := ","
+		if i == len(names)-1 {
+			separator = ""
+		}
+		_, _ = fmt.Fprintf(b, "    %s %s%s\n", quoteIdentifier(name), types[i], separator)
+	}
+	b.WriteString(");\n\n")
+}
+
+func renderFunction(b *strings.Builder, r row) {
+	names, types := r.strings("argument_names"), r.strings("argument_types")
+	arguments := make([]string, len(names))
+	for i, name := range names {
+		arguments[i] = quoteIdentifier(name) + " " + types[i]
+	}
+	onNull := "RETURNS NULL ON NULL INPUT"
+	if r.bool("called_on_null_input") {
+		onNull = "CALLED ON NULL INPUT"
+	}
+	_, _ = fmt.Fprintf(b, "CREATE FUNCTION %s(%s)\n    %s\n    RETURNS %s\n    LANGUAGE %s\n    AS $$%s$$;\n\n",
+		qualified(r.str("keyspace_name"), r.str("function_name")), strings.Join(arguments, ", "),
+		onNull, r.str("return_type"), r.str("language"), r.str("body"))
+}
+
+func renderAggregate(b *strings.Builder, r row) {
+	_, _ = fmt.Fprintf(b, "CREATE AGGREGATE %s(%s)\n    SFUNC %s\n    STYPE %s",
+		qualified(r.str("keyspace_name"), r.str("aggregate_name")), strings.Join(r.strings("argument_types"), ", "),
+		quoteIdentifier(r.str("state_func")), r.str("state_type"))
+	if finalFunc := r.str("final_func"); finalFunc != "" {
+		_, _ = fmt.Fprintf(b, "\n    FINALFUNC %s", quoteIdentifier(finalFunc))
+	}
+	if initCond := r.str("initcond"); initCond != "" {
+		// initcond is stored as a CQL literal.
+		_, _ = fmt.Fprintf(b, "\n    INITCOND %s", initCond)
+	}
+	b.WriteString(";\n\n")
+}
+
+// tableOptions are the options of tables and views that are rendered when system_schema has them.
+// Which exist depends on the version of Cassandra.
+var tableOptions = []string{
+	"additional_write_policy",
+	"allow_auto_snapshot",
+	"bloom_filter_fp_chance",
+	"caching",
+	"cdc",
+	"comment",
+	"compaction",
+	"compression",
+	"crc_check_chance",
+	"dclocal_read_repair_chance",
+	"default_time_to_live",
+	"gc_grace_seconds",
+	"incremental_backups",
+	"max_index_interval",
+	"memtable",
+	"memtable_flush_period_in_ms",
+	"min_index_interval",
+	"read_repair",
+	"read_repair_chance",
+	"speculative_retry",
+}
+
+// primaryKey returns the columns, partition key columns first, then clustering columns, then
+// the rest by name, along with the PRIMARY KEY and CLUSTERING ORDER clauses.
+func primaryKey(columns []row) (ordered []row, key string, clusteringOrder string) {
+	var partition, clustering, other []row
+	for _, c := range columns {
+		switch c.str("kind") {
+		case "partition_key":
+			partition = append(partition, c)
+		case "clustering":
+			clustering = append(clustering, c)
+		default:
+			other = append(other, c)
+		}
+	}
+	byPosition := func(list []row) {
+		sort.SliceStable(list, func(i, j int) bool { return list[i].int("position") < list[j].int("position") })
+	}
+	byPosition(partition)
+	byPosition(clustering)
+	sort.SliceStable(other, func(i, j int) bool { return other[i].str("column_name") < other[j].str("column_name") })
+
+	names := func(list []row) []string {
+		result := make([]string, len(list))
+		for i, c := range list {
+			result[i] = quoteIdentifier(c.str("column_name"))
+		}
+		return result
+	}
+	key = strings.Join(names(partition), ", ")
+	if len(partition) > 1 {
+		key = "(" + key + ")"
+	}
+	if len(clustering) > 0 {
+		key += ", " + strings.Join(names(clustering), ", ")
+		orders := make([]string, len(clustering))
+		for i, c := range clustering {
+			orders[i] = quoteIdentifier(c.str("column_name")) + " " + strings.ToUpper(c.str("clustering_order"))
+		}
+		clusteringOrder = strings.Join(orders, ", ")
+	}
+	ordered = append(append(append(ordered, partition...), clustering...), other...)
+	return ordered, key, clusteringOrder
+}
+
+// optionClauses renders the options r has, except those in skip.
+func optionClauses(r row, skip string) []string {
+	var clauses []string
+	for _, option := range tableOptions {
+		if option == skip {
+			continue
+		}
+		if value, ok := renderValue(r[option]); ok {
+			clauses = append(clauses, option+" = "+value)
+		}
+	}
+	return clauses
+}
+
+func renderTable(b *strings.Builder, r row, columns []row) {
+	ordered, key, clusteringOrder := primaryKey(columns)
+	_, _ = fmt.Fprintf(b, "CREATE TABLE %s (\n", qualified(r.str("keyspace_name"), r.str("table_name")))
+	for _, c := range ordered {
+		static := ""
+		if c.str("kind") == "static" {
+			static = " static"
+		}
+		_, _ = fmt.Fprintf(b, "    %s %s%s,\n", quoteIdentifier(c.str("column_name")), c.str("type"), static)
+	}
+	var clauses []string
+	if id, ok := r["id"].(fmt.Stringer); ok {
+		clauses = append(clauses, "ID = "+id.String())
+	}
+	if clusteringOrder != "" {
+		clauses = append(clauses, "CLUSTERING ORDER BY ("+clusteringOrder+")")
+	}
+	clauses = append(clauses, optionClauses(r, "")...)
+	_, _ = fmt.Fprintf(b, "    PRIMARY KEY (%s)\n)", key)
+	if len(clauses) > 0 {
+		_, _ = fmt.Fprintf(b, " WITH %s", strings.Join(clauses, "\n    AND "))
+	}
+	b.WriteString(";\n\n")
+}
+
+// renderDroppedColumns recreates the drops of a table's columns with their original timestamps, as
+// DESCRIBE ... WITH INTERNALS does, so that cells written before a drop stay hidden. A column that
+// no longer exists is added back first; one that was added again after the drop is added back after.
+func renderDroppedColumns(b *strings.Builder, r row, columns []row, dropped []row) {
+	current := make(map[string]row, len(columns))
+	for _, c := range columns {
+		current[c.str("column_name")] = c
+	}
+	dropped = append([]row(nil), dropped...)
+	sort.Slice(dropped, func(i, j int) bool {
+		return dropped[i].str("column_name") < dropped[j].str("column_name")
+	})
+	table := qualified(r.str("keyspace_name"), r.str("table_name"))
+	add := func(c row) {
+		static := ""
+		if c.str("kind") == "static" {
+			static = " static"
+		}
+		_, _ = fmt.Fprintf(b, "ALTER TABLE %s ADD %s %s%s;\n\n", table, quoteIdentifier(c.str("column_name")), c.str("type"), static)
+	}
+	for _, d := range dropped {
+		name := d.str("column_name")
+		readded, exists := current[name]
+		if !exists {
+			add(d)
+		}
+		droppedTime, _ := d["dropped_time"].(time.Time)
+		_, _ = fmt.Fprintf(b, "ALTER TABLE %s DROP %s USING TIMESTAMP %d;\n\n", table, quoteIdentifier(name), droppedTime.UnixMicro())
+		if exists {
+			add(readded)
+		}
+	}
+}
+
+func renderIndex(b *strings.Builder, r row) {
+	options, _ := r["options"].(map[string]string)
+	target := options["target"]
+	name := quoteIdentifier(r.str("index_name"))
+	table := qualified(r.str("keyspace_name"), r.str("table_name"))
+	if r.str("kind") != "CUSTOM" {
+		_, _ = fmt.Fprintf(b, "CREATE INDEX %s ON %s (%s);\n\n", name, table, target)
+		return
+	}
+	_, _ = fmt.Fprintf(b, "CREATE CUSTOM INDEX %s ON %s (%s) USING %s", name, table, target, quoteString(options["class_name"]))
+	rest := make(map[string]string)
+	for k, v := range options {
+		if k != "target" && k != "class_name" {
+			rest[k] = v
+		}
+	}
+	if len(rest) > 0 {
+		_, _ = fmt.Fprintf(b, " WITH OPTIONS = %s", renderMap(rest))
+	}
+	b.WriteString(";\n\n")
+}
+
+func renderView(b *strings.Builder, r row, columns []row) {
+	ordered, key, clusteringOrder := primaryKey(columns)
+	selected := "*"
+	if !r.bool("include_all_columns") {
+		names := make([]string, len(ordered))
+		for i, c := range ordered {
+			names[i] = quoteIdentifier(c.str("column_name"))
+		}
+		selected = strings.Join(names, ", ")
+	}
+	keyspace := r.str("keyspace_name")
+	_, _ = fmt.Fprintf(b, "CREATE MATERIALIZED VIEW %s AS\n    SELECT %s\n    FROM %s\n    WHERE %s\n    PRIMARY KEY (%s)",
+		qualified(keyspace, r.str("view_name")), selected, qualified(keyspace, r.str("base_table_name")), r.str("where_clause"), key)
+	var clauses []string
+	if id, ok := r["id"].(fmt.Stringer); ok {
+		clauses = append(clauses, "ID = "+id.String())
+	}
+	if clusteringOrder != "" {
+		clauses = append(clauses, "CLUSTERING ORDER BY ("+clusteringOrder+")")
+	}
+	// Views can't have a TTL of their own.
+	clauses = append(clauses, optionClauses(r, "default_time_to_live")...)
+	if len(clauses) > 0 {
+		_, _ = fmt.Fprintf(b, "\n    WITH %s", strings.Join(clauses, "\n    AND "))
+	}
+	b.WriteString(";\n\n")
+}
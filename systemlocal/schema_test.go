@@ -0,0 +1,141 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package systemlocal
+
+import (
+	"testing"
+	"time"
+
+	gocql "github.com/apache/cassandra-gocql-driver/v2"
+	"github.com/go-test/deep"
+)
+
+func TestRenderSchema(t *testing.T) {
+	id, err := gocql.ParseUUID("5a1c395e-b41f-11e5-9f22-ba0be0483c18")
+	if err != nil {
+		t.Fatal(err)
+	}
+	viewID, err := gocql.ParseUUID("7b3e8a10-b41f-11e5-9f22-ba0be0483c18")
+	if err != nil {
+		t.Fatal(err)
+	}
+	droppedAt := time.Date(2026, 3, 4, 5, 6, 7, 890e6, time.UTC)
+	rows := schemaRows{
+		keyspaces: []row{
+			{"keyspace_name": "ks", "durable_writes": true, "replication": map[string]string{"dc1": "3", "class": "org.apache.cassandra.locator.NetworkTopologyStrategy"}},
+		},
+		types: []row{
+			{"keyspace_name": "ks", "type_name": "person", "field_names": []string{"name", "home"}, "field_types": []string{"text", "frozen<address>"}},
+			{"keyspace_name": "ks", "type_name": "address", "field_names": []string{"street"}, "field_types": []string{"text"}},
+		},
+		functions: []row{
+			{"keyspace_name": "ks", "function_name": "twice", "argument_names": []string{"x"}, "argument_types": []string{"int"},
+				"called_on_null_input": false, "return_type": "int", "language": "java", "body": "return x * 2;"},
+		},
+		tables: []row{
+			{"keyspace_name": "ks", "table_name": "Events", "id": id, "comment": "it's", "gc_grace_seconds": 864000,
+				"compaction": map[string]string{"class": "SizeTieredCompactionStrategy", "max_threshold": "32"}},
+		},
+		columns: []row{
+			{"keyspace_name": "ks", "table_name": "Events", "column_name": "value", "kind": "regular", "type": "text"},
+			{"keyspace_name": "ks", "table_name": "Events", "column_name": "at", "kind": "clustering", "position": 0, "clustering_order": "desc", "type": "timestamp"},
+			{"keyspace_name": "ks", "table_name": "Events", "column_name": "owner", "kind": "static", "type": "frozen<person>"},
+			{"keyspace_name": "ks", "table_name": "Events", "column_name": "bucket", "kind": "partition_key", "position": 1, "type": "int"},
+			{"keyspace_name": "ks", "table_name": "Events", "column_name": "id", "kind": "partition_key", "position": 0, "type": "uuid"},
+			{"keyspace_name": "ks", "table_name": "by_value", "column_name": "value", "kind": "partition_key", "position": 0, "type": "text"},
+			{"keyspace_name": "ks", "table_name": "by_value", "column_name": "id", "kind": "clustering", "position": 0, "clustering_order": "asc", "type": "uuid"},
+		},
+		droppedColumns: []row{
+			{"keyspace_name": "ks", "table_name": "Events", "column_name": "value", "dropped_time": droppedAt, "kind": "regular", "type": "int"},
+			{"keyspace_name": "ks", "table_name": "Events", "column_name": "Legacy", "dropped_time": droppedAt.Add(time.Second), "kind": "static", "type": "text"},
+		},
+		indexes: []row{
+			{"keyspace_name": "ks", "table_name": "Events", "index_name": "events_value", "kind": "COMPOSITES", "options": map[string]string{"target": "value"}},
+		},
+		views: []row{
+			{"keyspace_name": "ks", "view_name": "by_value", "id": viewID, "base_table_name": "Events", "include_all_columns": false,
+				"where_clause": "value IS NOT NULL AND id IS NOT NULL", "default_time_to_live": 0},
+		},
+	}
+
+	expected := `CREATE KEYSPACE ks WITH replication = {'class': 'org.apache.cassandra.locator.NetworkTopologyStrategy', 'dc1': '3'} AND durable_writes = true;
+
+CREATE TYPE ks.address (
+    street text
+);
+
+CREATE TYPE ks.person (
+    name text,
+    home frozen<address>
+);
+
+CREATE FUNCTION ks.twice(x int)
+    RETURNS NULL ON NULL INPUT
+    RETURNS int
+    LANGUAGE java
+    AS $$return x * 2;$$;
+
+CREATE TABLE ks."Events" (
+    id uuid,
+    bucket int,
+    at timestamp,
+    owner frozen<person> static,
+    value text,
+    PRIMARY KEY ((id, bucket), at)
+) WITH ID = 5a1c395e-b41f-11e5-9f22-ba0be0483c18
+    AND CLUSTERING ORDER BY (at DESC)
+    AND comment = 'it''s'
+    AND compaction = {'class': 'SizeTieredCompactionStrategy', 'max_threshold': '32'}
+    AND gc_grace_seconds = 864000;
+
+ALTER TABLE ks."Events" ADD "Legacy" text static;
+
+ALTER TABLE ks."Events" DROP "Legacy" USING TIMESTAMP 1772600768890000;
+
+ALTER TABLE ks."Events" DROP value USING TIMESTAMP 1772600767890000;
+
+ALTER TABLE ks."Events" ADD value text;
+
+CREATE INDEX events_value ON ks."Events" (value);
+
+CREATE MATERIALIZED VIEW ks.by_value AS
+    SELECT value, id
+    FROM ks."Events"
+    WHERE value IS NOT NULL AND id IS NOT NULL
+    PRIMARY KEY (value, id)
+    WITH ID = 7b3e8a10-b41f-11e5-9f22-ba0be0483c18
+    AND CLUSTERING ORDER BY (id ASC);
+
+`
+	if diff := deep.Equal(rows.render(), expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	cql := `CREATE TABLE ks.t (k int PRIMARY KEY) WITH comment = 'a;b''c';
+-- a comment; with a semicolon
+CREATE FUNCTION ks.f(x int) RETURNS NULL ON NULL INPUT RETURNS int LANGUAGE java AS $$int y = x; return y;$$;
+/* another; comment */ CREATE TABLE ks."t;2" (k int PRIMARY KEY);;
+`
+	expected := []string{
+		`CREATE TABLE ks.t (k int PRIMARY KEY) WITH comment = 'a;b''c'`,
+		"-- a comment; with a semicolon\nCREATE FUNCTION ks.f(x int) RETURNS NULL ON NULL INPUT RETURNS int LANGUAGE java AS $$int y = x; return y;$$",
+		`/* another; comment */ CREATE TABLE ks."t;2" (k int PRIMARY KEY)`,
+	}
+	if diff := deep.Equal(SplitStatements(cql), expected); diff != nil {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package throttle
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/alecthomas/units"
+	"go.uber.org/zap"
+)
+
+// Handler shows the current rates on GET, and changes them on POST with form values named after the
+// limiters, such as upload=20MiB. A rate of 0 means unlimited.
+var Handler http.Handler = handler{}
+
+type handler struct{}
+
+var limiters = []*Limiter{Upload, Download, DiskRead}
+
+func (handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		rates := make(map[*Limiter]int64)
+		for _, l := range limiters {
+			value := r.FormValue(l.name)
+			if value == "" {
+				continue
+			}
+			parsed, err := units.ParseBase2Bytes(value)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("invalid %s rate %q: %v", l.name, value, err), http.StatusBadRequest)
+				return
+			}
+			rates[l] = int64(parsed)
+		}
+		// Only apply the rates once they have all parsed.
+		for l, bytesPerSecond := range rates {
+			l.SetRate(bytesPerSecond)
+			zap.S().Infow("throttle_rate_changed", "limiter", l.name, "rate", bytesPerSecond, "remote", r.RemoteAddr)
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	for _, l := range limiters {
+		rate := "unlimited"
+		if bytesPerSecond := l.Rate(); bytesPerSecond > 0 {
+			rate = units.Base2Bytes(bytesPerSecond).String() + "/s"
+		}
+		_, _ = fmt.Fprintf(w, "%s: %s\n", l.name, rate)
+	}
+}
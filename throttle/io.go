@@ -0,0 +1,100 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package throttle
+
+import (
+	"context"
+	"io"
+)
+
+// Reader limits reads from r. Bytes are accounted for after they are read, so a large read
+// can run ahead of the rate once, and is then paid back before the next.
+func Reader(ctx context.Context, r io.Reader, l *Limiter) io.Reader {
+	return &reader{ctx: ctx, r: r, l: l}
+}
+
+type reader struct {
+	ctx context.Context
+	r   io.Reader
+	l   *Limiter
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		if waitErr := r.l.WaitN(r.ctx, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
+
+// ReaderAt limits reads from r, which may happen concurrently.
+func ReaderAt(ctx context.Context, r io.ReaderAt, l *Limiter) io.ReaderAt {
+	return &readerAt{ctx: ctx, r: r, l: l}
+}
+
+type readerAt struct {
+	ctx context.Context
+	r   io.ReaderAt
+	l   *Limiter
+}
+
+func (r *readerAt) ReadAt(p []byte, off int64) (int, error) {
+	n, err := r.r.ReadAt(p, off)
+	if n > 0 {
+		if waitErr := r.l.WaitN(r.ctx, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
+
+// Writer limits writes to w. Waiting before each write pushes back on whatever is producing the data,
+// such as a network download.
+func Writer(ctx context.Context, w io.Writer, l *Limiter) io.Writer {
+	return &writer{ctx: ctx, w: w, l: l}
+}
+
+type writer struct {
+	ctx context.Context
+	w   io.Writer
+	l   *Limiter
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	if err := w.l.WaitN(w.ctx, len(p)); err != nil {
+		return 0, err
+	}
+	return w.w.Write(p)
+}
+
+// WriterAt limits writes to w, which may happen concurrently.
+func WriterAt(ctx context.Context, w io.WriterAt, l *Limiter) io.WriterAt {
+	return &writerAt{ctx: ctx, w: w, l: l}
+}
+
+type writerAt struct {
+	ctx context.Context
+	w   io.WriterAt
+	l   *Limiter
+}
+
+func (w *writerAt) WriteAt(p []byte, off int64) (int, error) {
+	if err := w.l.WaitN(w.ctx, len(p)); err != nil {
+		return 0, err
+	}
+	return w.w.WriteAt(p, off)
+}
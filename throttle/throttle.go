@@ -0,0 +1,130 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package throttle
+
+import (
+	"context"
+	"time"
+
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+)
+
+// minBurst keeps the bucket big enough for a typical read when the rate is very low.
+const minBurst = 256 * 1024
+
+var (
+	uploadRate   = kingpin.Flag("upload-rate", "Limit uploads to this many bytes per second, such as 20MiB. 0 means unlimited.").Default("0").Bytes()
+	downloadRate = kingpin.Flag("download-rate", "Limit downloads to this many bytes per second, such as 50MiB. 0 means unlimited.").Default("0").Bytes()
+	diskReadRate = kingpin.Flag("disk-read-rate", "Limit reading local files for digests and uploads to this many bytes per second, such as 100MiB. 0 means unlimited.").Default("0").Bytes()
+)
+
+var (
+	Upload   = newLimiter("upload")
+	Download = newLimiter("download")
+	DiskRead = newLimiter("disk_read")
+)
+
+// Configure applies the rate flags. It must be called after the flags are parsed.
+func Configure() {
+	Upload.SetRate(int64(*uploadRate))
+	Download.SetRate(int64(*downloadRate))
+	DiskRead.SetRate(int64(*diskReadRate))
+}
+
+// Limiter is a token bucket of bytes, refilled at its rate and holding up to a second's worth.
+// It is safe for concurrent use, and its rate can be changed while it is in use.
+type Limiter struct {
+	name string
+	l    *rate.Limiter
+
+	rateGauge   prometheus.Gauge
+	waitSeconds prometheus.Counter
+}
+
+func newLimiter(name string) *Limiter {
+	return &Limiter{
+		name:        name,
+		l:           rate.NewLimiter(rate.Inf, minBurst),
+		rateGauge:   rateGauges.WithLabelValues(name),
+		waitSeconds: waitSecondsCounters.WithLabelValues(name),
+	}
+}
+
+func (l *Limiter) Name() string {
+	return l.name
+}
+
+// SetRate changes the rate in bytes per second; 0 or less means unlimited.
+func (l *Limiter) SetRate(bytesPerSecond int64) {
+	if bytesPerSecond <= 0 {
+		l.l.SetLimit(rate.Inf)
+		l.l.SetBurst(minBurst)
+		l.rateGauge.Set(0)
+		return
+	}
+	l.l.SetBurst(int(max(bytesPerSecond, minBurst)))
+	l.l.SetLimit(rate.Limit(bytesPerSecond))
+	l.rateGauge.Set(float64(bytesPerSecond))
+}
+
+// Rate returns the rate in bytes per second, or 0 if unlimited.
+func (l *Limiter) Rate() int64 {
+	limit := l.l.Limit()
+	if limit == rate.Inf {
+		return 0
+	}
+	return int64(limit)
+}
+
+// WaitN blocks until n bytes may pass, or ctx is done.
+func (l *Limiter) WaitN(ctx context.Context, n int) error {
+	if l == nil || l.l.Limit() == rate.Inf {
+		return nil
+	}
+	start := time.Now()
+	defer func() {
+		l.waitSeconds.Add(time.Since(start).Seconds())
+	}()
+	for n > 0 {
+		chunk := min(n, l.l.Burst())
+		if err := l.l.WaitN(ctx, chunk); err != nil {
+			return err
+		}
+		n -= chunk
+	}
+	return nil
+}
+
+var (
+	rateGauges = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "cassandrabackup",
+		Subsystem: "throttle",
+		Name:      "rate_bytes",
+		Help:      "Configured rate limit in bytes per second, or 0 if unlimited.",
+	}, []string{"limiter"})
+	waitSecondsCounters = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "cassandrabackup",
+		Subsystem: "throttle",
+		Name:      "wait_seconds_total",
+		Help:      "Time spent waiting for a rate limit.",
+	}, []string{"limiter"})
+)
+
+func init() {
+	prometheus.MustRegister(rateGauges)
+	prometheus.MustRegister(waitSecondsCounters)
+}
@@ -0,0 +1,95 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package throttle
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReaderIsLimited(t *testing.T) {
+	l := newLimiter("test")
+	l.SetRate(minBurst)
+	ctx := context.Background()
+
+	// The first second's worth comes out of the full bucket; the next half second's has to wait.
+	data := make([]byte, minBurst+minBurst/2)
+	start := time.Now()
+	n, err := io.Copy(io.Discard, Reader(ctx, bytes.NewReader(data), l))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(len(data)) {
+		t.Fatalf("expected %d bytes, got %d", len(data), n)
+	}
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Errorf("expected to wait about 500ms, waited %v", elapsed)
+	}
+
+	l.SetRate(0)
+	if l.Rate() != 0 {
+		t.Errorf("expected unlimited, got %d", l.Rate())
+	}
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := Writer(cancelled, io.Discard, l).Write(data); err != nil {
+		t.Errorf("unlimited writes shouldn't wait: %v", err)
+	}
+	l.SetRate(1)
+	if _, err := Writer(cancelled, io.Discard, l).Write(data); err == nil {
+		t.Error("expected an error from a cancelled wait")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	defer func() {
+		for _, l := range limiters {
+			l.SetRate(0)
+		}
+	}()
+
+	post := func(form url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/throttle", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		Handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := post(url.Values{"upload": {"20MiB"}, "disk_read": {"1GiB"}})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", rec.Code, rec.Body)
+	}
+	if Upload.Rate() != 20<<20 || DiskRead.Rate() != 1<<30 || Download.Rate() != 0 {
+		t.Errorf("unexpected rates %d %d %d", Upload.Rate(), DiskRead.Rate(), Download.Rate())
+	}
+	if body := rec.Body.String(); body != "upload: 20MiB/s\ndownload: unlimited\ndisk_read: 1GiB/s\n" {
+		t.Errorf("unexpected body:\n%s", body)
+	}
+
+	if rec := post(url.Values{"upload": {"0"}, "download": {"fast"}}); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected a bad request, got %d", rec.Code)
+	}
+	if Upload.Rate() != 20<<20 {
+		t.Errorf("a bad request shouldn't change any rate, upload is %d", Upload.Rate())
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tier
+
+import (
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+var (
+	Cmd = kingpin.Command("tier", "Move blobs referenced only by old manifests to an archive storage class.")
+
+	cmdOlderThan    = Cmd.Flag("older-than", "Move blobs that no manifest newer than this references, such as 2160h.").Required().Duration()
+	cmdStorageClass = Cmd.Flag("storage-class", "Storage class to move them to: GLACIER, DEEP_ARCHIVE or GLACIER_IR.").Default(s3.StorageClassGlacier).Enum(s3.StorageClassGlacier, s3.StorageClassDeepArchive, s3.StorageClassGlacierIr)
+	cmdMinSize      = Cmd.Flag("min-size", "Leave blobs smaller than this many bytes, which cost more to archive than to keep.").Default("131072").Int64()
+	cmdConcurrency  = Cmd.Flag("concurrency", "Number of blobs to move at once.").Default("8").Int()
+	cmdDryRun       = Cmd.Flag("dry-run", "Report what would be moved without moving anything.").Bool()
+)
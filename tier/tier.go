@@ -0,0 +1,113 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tier
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/retailnext/cassandrabackup/bucket"
+	"github.com/retailnext/cassandrabackup/digest"
+	"github.com/retailnext/cassandrabackup/manifests"
+	"github.com/retailnext/cassandrabackup/unixtime"
+	"go.uber.org/zap"
+)
+
+func Main(ctx context.Context) error {
+	cutoff := time.Now().Add(-*cmdOlderThan)
+	client := bucket.OpenShared()
+	blobs, err := ColdBlobs(ctx, client, cutoff)
+	if err != nil {
+		return err
+	}
+	result, err := bucket.Tier(ctx, client, blobs, *cmdStorageClass, *cmdMinSize, *cmdConcurrency, *cmdDryRun)
+	zap.S().Infow("tier_result", "dry_run", *cmdDryRun, "storage_class", *cmdStorageClass, "cutoff", cutoff.UTC(), "result", result)
+	return err
+}
+
+// ColdBlobs returns the blobs that only manifests at or before cutoff reference, across every
+// cluster since blobs are shared between them.
+func ColdBlobs(ctx context.Context, client bucket.Client, cutoff time.Time) ([]digest.ForRestore, error) {
+	hot := make(map[digest.ForRestore]struct{})
+	cold := make(map[digest.ForRestore]struct{})
+	clusters, err := client.ListClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, cluster := range clusters {
+		hosts, err := client.ListHostNames(ctx, cluster)
+		if err != nil {
+			return nil, err
+		}
+		for _, identity := range hosts {
+			keys, err := client.ListManifests(ctx, identity, 0, 0)
+			if err != nil {
+				return nil, err
+			}
+			hotKeys, coldKeys := Split(keys, unixtime.Seconds(cutoff.Unix()))
+			for _, split := range []struct {
+				keys  manifests.ManifestKeys
+				blobs map[digest.ForRestore]struct{}
+			}{{hotKeys, hot}, {coldKeys, cold}} {
+				if len(split.keys) == 0 {
+					continue
+				}
+				got, err := client.GetManifests(ctx, identity, split.keys)
+				if err != nil {
+					return nil, err
+				}
+				if err := ctx.Err(); err != nil {
+					return nil, err
+				}
+				for _, m := range got {
+					for _, file := range m.DataFiles {
+						split.blobs[file] = struct{}{}
+					}
+					if m.Schema != nil {
+						split.blobs[*m.Schema] = struct{}{}
+					}
+				}
+			}
+		}
+	}
+
+	var blobs []digest.ForRestore
+	for blob := range cold {
+		if _, ok := hot[blob]; !ok {
+			blobs = append(blobs, blob)
+		}
+	}
+	return blobs, nil
+}
+
+// Split divides a host's manifests into the ones whose blobs stay readable and the older ones
+// whose blobs may be archived. The manifests after cutoff stay, along with the snapshot they build
+// on, so that the latest backup of every host can be restored without waiting for an archive.
+func Split(keys manifests.ManifestKeys, cutoff unixtime.Seconds) (hot, cold manifests.ManifestKeys) {
+	sorted := make(manifests.ManifestKeys, len(keys))
+	copy(sorted, keys)
+	sort.Sort(sorted)
+	i := sort.Search(len(sorted), func(i int) bool {
+		return sorted[i].Time > cutoff
+	})
+	old := sorted[:i]
+	kept := old.FromLatestSnapshot()
+	if len(kept) == len(old) {
+		// Without a snapshot, FromLatestSnapshot keeps everything.
+		return sorted, nil
+	}
+	return sorted[len(old)-len(kept):], old[:len(old)-len(kept)]
+}
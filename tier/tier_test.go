@@ -0,0 +1,54 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tier
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/retailnext/cassandrabackup/manifests"
+	"github.com/retailnext/cassandrabackup/unixtime"
+)
+
+func TestSplit(t *testing.T) {
+	keys := manifests.ManifestKeys{
+		{Time: 100, ManifestType: manifests.ManifestTypeSnapshot},
+		{Time: 200, ManifestType: manifests.ManifestTypeIncremental},
+		{Time: 300, ManifestType: manifests.ManifestTypeSnapshot},
+		{Time: 400, ManifestType: manifests.ManifestTypeIncremental},
+		{Time: 500, ManifestType: manifests.ManifestTypeSnapshot},
+	}
+	for _, c := range []struct {
+		keys      manifests.ManifestKeys
+		cutoff    unixtime.Seconds
+		hot, cold manifests.ManifestKeys
+	}{
+		{keys: keys, cutoff: 50, hot: keys},
+		{keys: keys, cutoff: 250, hot: keys},
+		{keys: keys, cutoff: 400, hot: keys[2:], cold: keys[:2]},
+		// The latest snapshot stays readable even when it is older than the cutoff.
+		{keys: keys, cutoff: 1000, hot: keys[4:], cold: keys[:4]},
+		// Without a snapshot nothing can be told apart.
+		{keys: keys[1:2], cutoff: 1000, hot: keys[1:2]},
+	} {
+		hot, cold := Split(c.keys, c.cutoff)
+		if diff := deep.Equal(hot, c.hot); diff != nil {
+			t.Errorf("%d hot: %v", c.cutoff, diff)
+		}
+		if diff := deep.Equal(cold, c.cold); diff != nil {
+			t.Errorf("%d cold: %v", c.cutoff, diff)
+		}
+	}
+}
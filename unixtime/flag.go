@@ -0,0 +1,27 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unixtime
+
+import "github.com/alecthomas/kingpin/v2"
+
+// TimeHelp describes the forms a time flag accepts, for appending to its help.
+const TimeHelp = "(unix seconds, RFC3339, now, or relative like -6h or -2d)"
+
+// Flag parses a kingpin flag as a time in any form ParseTime accepts. It is zero when not given.
+func Flag(s kingpin.Settings) *Seconds {
+	t := new(Seconds)
+	s.SetValue(t)
+	return t
+}
@@ -18,6 +18,7 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"regexp"
 	"strconv"
 	"time"
 
@@ -35,6 +36,10 @@ func (t Seconds) Decimal() string {
 	return fmt.Sprintf("%020d", t)
 }
 
+func (t Seconds) Time() time.Time {
+	return time.Unix(int64(t), 0)
+}
+
 func (t Seconds) String() string {
 	return time.Unix(int64(t), 0).UTC().Format(time.RFC3339)
 }
@@ -70,6 +75,54 @@ func (t *Seconds) ParseStringIgnoreNanoseconds(value string) error {
 	return nil
 }
 
+var relativeTimeExpr = regexp.MustCompile(`^([+-])(?:(\d+)d)?(.*)$`)
+
+// ParseTime parses a time given as unix seconds, RFC3339 (fractional seconds are dropped), "now", or
+// relative to now as a signed duration such as "-6h" or "-2d12h".
+func ParseTime(value string, now time.Time) (Seconds, error) {
+	var t Seconds
+	if value == "now" {
+		return Seconds(now.Unix()), nil
+	}
+	if t.ParseDecimal(value) == nil {
+		return t, nil
+	}
+	if t.ParseStringIgnoreNanoseconds(value) == nil {
+		return t, nil
+	}
+	if match := relativeTimeExpr.FindStringSubmatch(value); match != nil && (match[2] != "" || match[3] != "") {
+		var offset time.Duration
+		if match[2] != "" {
+			days, err := strconv.Atoi(match[2])
+			if err != nil {
+				return 0, fmt.Errorf("invalid time %q: %w", value, err)
+			}
+			offset = time.Duration(days) * 24 * time.Hour
+		}
+		if match[3] != "" {
+			d, err := time.ParseDuration(match[3])
+			if err != nil || d < 0 {
+				return 0, fmt.Errorf("invalid time %q: expected a duration such as -6h or -2d12h", value)
+			}
+			offset += d
+		}
+		if match[1] == "-" {
+			offset = -offset
+		}
+		return Seconds(now.Add(offset).Unix()), nil
+	}
+	return 0, fmt.Errorf("invalid time %q: expected unix seconds, RFC3339, now, or a duration such as -6h", value)
+}
+
+// Set implements kingpin.Value, accepting any time that ParseTime does.
+func (t *Seconds) Set(value string) error {
+	parsed, err := ParseTime(value, time.Now())
+	if err == nil {
+		*t = parsed
+	}
+	return err
+}
+
 func (t Seconds) MarshalEasyJSON(w *jwriter.Writer) {
 	w.String(t.String())
 }
@@ -17,6 +17,7 @@ package unixtime
 import (
 	"fmt"
 	"testing"
+	"time"
 )
 
 type testCase struct {
@@ -106,3 +107,32 @@ func TestSeconds(t *testing.T) {
 		}
 	}
 }
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 500, time.UTC)
+	valid := map[string]Seconds{
+		"now":                       Seconds(now.Unix()),
+		"1790856000":                1790856000,
+		"2026-10-01T12:00:00Z":      Seconds(now.Unix()),
+		"2026-10-01T14:00:00+02:00": Seconds(now.Unix()),
+		"2026-10-01T12:00:00.75Z":   Seconds(now.Unix()),
+		"-6h":                       Seconds(now.Add(-6 * time.Hour).Unix()),
+		"-90m":                      Seconds(now.Add(-90 * time.Minute).Unix()),
+		"-2d":                       Seconds(now.Add(-48 * time.Hour).Unix()),
+		"-1d12h":                    Seconds(now.Add(-36 * time.Hour).Unix()),
+		"+1h":                       Seconds(now.Add(time.Hour).Unix()),
+	}
+	for value, expected := range valid {
+		actual, err := ParseTime(value, now)
+		if err != nil {
+			t.Errorf("%q: %v", value, err)
+		} else if actual != expected {
+			t.Errorf("%q: expected %v got %v", value, expected, actual)
+		}
+	}
+	for _, value := range []string{"", "-", "6h", "-d", "--6h", "-6x", "2026-10-01", "yesterday"} {
+		if actual, err := ParseTime(value, now); err == nil {
+			t.Errorf("%q: expected error got %v", value, actual)
+		}
+	}
+}
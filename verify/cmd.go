@@ -0,0 +1,37 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"os"
+
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/retailnext/cassandrabackup/unixtime"
+)
+
+var (
+	Cmd = kingpin.Command("verify", "Check that the blobs referenced by backup manifests are present and intact")
+
+	cmdCluster     = Cmd.Flag("cluster", "Cluster to verify").Required().String()
+	cmdHostname    = Cmd.Flag("hostname", "Only verify this host").String()
+	cmdNotBefore   = unixtime.Flag(Cmd.Flag("not-before", "Ignore manifests before this time "+unixtime.TimeHelp))
+	cmdNotAfter    = unixtime.Flag(Cmd.Flag("not-after", "Ignore manifests after this time "+unixtime.TimeHelp))
+	cmdAt          = unixtime.Flag(Cmd.Flag("at", "Only verify the manifests a restore at this time would use "+unixtime.TimeHelp))
+	cmdDeep        = Cmd.Flag("deep", "Download every blob and check its digest, instead of only checking that it exists with the length it was uploaded with").Bool()
+	cmdConcurrency = Cmd.Flag("concurrency", "Number of blobs to check at once").Default("8").Int()
+	cmdTempDir     = Cmd.Flag("temp-dir", "Directory to download blobs into for --deep").Default(os.TempDir()).ExistingDir()
+	cmdJSON        = Cmd.Flag("json", "Print the report as JSON on stdout").Bool()
+	cmdTextfile    = Cmd.Flag("metrics-textfile", "Write the verify metrics to this file on exit, for node_exporter's textfile collector").String()
+)
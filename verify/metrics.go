@@ -0,0 +1,82 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	blobCounters = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "cassandrabackup",
+		Subsystem: "verify",
+		Name:      "blobs_total",
+		Help:      "Number of blobs checked, by result.",
+	}, []string{"deep", "result"})
+	bytesCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "cassandrabackup",
+		Subsystem: "verify",
+		Name:      "bytes_total",
+		Help:      "Total size of the blobs checked.",
+	}, []string{"deep"})
+	lastOkGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "cassandrabackup",
+		Subsystem: "verify",
+		Name:      "last_ok",
+		Help:      "1 if the last verification found no problems.",
+	})
+	lastSuccessGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "cassandrabackup",
+		Subsystem: "verify",
+		Name:      "last_success_seconds",
+		Help:      "Time the last verification that found no problems completed.",
+	})
+
+	// textfileRegistry holds only the verify metrics, so that the file written for node_exporter's
+	// textfile collector doesn't repeat the process metrics node_exporter exports itself.
+	textfileRegistry = prometheus.NewRegistry()
+
+	registerOnce sync.Once
+)
+
+func registerMetrics() {
+	registerOnce.Do(func() {
+		for _, registerer := range []prometheus.Registerer{prometheus.DefaultRegisterer, textfileRegistry} {
+			registerer.MustRegister(blobCounters)
+			registerer.MustRegister(bytesCounter)
+			registerer.MustRegister(lastOkGauge)
+			registerer.MustRegister(lastSuccessGauge)
+		}
+	})
+}
+
+// writeMetricsTextfile writes the verify metrics to path, since verify exits before anything could
+// scrape them.
+func writeMetricsTextfile(path string) error {
+	return prometheus.WriteToTextfile(path, textfileRegistry)
+}
+
+func recordResult(result blobResult, deep bool) {
+	mode := strconv.FormatBool(deep)
+	label := string(result.problem)
+	if label == "" {
+		label = "ok"
+	}
+	blobCounters.WithLabelValues(mode, label).Inc()
+	bytesCounter.WithLabelValues(mode).Add(float64(result.size))
+}
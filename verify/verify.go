@@ -0,0 +1,379 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/retailnext/cassandrabackup/bucket"
+	"github.com/retailnext/cassandrabackup/compression"
+	"github.com/retailnext/cassandrabackup/digest"
+	"github.com/retailnext/cassandrabackup/envelope"
+	"github.com/retailnext/cassandrabackup/manifests"
+	"github.com/retailnext/cassandrabackup/restore/plan"
+	"github.com/retailnext/cassandrabackup/unixtime"
+	"go.uber.org/zap"
+)
+
+type Problem string
+
+const (
+	ProblemMissing Problem = "missing"
+	ProblemCorrupt Problem = "corrupt"
+	ProblemError   Problem = "error"
+)
+
+// schemaProblemName is the name a problem with a manifest's schema blob is reported under. It can't
+// be confused with a data file, whose names all have a directory.
+const schemaProblemName = "schema"
+
+type FileProblem struct {
+	Name    string            `json:"name"`
+	Blob    digest.ForRestore `json:"blob"`
+	Problem Problem           `json:"problem"`
+	Error   string            `json:"error,omitempty"`
+}
+
+type ManifestReport struct {
+	Cluster  string        `json:"cluster"`
+	Hostname string        `json:"hostname"`
+	Manifest string        `json:"manifest"`
+	Time     time.Time     `json:"time"`
+	Files    int           `json:"files"`
+	Problems []FileProblem `json:"problems"`
+}
+
+type Report struct {
+	Deep      bool             `json:"deep"`
+	Manifests []ManifestReport `json:"manifests"`
+	Blobs     int              `json:"blobs"`
+	Bytes     int64            `json:"bytes"`
+	Missing   int              `json:"missing_blobs"`
+	Corrupt   int              `json:"corrupt_blobs"`
+	Errors    int              `json:"error_blobs"`
+}
+
+func (r Report) failed() int {
+	return r.Missing + r.Corrupt + r.Errors
+}
+
+// Options selects what Verify checks and how.
+type Options struct {
+	NotBefore unixtime.Seconds
+	NotAfter  unixtime.Seconds
+	// At, when set, selects only the manifests that a restore at that time would use.
+	At          unixtime.Seconds
+	Deep        bool
+	Concurrency int
+	TempDir     string
+}
+
+func Main(ctx context.Context) error {
+	registerMetrics()
+	err := run(ctx)
+	if *cmdTextfile != "" {
+		if writeErr := writeMetricsTextfile(*cmdTextfile); writeErr != nil {
+			zap.S().Errorw("verify_metrics_textfile_error", "path", *cmdTextfile, "err", writeErr)
+			if err == nil {
+				err = writeErr
+			}
+		}
+	}
+	return err
+}
+
+func run(ctx context.Context) error {
+	client := bucket.OpenShared()
+	identities, err := selectHosts(ctx, client, *cmdCluster, *cmdHostname)
+	if err != nil {
+		return err
+	}
+	report, err := Verify(ctx, client, identities, Options{
+		NotBefore:   *cmdNotBefore,
+		NotAfter:    *cmdNotAfter,
+		At:          *cmdAt,
+		Deep:        *cmdDeep,
+		Concurrency: *cmdConcurrency,
+		TempDir:     *cmdTempDir,
+	})
+	if err != nil {
+		return err
+	}
+
+	if *cmdJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		lgr := zap.S()
+		for _, m := range report.Manifests {
+			manifestLgr := lgr.With("cluster", m.Cluster, "hostname", m.Hostname, "manifest", m.Manifest)
+			for _, problem := range m.Problems {
+				manifestLgr.Errorw("verify_file_problem", "name", problem.Name, "blob", problem.Blob, "problem", problem.Problem, "err", problem.Error)
+			}
+			manifestLgr.Infow("verify_manifest", "files", m.Files, "problems", len(m.Problems))
+		}
+		lgr.Infow("verify_result", "deep", report.Deep, "manifests", len(report.Manifests), "blobs", report.Blobs,
+			"bytes", report.Bytes, "missing", report.Missing, "corrupt", report.Corrupt, "errors", report.Errors)
+	}
+
+	lastOkGauge.Set(0)
+	if failed := report.failed(); failed > 0 {
+		return fmt.Errorf("%d of %d blobs failed verification", failed, report.Blobs)
+	}
+	if len(report.Manifests) == 0 {
+		return errors.New("no manifests found")
+	}
+	lastOkGauge.Set(1)
+	lastSuccessGauge.SetToCurrentTime()
+	return nil
+}
+
+func selectHosts(ctx context.Context, client bucket.Client, cluster, hostname string) ([]manifests.NodeIdentity, error) {
+	if hostname != "" {
+		return []manifests.NodeIdentity{{Cluster: cluster, Hostname: hostname}}, nil
+	}
+	return client.ListHostNames(ctx, cluster)
+}
+
+type blobResult struct {
+	problem Problem
+	err     error
+	size    int64
+	// blobSizes are the lengths manifests record the blob as uploaded with.
+	blobSizes []int64
+}
+
+// Verify checks every blob referenced by the hosts' manifests in the selected time range. Each blob is
+// checked once however many manifests reference it, and its result is reported against each of them.
+// Without Deep, each blob is checked to exist with the length manifests record it as uploaded with,
+// sealed or not, when they record one.
+func Verify(ctx context.Context, client bucket.Client, identities []manifests.NodeIdentity, options Options) (Report, error) {
+	report := Report{Deep: options.Deep}
+	type hostManifests struct {
+		identity  manifests.NodeIdentity
+		manifests []manifests.Manifest
+	}
+	notAfter, err := plan.NotAfter(options.At, options.NotAfter)
+	if err != nil {
+		return report, err
+	}
+	var hosts []hostManifests
+	blobs := make(map[digest.ForRestore]*blobResult)
+	blobSizes := make(map[digest.ForRestore][]int64)
+	for _, identity := range identities {
+		keys, err := client.ListManifests(ctx, identity, options.NotBefore, notAfter)
+		if err != nil {
+			return report, err
+		}
+		if options.At != 0 {
+			keys = keys.FromLatestSnapshot()
+		}
+		if len(keys) == 0 {
+			zap.S().Warnw("verify_no_manifests", "identity", identity)
+			continue
+		}
+		got, err := client.GetManifests(ctx, identity, keys)
+		if err != nil {
+			return report, err
+		}
+		for _, m := range got {
+			for name, file := range m.DataFiles {
+				blobs[file] = nil
+				if info, ok := m.FileInfo[name]; ok && info.BlobSize > 0 && !containsSize(blobSizes[file], info.BlobSize) {
+					blobSizes[file] = append(blobSizes[file], info.BlobSize)
+				}
+			}
+			if m.Schema != nil {
+				blobs[*m.Schema] = nil
+			}
+		}
+		hosts = append(hosts, hostManifests{identity: identity, manifests: got})
+	}
+
+	for digests := range blobs {
+		blobs[digests] = &blobResult{blobSizes: blobSizes[digests]}
+	}
+	if err := checkBlobs(ctx, client, blobs, options); err != nil {
+		return report, err
+	}
+	for _, result := range blobs {
+		report.Blobs++
+		report.Bytes += result.size
+		switch result.problem {
+		case ProblemMissing:
+			report.Missing++
+		case ProblemCorrupt:
+			report.Corrupt++
+		case ProblemError:
+			report.Errors++
+		}
+	}
+
+	for _, host := range hosts {
+		for _, m := range host.manifests {
+			manifestReport := ManifestReport{
+				Cluster:  host.identity.Cluster,
+				Hostname: host.identity.Hostname,
+				Manifest: m.Key().FileName(),
+				Time:     m.Time.Time().UTC(),
+				Files:    len(m.DataFiles),
+				Problems: []FileProblem{},
+			}
+			for name, file := range m.DataFiles {
+				result := blobs[file]
+				if result.problem == "" {
+					continue
+				}
+				problem := FileProblem{Name: name, Blob: file, Problem: result.problem}
+				if result.err != nil {
+					problem.Error = result.err.Error()
+				}
+				manifestReport.Problems = append(manifestReport.Problems, problem)
+			}
+			if m.Schema != nil {
+				if result := blobs[*m.Schema]; result.problem != "" {
+					problem := FileProblem{Name: schemaProblemName, Blob: *m.Schema, Problem: result.problem}
+					if result.err != nil {
+						problem.Error = result.err.Error()
+					}
+					manifestReport.Problems = append(manifestReport.Problems, problem)
+				}
+			}
+			sort.Slice(manifestReport.Problems, func(i, j int) bool {
+				return manifestReport.Problems[i].Name < manifestReport.Problems[j].Name
+			})
+			report.Manifests = append(report.Manifests, manifestReport)
+		}
+	}
+	return report, nil
+}
+
+func checkBlobs(ctx context.Context, client bucket.Client, blobs map[digest.ForRestore]*blobResult, options Options) error {
+	concurrency := options.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	limiter := make(chan struct{}, concurrency)
+	doneCh := ctx.Done()
+	pending := make([]digest.ForRestore, 0, len(blobs))
+	for digests := range blobs {
+		pending = append(pending, digests)
+	}
+schedule:
+	for _, digests := range pending {
+		select {
+		case <-doneCh:
+			break schedule
+		case limiter <- struct{}{}:
+		}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-limiter
+				wg.Done()
+			}()
+			lock.Lock()
+			blobSizes := blobs[digests].blobSizes
+			lock.Unlock()
+			result := checkBlob(ctx, client, digests, blobSizes, options)
+			recordResult(result, options.Deep)
+			lock.Lock()
+			blobs[digests] = &result
+			lock.Unlock()
+		}()
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+func checkBlob(ctx context.Context, client bucket.Client, digests digest.ForRestore, blobSizes []int64, options Options) blobResult {
+	if !options.Deep {
+		size, err := client.StatBlob(ctx, digests)
+		result := classify(size, err)
+		if result.problem == "" && !storedSizeMatches(size, blobSizes) {
+			result.problem = ProblemCorrupt
+			result.err = fmt.Errorf("stored length %d does not match the uploaded length of %v", size, blobSizes)
+		}
+		return result
+	}
+
+	tmp, err := os.CreateTemp(options.TempDir, "cassandrabackup-verify-*")
+	if err != nil {
+		return blobResult{problem: ProblemError, err: err}
+	}
+	defer func() {
+		if closeErr := tmp.Close(); closeErr != nil {
+			zap.S().Errorw("verify_temp_close_error", "name", tmp.Name(), "err", closeErr)
+		}
+		if removeErr := os.Remove(tmp.Name()); removeErr != nil {
+			zap.S().Errorw("verify_temp_remove_error", "name", tmp.Name(), "err", removeErr)
+		}
+	}()
+	err = client.DownloadBlob(ctx, digests, tmp)
+	var size int64
+	if info, statErr := tmp.Stat(); statErr == nil {
+		size = info.Size()
+	}
+	return classify(size, err)
+}
+
+// storedSizeMatches reports whether a blob stored with size bytes was uploaded with one of blobSizes,
+// either as is or sealed. Blobs whose manifests don't record a length match any size.
+func storedSizeMatches(size int64, blobSizes []int64) bool {
+	if len(blobSizes) == 0 {
+		return true
+	}
+	for _, blobSize := range blobSizes {
+		if size == blobSize || size == envelope.SealedSize(blobSize) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsSize(sizes []int64, size int64) bool {
+	for _, s := range sizes {
+		if s == size {
+			return true
+		}
+	}
+	return false
+}
+
+func classify(size int64, err error) blobResult {
+	var mismatch digest.MismatchError
+	switch {
+	case err == nil:
+		return blobResult{size: size}
+	case bucket.IsNoSuchKey(err):
+		return blobResult{problem: ProblemMissing}
+	case errors.As(err, &mismatch), errors.Is(err, envelope.ErrMalformed), errors.Is(err, compression.ErrMalformed):
+		return blobResult{problem: ProblemCorrupt, err: err, size: size}
+	default:
+		return blobResult{problem: ProblemError, err: err}
+	}
+}
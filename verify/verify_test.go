@@ -0,0 +1,192 @@
+// Copyright 2026 RetailNext, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/retailnext/cassandrabackup/bucket"
+	"github.com/retailnext/cassandrabackup/digest"
+	"github.com/retailnext/cassandrabackup/envelope"
+	"github.com/retailnext/cassandrabackup/manifests"
+	"github.com/retailnext/cassandrabackup/paranoid"
+	"github.com/retailnext/cassandrabackup/unixtime"
+)
+
+// fakeClient serves manifests and blobs from memory.
+type fakeClient struct {
+	bucket.Client
+	manifests []manifests.Manifest
+	blobs     map[digest.ForRestore][]byte
+}
+
+func (c *fakeClient) ListManifests(ctx context.Context, identity manifests.NodeIdentity, startAfter, notAfter unixtime.Seconds) (manifests.ManifestKeys, error) {
+	var keys manifests.ManifestKeys
+	for _, m := range c.manifests {
+		keys = append(keys, m.Key())
+	}
+	return keys, nil
+}
+
+func (c *fakeClient) GetManifests(ctx context.Context, identity manifests.NodeIdentity, keys manifests.ManifestKeys) ([]manifests.Manifest, error) {
+	return c.manifests, nil
+}
+
+func (c *fakeClient) StatBlob(ctx context.Context, digests digest.ForRestore) (int64, error) {
+	data, ok := c.blobs[digests]
+	if !ok {
+		return 0, fs.ErrNotExist
+	}
+	return int64(len(data)), nil
+}
+
+func (c *fakeClient) DownloadBlob(ctx context.Context, digests digest.ForRestore, file *os.File) error {
+	data, ok := c.blobs[digests]
+	if !ok {
+		return fs.ErrNotExist
+	}
+	if _, err := file.Write(data); err != nil {
+		return err
+	}
+	return digests.Verify(ctx, file)
+}
+
+func digestOf(t *testing.T, data string) digest.ForRestore {
+	path := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	file, err := paranoid.NewFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digests, err := digest.GetUncached(context.Background(), file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return digests.ForRestore()
+}
+
+func TestVerify(t *testing.T) {
+	ctx := context.Background()
+	good, missing, corrupt := digestOf(t, "good"), digestOf(t, "missing"), digestOf(t, "corrupt")
+	client := &fakeClient{
+		manifests: []manifests.Manifest{
+			{Time: 100, ManifestType: manifests.ManifestTypeSnapshot, DataFiles: map[string]digest.ForRestore{
+				"ks/t/a-Data.db": good,
+				"ks/t/b-Data.db": missing,
+			}},
+			{Time: 200, ManifestType: manifests.ManifestTypeIncremental, DataFiles: map[string]digest.ForRestore{
+				"ks/t/a-Data.db": good,
+				"ks/t/c-Data.db": corrupt,
+			}},
+		},
+		blobs: map[digest.ForRestore][]byte{
+			good:    []byte("good"),
+			corrupt: []byte("c0rrupt"),
+		},
+	}
+	identities := []manifests.NodeIdentity{{Cluster: "cluster", Hostname: "host"}}
+
+	report, err := Verify(ctx, client, identities, Options{Concurrency: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Blobs != 3 || report.Missing != 1 || report.Corrupt != 0 || report.Errors != 0 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if len(report.Manifests) != 2 || len(report.Manifests[0].Problems) != 1 || len(report.Manifests[1].Problems) != 0 {
+		t.Fatalf("unexpected manifest reports %+v", report.Manifests)
+	}
+	if problem := report.Manifests[0].Problems[0]; problem.Name != "ks/t/b-Data.db" || problem.Problem != ProblemMissing {
+		t.Fatalf("unexpected problem %+v", problem)
+	}
+
+	report, err = Verify(ctx, client, identities, Options{Deep: true, Concurrency: 2, TempDir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Blobs != 3 || report.Missing != 1 || report.Corrupt != 1 || report.Errors != 0 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if problems := report.Manifests[1].Problems; len(problems) != 1 || problems[0].Blob != corrupt || problems[0].Problem != ProblemCorrupt {
+		t.Fatalf("unexpected problems %+v", problems)
+	}
+}
+
+func TestVerifyLengths(t *testing.T) {
+	ctx := context.Background()
+	plain, sealed, truncated, unknown := digestOf(t, "plain"), digestOf(t, "sealed"), digestOf(t, "truncated"), digestOf(t, "unknown")
+	client := &fakeClient{
+		manifests: []manifests.Manifest{
+			{Time: 100, ManifestType: manifests.ManifestTypeSnapshot,
+				DataFiles: map[string]digest.ForRestore{
+					"ks/t/a-Data.db": plain,
+					"ks/t/b-Data.db": sealed,
+					"ks/t/c-Data.db": truncated,
+					"ks/t/d-Data.db": unknown,
+				},
+				FileInfo: map[string]manifests.FileInfo{
+					"ks/t/a-Data.db": {Size: 5, BlobSize: 5},
+					"ks/t/b-Data.db": {Size: 6, BlobSize: 6},
+					"ks/t/c-Data.db": {Size: 9, BlobSize: 9},
+					"ks/t/d-Data.db": {Size: 7},
+				},
+			},
+		},
+		blobs: map[digest.ForRestore][]byte{
+			plain:     []byte("plain"),
+			sealed:    make([]byte, envelope.SealedSize(6)),
+			truncated: []byte("trunc"),
+			unknown:   []byte("unk"),
+		},
+	}
+	identities := []manifests.NodeIdentity{{Cluster: "cluster", Hostname: "host"}}
+
+	report, err := Verify(ctx, client, identities, Options{Concurrency: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Blobs != 4 || report.Missing != 0 || report.Corrupt != 1 || report.Errors != 0 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if problems := report.Manifests[0].Problems; len(problems) != 1 || problems[0].Blob != truncated || problems[0].Problem != ProblemCorrupt {
+		t.Fatalf("unexpected problems %+v", problems)
+	}
+}
+
+func TestWriteMetricsTextfile(t *testing.T) {
+	registerMetrics()
+	lastOkGauge.Set(1)
+	path := filepath.Join(t.TempDir(), "verify.prom")
+	if err := writeMetricsTextfile(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "cassandrabackup_verify_last_ok 1\n") {
+		t.Errorf("expected the last_ok gauge in %s", data)
+	}
+	if strings.Contains(string(data), "go_goroutines") {
+		t.Errorf("expected only the verify metrics in %s", data)
+	}
+}